@@ -2,12 +2,21 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"io/fs"
 	"log"
 	"os"
+	"time"
 
 	"backend/internal/api"
+	"backend/internal/cloud"
+	"backend/internal/cve"
 	"backend/internal/database"
+	"backend/internal/exploit"
+	"backend/internal/geoip"
 	"backend/internal/models"
+	"backend/internal/scanner"
 	"backend/internal/services"
 
 	"github.com/joho/godotenv"
@@ -58,6 +67,60 @@ func main() {
 	aplicationService := services.NewApplicationService(db)
 	dnsRecordService := services.NewDNSRecordService(db)
 	certificateService := services.NewCertificateService(db)
+	nucleiTemplateService := services.NewNucleiTemplateService(db)
+
+	// CVE feeds are read from local files so matching works without network access
+	vulnerabilityService := services.NewVulnerabilityService(db, cve.FeedDir())
+	if _, err := vulnerabilityService.LoadFeeds(false); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("No CVE feeds found in %s, vulnerable version matching is disabled", cve.FeedDir())
+		} else {
+			log.Printf("Failed to load CVE feeds: %v", err)
+		}
+	}
+
+	feedCheckInterval := 10 * time.Minute
+	if value := os.Getenv("CVE_FEED_CHECK_INTERVAL"); value != "" {
+		if interval, err := time.ParseDuration(value); err == nil && interval > 0 {
+			feedCheckInterval = interval
+		}
+	}
+	go vulnerabilityService.WatchFeeds(context.Background(), feedCheckInterval)
+
+	// Findings with CVEs are scored with EPSS and CISA KEV data from local files
+	exploitService := services.NewExploitService(db, exploit.DataDir())
+	if _, err := exploitService.LoadData(false); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("No EPSS or KEV data found in %s, exploit scoring is disabled", exploit.DataDir())
+		} else {
+			log.Printf("Failed to load EPSS and KEV data: %v", err)
+		}
+	}
+	findingService.AddEnricher(exploitService)
+	vulnerabilityService.AddEnricher(exploitService)
+	go exploitService.WatchData(context.Background(), feedCheckInterval)
+
+	// IP targets are enriched with ASN and GeoIP data from local databases as they are created
+	geoipService := services.NewGeoIPService(db, geoip.DatabaseDir())
+	if _, err := geoipService.LoadDatabases(false); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("No GeoIP databases found in %s, target enrichment is disabled", geoip.DatabaseDir())
+		} else {
+			log.Printf("Failed to load GeoIP databases: %v", err)
+		}
+	}
+	targetService.AddEnricher(geoipService)
+
+	// IP targets are attributed to cloud and CDN providers from their published ranges
+	attributionService := services.NewAttributionService(db, cloud.RangesDir())
+	if _, err := attributionService.LoadRanges(false); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			log.Printf("No cloud range files found in %s, provider attribution is disabled", cloud.RangesDir())
+		} else {
+			log.Printf("Failed to load cloud ranges: %v", err)
+		}
+	}
+	targetService.AddEnricher(attributionService)
 
 	// Setup findings consumer
 	err = queueService.ConsumeFindings(func(finding models.Finding) error {
@@ -70,14 +133,30 @@ func main() {
 
 	// Setup status updates consumer
 	err = queueService.ConsumeStatusUpdates(func(update services.StatusUpdate) error {
-		return scanService.UpdateStatus(update.ScanID, update.Status)
+		if err := scanService.UpdateStatus(update.ScanID, update.Status); err != nil {
+			return err
+		}
+
+		// Match newly discovered versions against the CVE feeds once a scan completes
+		if update.Status == models.StatusCompleted {
+			if scan, err := scanService.GetByID(update.ScanID); err == nil {
+				go func() {
+					if _, err := vulnerabilityService.RecomputeProject(scan.ProjectID); err != nil && !errors.Is(err, services.ErrNoFeeds) {
+						log.Printf("Failed to match vulnerable versions for project %s: %v", scan.ProjectID, err)
+					}
+				}()
+			}
+		}
+		return nil
 	})
 	if err != nil {
 		log.Fatalf("Failed to set up status updates consumer: %v", err)
 	}
 
+	// Targets discovered by workers are only stored here, so they all get enriched
 	err = queueService.ConsumeTargets(func(target models.Target) error {
-		return targetService.Create(&target)
+		_, err := targetService.UpsertTarget(&target)
+		return err
 	})
 	if err != nil {
 		log.Fatalf("Failed to set up targets consumer: %v", err)
@@ -99,8 +178,13 @@ func main() {
 		log.Fatalf("Failed to set up services consumer: %v", err)
 	}
 
+	// Scanner catalog used to describe scanners and validate scan configs
+	scannerRegistry := scanner.NewRegistry()
+	scannerRegistry.RegisterBuiltins()
+	scannerRegistry.RegisterPlugins(context.Background(), scanner.PluginDir())
+
 	// Setup router
-	router := api.SetupRouter(projectService, targetService, scanService, findingService, queueService, authService, serviceService, relationService, aplicationService, dnsRecordService, certificateService)
+	router := api.SetupRouter(projectService, targetService, scanService, findingService, queueService, authService, serviceService, relationService, aplicationService, dnsRecordService, certificateService, vulnerabilityService, exploitService, geoipService, attributionService, nucleiTemplateService, scannerRegistry)
 
 	// Start server
 	port := os.Getenv("PORT")
@@ -2,6 +2,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
@@ -73,27 +74,12 @@ func main() {
 	applicationService := services.NewApplicationService(db)
 	dnsRecordService := services.NewDNSRecordService(db)
 	certificateService := services.NewCertificateService(db)
+	templateService := services.NewNucleiTemplateService(db)
 
-	// Initialize scanner registry
+	// Initialize scanner registry with the built-in scanners and any plugins
 	scannerRegistry := scanner.NewRegistry()
-
-	nmapScanner := scanner.NewNmapScanner()
-	scannerRegistry.Register("nmap", nmapScanner)
-
-	dnsScanner := scanner.NewDNSScanner()
-	scannerRegistry.Register("dns", dnsScanner)
-
-	subdomainScanner := scanner.NewSubdomainScanner()
-	scannerRegistry.Register("subdomain", subdomainScanner)
-
-	nucleiScanner := scanner.NewNucleiScanner()
-	scannerRegistry.Register("nuclei", nucleiScanner)
-
-	httpxScanner := scanner.NewHTTPXScanner()
-	scannerRegistry.Register("httpx", httpxScanner)
-
-	testSSLScanner := scanner.NewTestSSLScanner()
-	scannerRegistry.Register("testSSL", testSSLScanner)
+	scannerRegistry.RegisterBuiltins()
+	scannerRegistry.RegisterPlugins(context.Background(), scanner.PluginDir())
 
 	// Create and start the worker
 	scanWorker := worker.NewWorker(
@@ -104,6 +90,7 @@ func main() {
 		applicationService,
 		dnsRecordService,
 		certificateService,
+		templateService,
 		*workerID,
 	)
 
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttributionHandler struct {
+	attributionService *services.AttributionService
+}
+
+func NewAttributionHandler(attributionService *services.AttributionService) *AttributionHandler {
+	return &AttributionHandler{
+		attributionService: attributionService,
+	}
+}
+
+// GetRangeStatus returns the state of the loaded cloud and CDN range files
+// @Summary Get cloud range status
+// @Description Get the cloud and CDN range files loaded for target attribution
+// @Tags cloud
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.CloudRangeStatus
+// @Router /api/v1/cloud/ranges [get]
+func (h *AttributionHandler) GetRangeStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.attributionService.Status())
+}
+
+// RefreshRanges reloads the cloud and CDN range files
+// @Summary Refresh cloud ranges
+// @Description Reload the cloud and CDN range files from the range directory
+// @Tags cloud
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.CloudRangeStatus
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/cloud/ranges/refresh [post]
+func (h *AttributionHandler) RefreshRanges(c *gin.Context) {
+	reloadData(c, h.attributionService.LoadRanges, h.attributionService.Status, "cloud ranges")
+}
+
+// AttributeProjectTargets attributes every IP target of a project to a cloud or CDN provider
+// @Summary Attribute project targets
+// @Description Write provider, service, region and CDN flag of every IP target of a project into its metadata
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} services.AttributionSummary
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/projects/{id}/targets/attribute [post]
+func (h *AttributionHandler) AttributeProjectTargets(c *gin.Context) {
+	enrichProjectTargets(c, h.attributionService.LoadRanges, "cloud ranges", h.attributionService.AttributeProject, "attribute targets")
+}
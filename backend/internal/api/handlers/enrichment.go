@@ -0,0 +1,43 @@
+package handlers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// reloadData reloads the files of a data directory and responds with its status.
+// what names the files in the error message, e.g. "GeoIP databases".
+func reloadData[S any](c *gin.Context, load func(force bool) (bool, error), status func() S, what string) {
+	if _, err := load(true); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load " + what + ": " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, status())
+}
+
+// enrichProjectTargets picks up data files that were added or updated since they were last loaded, then
+// runs an enrichment on the targets of the project in the path and responds with its summary
+func enrichProjectTargets[S any](c *gin.Context, load func(force bool) (bool, error), what string, enrich func(projectID uuid.UUID) (*S, error), action string) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+		return
+	}
+
+	if _, err := load(false); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load " + what + ": " + err.Error()})
+		return
+	}
+
+	summary, err := enrich(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to " + action})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,73 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ExploitHandler struct {
+	exploitService *services.ExploitService
+}
+
+func NewExploitHandler(exploitService *services.ExploitService) *ExploitHandler {
+	return &ExploitHandler{
+		exploitService: exploitService,
+	}
+}
+
+// GetDataStatus returns the state of the loaded EPSS and KEV files
+// @Summary Get exploit data status
+// @Description Get the EPSS score and CISA KEV files loaded for finding prioritization
+// @Tags vulnerabilities
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.ExploitDataStatus
+// @Router /api/v1/vulnerabilities/exploits [get]
+func (h *ExploitHandler) GetDataStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.exploitService.Status())
+}
+
+// RefreshData reloads the EPSS and KEV files and rescores the findings with CVEs
+// @Summary Refresh exploit data
+// @Description Reload the EPSS score and CISA KEV files from the data directory and rescore findings with CVEs
+// @Tags vulnerabilities
+// @Accept json
+// @Produce json
+// @Param project_id query string false "Only rescore findings for this project"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/vulnerabilities/exploits/refresh [post]
+func (h *ExploitHandler) RefreshData(c *gin.Context) {
+	// Get project ID from query param if exists
+	projectIDStr := c.Query("project_id")
+	var projectID *uuid.UUID
+
+	if projectIDStr != "" {
+		id, err := uuid.Parse(projectIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+			return
+		}
+		projectID = &id
+	}
+
+	if _, err := h.exploitService.LoadData(true); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load EPSS and KEV data: " + err.Error()})
+		return
+	}
+
+	summary, err := h.exploitService.Rescore(projectID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to rescore findings"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    h.exploitService.Status(),
+		"summary": summary,
+	})
+}
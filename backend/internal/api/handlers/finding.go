@@ -2,14 +2,21 @@ package handlers
 
 import (
 	"backend/internal/models"
+	"backend/internal/scanner"
 	"backend/internal/services"
+	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// cweQueryPattern accepts a CWE id written as CWE-<number> or as a plain number
+var cweQueryPattern = regexp.MustCompile(`(?i)^(?:CWE-)?\d+$`)
+
 type FindingHandler struct {
 	findingService *services.FindingService
 }
@@ -30,15 +37,23 @@ func NewFindingHandler(findingService *services.FindingService) *FindingHandler
 // @Param type query string false "Filter by finding type"
 // @Param fixed query string false "Filter by fixed status"
 // @Param project_id query string false "Filter by project ID"
+// @Param cve query string false "Filter by CVE ID"
+// @Param cwe query string false "Filter by CWE ID"
+// @Param min_cvss query number false "Minimum CVSS base score"
+// @Param min_epss query number false "Minimum EPSS score"
+// @Param kev query bool false "Filter by presence in the CISA KEV catalog"
+// @Param sort query string false "Sort by cvss, epss or discovered_at, highest or newest first"
 // @Success 200 {array} models.Finding
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/findings [get]
 func (h *FindingHandler) GetFindings(c *gin.Context) {
 	// Support filtering
-	severity := c.Query("severity")
-	findingType := c.Query("type")
-	fixed := c.Query("fixed")
+	filter, err := parseFindingFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	// Get project ID from query param if exists
 	projectIDStr := c.Query("project_id")
@@ -53,7 +68,7 @@ func (h *FindingHandler) GetFindings(c *gin.Context) {
 		projectID = &id
 	}
 
-	findings, err := h.findingService.GetFiltered(projectID, severity, findingType, fixed)
+	findings, err := h.findingService.GetFiltered(projectID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve findings"})
 		return
@@ -112,6 +127,11 @@ func (h *FindingHandler) CreateFinding(c *gin.Context) {
 		Details     models.JSONB `json:"details"`
 		Verified    bool         `json:"verified"`
 		Manual      *bool        `json:"manual"`
+		CVEIDs      []string     `json:"cve_ids"`
+		CWEIDs      []string     `json:"cwe_ids"`
+		CVSSScore   *float64     `json:"cvss_score"`
+		CVSSVector  string       `json:"cvss_vector"`
+		References  []string     `json:"references"`
 	}
 
 	verified := input.Verified
@@ -168,7 +188,17 @@ func (h *FindingHandler) CreateFinding(c *gin.Context) {
 		Verified:    verified,
 		Fixed:       false, // Default to not fixed
 		Manual:      manual,
+		CVEIDs:      input.CVEIDs,
+		CWEIDs:      input.CWEIDs,
+		CVSSScore:   input.CVSSScore,
+		CVSSVector:  input.CVSSVector,
+		References:  input.References,
+	}
+	if !validCVSSScore(finding.CVSSScore) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CVSS score, must be between 0 and 10"})
+		return
 	}
+	scanner.Classify(finding)
 
 	err = h.findingService.Create(finding)
 	if err != nil {
@@ -208,6 +238,11 @@ func (h *FindingHandler) UpdateFinding(c *gin.Context) {
 		Details     models.JSONB `json:"details"`
 		Verified    *bool        `json:"verified"`
 		Fixed       *bool        `json:"fixed"`
+		CVEIDs      []string     `json:"cve_ids"`
+		CWEIDs      []string     `json:"cwe_ids"`
+		CVSSScore   *float64     `json:"cvss_score"`
+		CVSSVector  *string      `json:"cvss_vector"`
+		References  []string     `json:"references"`
 	}
 
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -260,6 +295,32 @@ func (h *FindingHandler) UpdateFinding(c *gin.Context) {
 		finding.Fixed = *input.Fixed
 	}
 
+	if input.CVEIDs != nil {
+		finding.CVEIDs = input.CVEIDs
+	}
+
+	if input.CWEIDs != nil {
+		finding.CWEIDs = input.CWEIDs
+	}
+
+	if input.CVSSScore != nil {
+		if !validCVSSScore(input.CVSSScore) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid CVSS score, must be between 0 and 10"})
+			return
+		}
+		finding.CVSSScore = input.CVSSScore
+	}
+
+	if input.CVSSVector != nil {
+		finding.CVSSVector = *input.CVSSVector
+		finding.CVSSVersion = ""
+	}
+
+	if input.References != nil {
+		finding.References = input.References
+	}
+	scanner.Classify(finding)
+
 	err = h.findingService.Update(finding)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update finding"})
@@ -483,3 +544,56 @@ func (h *FindingHandler) GetLatestFindings(c *gin.Context) {
 
 	c.JSON(http.StatusOK, findings)
 }
+
+// validCVSSScore reports whether a CVSS base score, if given, is in the range of the CVSS scale
+func validCVSSScore(score *float64) bool {
+	return score == nil || (*score >= 0 && *score <= 10)
+}
+
+// parseFindingFilter reads the finding filters from the query parameters
+func parseFindingFilter(c *gin.Context) (services.FindingFilter, error) {
+	filter := services.FindingFilter{
+		Severity:    c.Query("severity"),
+		FindingType: c.Query("type"),
+		Fixed:       c.Query("fixed"),
+		CVE:         c.Query("cve"),
+		CWE:         c.Query("cwe"),
+		Sort:        c.Query("sort"),
+	}
+
+	if filter.CWE != "" && !cweQueryPattern.MatchString(filter.CWE) {
+		return filter, errors.New("Invalid cwe value, must be a CWE ID such as CWE-79")
+	}
+
+	switch filter.Sort {
+	case "", "cvss", "epss", "discovered_at":
+	default:
+		return filter, errors.New("Invalid sort value, must be cvss, epss or discovered_at")
+	}
+
+	if minCVSS := c.Query("min_cvss"); minCVSS != "" {
+		value, err := strconv.ParseFloat(minCVSS, 64)
+		if err != nil || value < 0 || value > 10 {
+			return filter, errors.New("Invalid min_cvss value, must be a number between 0 and 10")
+		}
+		filter.MinCVSS = &value
+	}
+
+	if minEPSS := c.Query("min_epss"); minEPSS != "" {
+		value, err := strconv.ParseFloat(minEPSS, 64)
+		if err != nil || value < 0 || value > 1 {
+			return filter, errors.New("Invalid min_epss value, must be a number between 0 and 1")
+		}
+		filter.MinEPSS = &value
+	}
+
+	if kev := c.Query("kev"); kev != "" {
+		value, err := strconv.ParseBool(kev)
+		if err != nil {
+			return filter, errors.New("Invalid kev value, must be true or false")
+		}
+		filter.KnownExploited = &value
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GeoIPHandler struct {
+	geoipService *services.GeoIPService
+}
+
+func NewGeoIPHandler(geoipService *services.GeoIPService) *GeoIPHandler {
+	return &GeoIPHandler{
+		geoipService: geoipService,
+	}
+}
+
+// GetDatabaseStatus returns the state of the loaded GeoIP and ASN databases
+// @Summary Get GeoIP database status
+// @Description Get the GeoIP and ASN database files loaded for target enrichment
+// @Tags geoip
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.GeoIPStatus
+// @Router /api/v1/geoip/databases [get]
+func (h *GeoIPHandler) GetDatabaseStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.geoipService.Status())
+}
+
+// RefreshDatabases reloads the GeoIP and ASN databases
+// @Summary Refresh GeoIP databases
+// @Description Reload the GeoIP and ASN databases from the database directory
+// @Tags geoip
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.GeoIPStatus
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/geoip/databases/refresh [post]
+func (h *GeoIPHandler) RefreshDatabases(c *gin.Context) {
+	reloadData(c, h.geoipService.LoadDatabases, h.geoipService.Status, "GeoIP databases")
+}
+
+// EnrichProjectTargets looks up every IP target of a project in the GeoIP and ASN databases
+// @Summary Enrich project targets
+// @Description Write ASN, organization, country and network prefix of every IP target of a project into its metadata
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} services.EnrichmentSummary
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/projects/{id}/targets/enrich [post]
+func (h *GeoIPHandler) EnrichProjectTargets(c *gin.Context) {
+	enrichProjectTargets(c, h.geoipService.LoadDatabases, "GeoIP databases", h.geoipService.EnrichProject, "enrich targets")
+}
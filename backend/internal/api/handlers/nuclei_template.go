@@ -0,0 +1,213 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NucleiTemplateHandler struct {
+	nucleiTemplateService *services.NucleiTemplateService
+}
+
+func NewNucleiTemplateHandler(nucleiTemplateService *services.NucleiTemplateService) *NucleiTemplateHandler {
+	return &NucleiTemplateHandler{
+		nucleiTemplateService: nucleiTemplateService,
+	}
+}
+
+// NucleiTemplateInput holds an uploaded template
+type NucleiTemplateInput struct {
+	Content string `json:"content" binding:"required"`
+}
+
+// GetTemplates returns the custom nuclei templates
+// @Summary List nuclei templates
+// @Description Get the custom nuclei templates with their id, tags and severity, without their content
+// @Tags nuclei
+// @Accept json
+// @Produce json
+// @Param tag query string false "Filter by tag"
+// @Param severity query string false "Filter by severity"
+// @Success 200 {array} models.NucleiTemplate
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/nuclei/templates [get]
+func (h *NucleiTemplateHandler) GetTemplates(c *gin.Context) {
+	templates, err := h.nucleiTemplateService.GetAll(services.NucleiTemplateFilter{
+		Tag:      c.Query("tag"),
+		Severity: c.Query("severity"),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve nuclei templates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, templates)
+}
+
+// GetTemplate returns a specific nuclei template with its content
+// @Summary Get a nuclei template
+// @Description Get a custom nuclei template by ID, including its content
+// @Tags nuclei
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 200 {object} models.NucleiTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/v1/nuclei/templates/{id} [get]
+func (h *NucleiTemplateHandler) GetTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID format"})
+		return
+	}
+
+	template, err := h.nucleiTemplateService.GetByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nuclei template not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// CreateTemplate validates and stores a custom nuclei template
+// @Summary Upload a nuclei template
+// @Description Upload a custom nuclei template, either as YAML or as JSON with the YAML in content
+// @Tags nuclei
+// @Accept json,application/x-yaml
+// @Produce json
+// @Param template body NucleiTemplateInput true "Template"
+// @Success 201 {object} models.NucleiTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/nuclei/templates [post]
+func (h *NucleiTemplateHandler) CreateTemplate(c *gin.Context) {
+	content, ok := h.readContent(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.nucleiTemplateService.Create(content)
+	if err != nil {
+		h.respondError(c, err, "Failed to store nuclei template")
+		return
+	}
+
+	c.JSON(http.StatusCreated, template)
+}
+
+// UpdateTemplate replaces the content of a custom nuclei template
+// @Summary Update a nuclei template
+// @Description Replace a custom nuclei template, either as YAML or as JSON with the YAML in content
+// @Tags nuclei
+// @Accept json,application/x-yaml
+// @Produce json
+// @Param id path string true "Template ID"
+// @Param template body NucleiTemplateInput true "Template"
+// @Success 200 {object} models.NucleiTemplate
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/nuclei/templates/{id} [put]
+func (h *NucleiTemplateHandler) UpdateTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID format"})
+		return
+	}
+
+	if _, err := h.nucleiTemplateService.GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nuclei template not found"})
+		return
+	}
+
+	content, ok := h.readContent(c)
+	if !ok {
+		return
+	}
+
+	template, err := h.nucleiTemplateService.Update(id, content)
+	if err != nil {
+		h.respondError(c, err, "Failed to update nuclei template")
+		return
+	}
+
+	c.JSON(http.StatusOK, template)
+}
+
+// DeleteTemplate deletes a custom nuclei template
+// @Summary Delete a nuclei template
+// @Description Delete a custom nuclei template by ID
+// @Tags nuclei
+// @Accept json
+// @Produce json
+// @Param id path string true "Template ID"
+// @Success 204 "No Content"
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/nuclei/templates/{id} [delete]
+func (h *NucleiTemplateHandler) DeleteTemplate(c *gin.Context) {
+	idStr := c.Param("id")
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid template ID format"})
+		return
+	}
+
+	if _, err := h.nucleiTemplateService.GetByID(id); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Nuclei template not found"})
+		return
+	}
+
+	if err := h.nucleiTemplateService.Delete(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete nuclei template"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// readContent reads the template from a JSON body or from a raw YAML body
+func (h *NucleiTemplateHandler) readContent(c *gin.Context) (string, bool) {
+	body := http.MaxBytesReader(c.Writer, c.Request.Body, services.MaxNucleiTemplateSize+1024)
+
+	if c.ContentType() == "application/json" {
+		c.Request.Body = body
+		var input NucleiTemplateInput
+		if err := c.ShouldBindJSON(&input); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return "", false
+		}
+		return input.Content, true
+	}
+
+	content, err := io.ReadAll(body)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read template: " + err.Error()})
+		return "", false
+	}
+	return string(content), true
+}
+
+// respondError maps template validation and conflicts to their status codes
+func (h *NucleiTemplateHandler) respondError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrInvalidNucleiTemplate):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNucleiTemplateExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": message})
+	}
+}
@@ -223,11 +223,17 @@ func (h *ProjectHandler) DeleteProject(c *gin.Context) {
 
 // GetProjectTargets gets all targets for a project
 // @Summary Get project targets
-// @Description Get all targets for a specific project
+// @Description Get all targets for a specific project, optionally filtered by type, ASN, organization or country
 // @Tags projects
 // @Accept json
 // @Produce json
 // @Param id path string true "Project ID"
+// @Param target_type query string false "Filter by target type"
+// @Param asn query string false "Filter by AS number, such as 13335 or AS13335"
+// @Param org query string false "Filter by AS organization, case-insensitive substring"
+// @Param country query string false "Filter by ISO country code"
+// @Param provider query string false "Filter by cloud or CDN provider, such as aws or cloudflare"
+// @Param cdn query bool false "Filter on whether the target is a CDN edge"
 // @Success 200 {array} models.Target
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -240,7 +246,18 @@ func (h *ProjectHandler) GetProjectTargets(c *gin.Context) {
 		return
 	}
 
-	targets, err := h.targetService.GetByProjectID(id)
+	filter, err := parseTargetFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var targets []models.Target
+	if filter == (services.TargetFilter{}) {
+		targets, err = h.targetService.GetByProjectID(id)
+	} else {
+		targets, err = h.targetService.GetFiltered(&id, filter)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve targets"})
 		return
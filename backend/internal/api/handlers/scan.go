@@ -2,9 +2,11 @@ package handlers
 
 import (
 	"net/http"
+	"strings"
 	"time"
 
 	"backend/internal/models"
+	"backend/internal/scanner"
 	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -16,6 +18,8 @@ type ScanHandler struct {
 	queueService   *services.QueueService
 	projectService *services.ProjectService
 	targetService  *services.TargetService
+	serviceService *services.ServiceService
+	registry       *scanner.Registry
 }
 
 func NewScanHandler(
@@ -23,15 +27,55 @@ func NewScanHandler(
 	queueService *services.QueueService,
 	projectService *services.ProjectService,
 	targetService *services.TargetService,
+	serviceService *services.ServiceService,
+	registry *scanner.Registry,
 ) *ScanHandler {
 	return &ScanHandler{
 		scanService:    scanService,
 		queueService:   queueService,
 		projectService: projectService,
 		targetService:  targetService,
+		serviceService: serviceService,
+		registry:       registry,
 	}
 }
 
+// GetScanners returns the scanner catalog
+// @Summary Get available scanners
+// @Description Get every scanner with its supported target types, service support and parameter JSON Schema
+// @Tags scanners
+// @Accept json
+// @Produce json
+// @Success 200 {array} scanner.ScannerInfo
+// @Router /api/v1/scanners [get]
+func (h *ScanHandler) GetScanners(c *gin.Context) {
+	c.JSON(http.StatusOK, h.registry.Catalog())
+}
+
+// validateScanConfig checks the scanner type and parameters of a scan configuration,
+// returning an error message per invalid field
+func (h *ScanHandler) validateScanConfig(scannerType string, parameters models.JSONB) map[string]string {
+	fieldErrors, err := h.registry.Validate(scannerType, parameters)
+	if err != nil {
+		// Plugins may only be installed on the workers, so their parameters can't be checked here
+		if strings.HasPrefix(scannerType, scanner.PluginTypePrefix) {
+			return nil
+		}
+		return map[string]string{"scanner_type": "unknown scanner type " + scannerType}
+	}
+
+	// Prefix parameter fields so they can be told apart from the config fields
+	prefixed := make(map[string]string, len(fieldErrors))
+	for field, message := range fieldErrors {
+		if field == "parameters" {
+			prefixed[field] = message
+		} else {
+			prefixed["parameters."+field] = message
+		}
+	}
+	return prefixed
+}
+
 // GetScans returns all scans
 // @Summary Get all scans
 // @Description Get all scans across all projects
@@ -126,6 +170,30 @@ func (h *ScanHandler) StartScan(c *gin.Context) {
 		return
 	}
 
+	// Determine services
+	var scanServices []models.Service
+	for _, serviceID := range input.ServiceIDs {
+		service, err := h.serviceService.GetByID(serviceID)
+		if err != nil {
+			continue
+		}
+
+		target, err := h.targetService.GetByID(service.TargetID)
+		if err != nil || target.ProjectID != project.ID {
+			continue
+		}
+
+		// Scanners address services through the host stored in the raw info
+		if service.RawInfo == nil {
+			service.RawInfo = models.JSONB{}
+		}
+		if _, ok := service.RawInfo["target_value"].(string); !ok {
+			service.RawInfo["target_value"] = target.Value
+		}
+
+		scanServices = append(scanServices, *service)
+	}
+
 	// Determine targets
 	var targets []models.Target
 	if len(input.TargetIDs) > 0 {
@@ -136,7 +204,7 @@ func (h *ScanHandler) StartScan(c *gin.Context) {
 				targets = append(targets, *target)
 			}
 		}
-	} else {
+	} else if len(input.ServiceIDs) == 0 {
 		// Use all targets from the project
 		projectTargets, err := h.targetService.GetByProjectID(project.ID)
 		if err == nil {
@@ -144,7 +212,7 @@ func (h *ScanHandler) StartScan(c *gin.Context) {
 		}
 	}
 
-	if len(targets) == 0 {
+	if len(targets) == 0 && len(scanServices) == 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "No valid targets found for scanning"})
 		return
 	}
@@ -154,6 +222,7 @@ func (h *ScanHandler) StartScan(c *gin.Context) {
 		ScanID:      scan.ID,
 		ScannerType: scanConfig.ScannerType,
 		Targets:     targets,
+		Services:    scanServices,
 		Parameters:  scanConfig.Parameters,
 	}
 
@@ -343,6 +412,11 @@ func (h *ScanHandler) CreateScanConfig(c *gin.Context) {
 		return
 	}
 
+	if fieldErrors := h.validateScanConfig(input.ScannerType, input.Parameters); len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan configuration", "fields": fieldErrors})
+		return
+	}
+
 	config := &models.ScanConfig{
 		Name:        input.Name,
 		ScannerType: input.ScannerType,
@@ -415,6 +489,11 @@ func (h *ScanHandler) UpdateScanConfig(c *gin.Context) {
 		config.Active = *input.Active
 	}
 
+	if fieldErrors := h.validateScanConfig(config.ScannerType, config.Parameters); len(fieldErrors) > 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid scan configuration", "fields": fieldErrors})
+		return
+	}
+
 	err = h.scanService.UpdateScanConfig(config)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update scan configuration"})
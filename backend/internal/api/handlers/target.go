@@ -1,8 +1,11 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
+	"backend/internal/geoip"
 	"backend/internal/models"
 	"backend/internal/services"
 
@@ -22,15 +25,33 @@ func NewTargetHandler(targetService *services.TargetService) *TargetHandler {
 
 // GetTargets returns all targets
 // @Summary Get all targets
-// @Description Get all targets across all projects
+// @Description Get all targets across all projects, optionally filtered by type, ASN, organization or country
 // @Tags targets
 // @Accept json
 // @Produce json
+// @Param target_type query string false "Filter by target type"
+// @Param asn query string false "Filter by AS number, such as 13335 or AS13335"
+// @Param org query string false "Filter by AS organization, case-insensitive substring"
+// @Param country query string false "Filter by ISO country code"
+// @Param provider query string false "Filter by cloud or CDN provider, such as aws or cloudflare"
+// @Param cdn query bool false "Filter on whether the target is a CDN edge"
 // @Success 200 {array} models.Target
+// @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/v1/targets [get]
 func (h *TargetHandler) GetTargets(c *gin.Context) {
-	targets, err := h.targetService.GetAll()
+	filter, err := parseTargetFilter(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var targets []models.Target
+	if filter == (services.TargetFilter{}) {
+		targets, err = h.targetService.GetAll()
+	} else {
+		targets, err = h.targetService.GetFiltered(nil, filter)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to retrieve targets"})
 		return
@@ -349,3 +370,30 @@ func (h *TargetHandler) GetTargetServices(c *gin.Context) {
 
 	c.JSON(http.StatusOK, services)
 }
+
+// parseTargetFilter reads the target filters from the query parameters
+func parseTargetFilter(c *gin.Context) (services.TargetFilter, error) {
+	filter := services.TargetFilter{
+		TargetType:   c.Query("target_type"),
+		Organization: c.Query("org"),
+		Country:      c.Query("country"),
+		Provider:     c.Query("provider"),
+	}
+
+	if asn := c.Query("asn"); asn != "" {
+		filter.ASN = geoip.ParseASN(asn)
+		if filter.ASN == 0 {
+			return filter, errors.New("Invalid ASN format")
+		}
+	}
+
+	if cdn := c.Query("cdn"); cdn != "" {
+		value, err := strconv.ParseBool(cdn)
+		if err != nil {
+			return filter, errors.New("Invalid cdn value, must be true or false")
+		}
+		filter.CDN = &value
+	}
+
+	return filter, nil
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"backend/internal/services"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type VulnerabilityHandler struct {
+	vulnerabilityService *services.VulnerabilityService
+}
+
+func NewVulnerabilityHandler(vulnerabilityService *services.VulnerabilityService) *VulnerabilityHandler {
+	return &VulnerabilityHandler{
+		vulnerabilityService: vulnerabilityService,
+	}
+}
+
+// GetFeedStatus returns the state of the loaded CVE feeds
+// @Summary Get CVE feed status
+// @Description Get the CVE feed files loaded for offline version matching
+// @Tags vulnerabilities
+// @Accept json
+// @Produce json
+// @Success 200 {object} services.FeedStatus
+// @Router /api/v1/vulnerabilities/feeds [get]
+func (h *VulnerabilityHandler) GetFeedStatus(c *gin.Context) {
+	c.JSON(http.StatusOK, h.vulnerabilityService.Status())
+}
+
+// RefreshFeeds reloads the CVE feeds and recomputes vulnerable version findings
+// @Summary Refresh CVE feeds
+// @Description Reload the CVE feeds from the feed directory and match services and applications against them
+// @Tags vulnerabilities
+// @Accept json
+// @Produce json
+// @Param project_id query string false "Only recompute findings for this project"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/v1/vulnerabilities/feeds/refresh [post]
+func (h *VulnerabilityHandler) RefreshFeeds(c *gin.Context) {
+	// Get project ID from query param if exists
+	projectIDStr := c.Query("project_id")
+	var projectID *uuid.UUID
+
+	if projectIDStr != "" {
+		id, err := uuid.Parse(projectIDStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID format"})
+			return
+		}
+		projectID = &id
+	}
+
+	if _, err := h.vulnerabilityService.LoadFeeds(true); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to load CVE feeds: " + err.Error()})
+		return
+	}
+
+	var summary *services.RecomputeSummary
+	var err error
+	if projectID != nil {
+		summary, err = h.vulnerabilityService.RecomputeProject(*projectID)
+	} else {
+		summary, err = h.vulnerabilityService.RecomputeAll()
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to recompute vulnerable versions"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"feeds":   h.vulnerabilityService.Status(),
+		"summary": summary,
+	})
+}
@@ -4,6 +4,7 @@ package api
 import (
 	"backend/internal/api/handlers"
 	"backend/internal/api/middleware"
+	"backend/internal/scanner"
 	"backend/internal/services"
 
 	"github.com/gin-gonic/gin"
@@ -22,6 +23,12 @@ func SetupRouter(
 	applicationService *services.ApplicationService,
 	dnsRecordService *services.DNSRecordService,
 	certificateService *services.CertificateService,
+	vulnerabilityService *services.VulnerabilityService,
+	exploitService *services.ExploitService,
+	geoipService *services.GeoIPService,
+	attributionService *services.AttributionService,
+	nucleiTemplateService *services.NucleiTemplateService,
+	scannerRegistry *scanner.Registry,
 ) *gin.Engine {
 	// Create router with default logger and recovery middleware
 	router := gin.Default()
@@ -38,13 +45,18 @@ func SetupRouter(
 	// Create handlers
 	projectHandler := handlers.NewProjectHandler(projectService, targetService)
 	targetHandler := handlers.NewTargetHandler(targetService)
-	scanHandler := handlers.NewScanHandler(scanService, queueService, projectService, targetService)
+	scanHandler := handlers.NewScanHandler(scanService, queueService, projectService, targetService, serviceService, scannerRegistry)
 	findingHandler := handlers.NewFindingHandler(findingService)
 	serviceHandler := handlers.NewServiceHandler(serviceService, targetService)
 	relationHandler := handlers.NewRelationHandler(relationService, targetService)
 	applicationHandler := handlers.NewApplicationHandler(applicationService, projectService, targetService, serviceService)
 	dnsRecordHandler := handlers.NewDNSRecordHandler(dnsRecordService)
 	certificateHandler := handlers.NewCertificateHandler(certificateService)
+	vulnerabilityHandler := handlers.NewVulnerabilityHandler(vulnerabilityService)
+	exploitHandler := handlers.NewExploitHandler(exploitService)
+	geoipHandler := handlers.NewGeoIPHandler(geoipService)
+	attributionHandler := handlers.NewAttributionHandler(attributionService)
+	nucleiTemplateHandler := handlers.NewNucleiTemplateHandler(nucleiTemplateService)
 
 	// Health check endpoint
 	router.GET("/health", func(c *gin.Context) {
@@ -67,6 +79,8 @@ func SetupRouter(
 			projects.PUT("/:id", projectHandler.UpdateProject)
 			projects.DELETE("/:id", projectHandler.DeleteProject)
 			projects.GET("/:id/targets", projectHandler.GetProjectTargets)
+			projects.POST("/:id/targets/enrich", geoipHandler.EnrichProjectTargets)
+			projects.POST("/:id/targets/attribute", attributionHandler.AttributeProjectTargets)
 			projects.GET("/:id/scans", projectHandler.GetProjectScans)
 			projects.GET("/:id/findings", projectHandler.GetProjectFindings)
 			projects.GET("/:id/services", projectHandler.GetProjectServices)
@@ -134,6 +148,12 @@ func SetupRouter(
 			scans.GET("/:id/tasks", scanHandler.GetScanTasks)
 		}
 
+		// Scanner catalog
+		scanners := v1.Group("/scanners")
+		{
+			scanners.GET("", scanHandler.GetScanners)
+		}
+
 		// Scan configurations
 		scanConfigs := v1.Group("/scan-configs")
 		{
@@ -178,6 +198,39 @@ func SetupRouter(
 			certificates.PUT("/:id", certificateHandler.UpdateCertificate)
 			certificates.DELETE("/:id", certificateHandler.DeleteCertificate)
 		}
+
+		// CVE feeds used for vulnerable version matching, EPSS and KEV data used to prioritize findings
+		vulnerabilities := v1.Group("/vulnerabilities")
+		{
+			vulnerabilities.GET("/feeds", vulnerabilityHandler.GetFeedStatus)
+			vulnerabilities.POST("/feeds/refresh", vulnerabilityHandler.RefreshFeeds)
+			vulnerabilities.GET("/exploits", exploitHandler.GetDataStatus)
+			vulnerabilities.POST("/exploits/refresh", exploitHandler.RefreshData)
+		}
+
+		// GeoIP and ASN databases used for target enrichment
+		geoip := v1.Group("/geoip")
+		{
+			geoip.GET("/databases", geoipHandler.GetDatabaseStatus)
+			geoip.POST("/databases/refresh", geoipHandler.RefreshDatabases)
+		}
+
+		// Cloud and CDN ranges used for target attribution
+		cloud := v1.Group("/cloud")
+		{
+			cloud.GET("/ranges", attributionHandler.GetRangeStatus)
+			cloud.POST("/ranges/refresh", attributionHandler.RefreshRanges)
+		}
+
+		// Custom nuclei templates distributed to the workers
+		nuclei := v1.Group("/nuclei")
+		{
+			nuclei.GET("/templates", nucleiTemplateHandler.GetTemplates)
+			nuclei.POST("/templates", nucleiTemplateHandler.CreateTemplate)
+			nuclei.GET("/templates/:id", nucleiTemplateHandler.GetTemplate)
+			nuclei.PUT("/templates/:id", nucleiTemplateHandler.UpdateTemplate)
+			nuclei.DELETE("/templates/:id", nucleiTemplateHandler.DeleteTemplate)
+		}
 	}
 
 	return router
@@ -0,0 +1,166 @@
+// internal/cloud/database.go
+package cloud
+
+import (
+	"backend/internal/datadir"
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// RangeFile describes a range file loaded into the database
+type RangeFile struct {
+	Name       string    `json:"name"`
+	Provider   string    `json:"provider"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Ranges     int       `json:"ranges"`
+}
+
+// Attribution is the provider an IP address belongs to
+type Attribution struct {
+	Provider string `json:"provider"`
+	Service  string `json:"service,omitempty"`
+	Region   string `json:"region,omitempty"`
+	Network  string `json:"network"`
+	CDN      bool   `json:"cdn"`
+}
+
+// Database holds the published ranges of every loaded file, indexed by network
+type Database struct {
+	Files    []RangeFile
+	LoadedAt time.Time
+
+	ranges map[netip.Prefix][]rangeEntry
+	bits   map[int]bool // Prefix lengths present in the index, per address family
+	size   int
+}
+
+// RangesDir returns the directory range files are loaded from, set through CLOUD_RANGES_DIR
+func RangesDir() string {
+	return datadir.Dir("CLOUD_RANGES_DIR", "cloud")
+}
+
+// IsRangeFile reports whether a file name is a JSON or text range file, gzip compressed or not
+var IsRangeFile = datadir.Extensions(".json", ".txt")
+
+// Load reads every range file in dir. JSON files are recognized by their layout (AWS ip-ranges.json,
+// Azure service tags, Google goog.json and cloud.json, Fastly and Oracle range lists), text files list one
+// network per line and are attributed to the provider they are named after. Files may be gzip compressed.
+func Load(dir string) (*Database, error) {
+	files, err := datadir.Files(dir, IsRangeFile)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &Database{
+		ranges: make(map[netip.Prefix][]rangeEntry),
+		bits:   make(map[int]bool),
+	}
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		entries, provider, err := loadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", file.Name(), err)
+		}
+
+		for _, entry := range entries {
+			db.ranges[entry.prefix] = append(db.ranges[entry.prefix], entry)
+			db.bits[familyBits(entry.prefix.Addr(), entry.prefix.Bits())] = true
+		}
+		db.size += len(entries)
+
+		db.Files = append(db.Files, RangeFile{
+			Name:       file.Name(),
+			Provider:   provider,
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+			Ranges:     len(entries),
+		})
+	}
+
+	if db.size == 0 {
+		return nil, fmt.Errorf("no cloud or CDN ranges found in %s", dir)
+	}
+
+	db.LoadedAt = time.Now()
+	return db, nil
+}
+
+// loadFile reads a single range file, decompressing it when needed
+func loadFile(path string) ([]rangeEntry, string, error) {
+	reader, name, err := datadir.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	defer reader.Close()
+
+	if strings.HasSuffix(name, ".json") {
+		return parseJSONRanges(reader)
+	}
+	return parseTextRanges(reader, name)
+}
+
+// Size returns the number of ranges loaded
+func (db *Database) Size() int {
+	return db.size
+}
+
+// Lookup returns the provider an IP address belongs to, or nil when it isn't in any published range.
+// A specific service is preferred over a provider's catch-all ranges, then the most specific network.
+func (db *Database) Lookup(ip string) (*Attribution, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+	addr = addr.Unmap()
+
+	var best *rangeEntry
+	cdn := false
+	for bits := addr.BitLen(); bits >= 0; bits-- {
+		if !db.bits[familyBits(addr, bits)] {
+			continue
+		}
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			continue
+		}
+		for i := range db.ranges[prefix] {
+			entry := &db.ranges[prefix][i]
+			cdn = cdn || entry.cdn
+			if best == nil || (!entry.generic() && best.generic()) {
+				best = entry
+			}
+		}
+	}
+	if best == nil {
+		return nil, nil
+	}
+
+	return &Attribution{
+		Provider: best.provider,
+		Service:  best.service,
+		Region:   best.region,
+		Network:  best.prefix.String(),
+		CDN:      cdn,
+	}, nil
+}
+
+// generic reports whether the entry is one of a provider's catch-all ranges
+func (e *rangeEntry) generic() bool {
+	return e.service == "" || genericServices[e.provider+":"+e.service]
+}
+
+// familyBits keys prefix lengths by address family, since IPv4 and IPv6 lengths overlap
+func familyBits(addr netip.Addr, bits int) int {
+	if addr.Is4() {
+		return bits
+	}
+	return 1000 + bits
+}
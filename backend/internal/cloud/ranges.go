@@ -0,0 +1,239 @@
+// internal/cloud/ranges.go
+package cloud
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/netip"
+	"path/filepath"
+	"strings"
+)
+
+// cdnProviders are providers whose published ranges are CDN or reverse proxy edges
+var cdnProviders = map[string]bool{
+	"akamai":     true,
+	"bunnycdn":   true,
+	"cdn77":      true,
+	"cloudflare": true,
+	"cloudfront": true,
+	"edgecast":   true,
+	"fastly":     true,
+	"gcore":      true,
+	"imperva":    true,
+	"incapsula":  true,
+	"keycdn":     true,
+	"stackpath":  true,
+	"sucuri":     true,
+}
+
+// cdnServices are services of cloud providers that are CDN edges
+var cdnServices = map[string]bool{
+	"aws:CLOUDFRONT":                true,
+	"azure:AzureFrontDoor.Frontend": true,
+}
+
+// genericServices cover every range of a provider, more specific services are preferred over them
+var genericServices = map[string]bool{
+	"aws:AMAZON":       true,
+	"azure:AzureCloud": true,
+}
+
+// rangeEntry is a network published by a provider
+type rangeEntry struct {
+	prefix   netip.Prefix
+	provider string
+	service  string
+	region   string
+	cdn      bool
+}
+
+// rangeFile holds the fields of the supported JSON range formats
+type rangeFile struct {
+	// AWS ip-ranges.json and Google goog.json / cloud.json
+	Prefixes []struct {
+		IPPrefix   string `json:"ip_prefix"`
+		IPv4Prefix string `json:"ipv4Prefix"`
+		IPv6Prefix string `json:"ipv6Prefix"`
+		Region     string `json:"region"`
+		Scope      string `json:"scope"`
+		Service    string `json:"service"`
+	} `json:"prefixes"`
+	IPv6Prefixes []struct {
+		IPv6Prefix string `json:"ipv6_prefix"`
+		Region     string `json:"region"`
+		Service    string `json:"service"`
+	} `json:"ipv6_prefixes"`
+
+	// Azure service tags
+	Values []struct {
+		Name       string `json:"name"`
+		Properties struct {
+			Region          string   `json:"region"`
+			AddressPrefixes []string `json:"addressPrefixes"`
+		} `json:"properties"`
+	} `json:"values"`
+
+	// Fastly public-ip-list
+	Addresses     []string `json:"addresses"`
+	IPv6Addresses []string `json:"ipv6_addresses"`
+
+	// Oracle Cloud public_ip_ranges.json
+	Regions []struct {
+		Region string `json:"region"`
+		CIDRs  []struct {
+			CIDR string   `json:"cidr"`
+			Tags []string `json:"tags"`
+		} `json:"cidrs"`
+	} `json:"regions"`
+}
+
+// parseJSONRanges reads a provider's JSON range file, recognizing the provider from its layout
+func parseJSONRanges(reader io.Reader) ([]rangeEntry, string, error) {
+	var file rangeFile
+	if err := json.NewDecoder(reader).Decode(&file); err != nil {
+		return nil, "", err
+	}
+
+	var entries []rangeEntry
+	add := func(value, provider, service, region string) {
+		prefix, ok := parsePrefix(value)
+		if !ok {
+			return
+		}
+		entries = append(entries, newEntry(prefix, provider, service, region))
+	}
+
+	switch {
+	case len(file.Prefixes) > 0 && file.Prefixes[0].IPPrefix != "", len(file.IPv6Prefixes) > 0:
+		for _, prefix := range file.Prefixes {
+			add(prefix.IPPrefix, "aws", prefix.Service, prefix.Region)
+		}
+		for _, prefix := range file.IPv6Prefixes {
+			add(prefix.IPv6Prefix, "aws", prefix.Service, prefix.Region)
+		}
+		return entries, "aws", nil
+
+	case len(file.Prefixes) > 0:
+		// cloud.json lists Google Cloud customer ranges, goog.json every Google range
+		provider := "google"
+		for _, prefix := range file.Prefixes {
+			if prefix.Service != "" {
+				provider = "gcp"
+				break
+			}
+		}
+		for _, prefix := range file.Prefixes {
+			value := prefix.IPv4Prefix
+			if value == "" {
+				value = prefix.IPv6Prefix
+			}
+			add(value, provider, prefix.Service, prefix.Scope)
+		}
+		return entries, provider, nil
+
+	case len(file.Values) > 0:
+		for _, value := range file.Values {
+			// Regional tags are named after their region, such as AzureCloud.westeurope
+			service := value.Name
+			if value.Properties.Region != "" {
+				service = strings.TrimSuffix(service, "."+value.Properties.Region)
+			}
+			for _, prefix := range value.Properties.AddressPrefixes {
+				add(prefix, "azure", service, value.Properties.Region)
+			}
+		}
+		return entries, "azure", nil
+
+	case len(file.Addresses) > 0 || len(file.IPv6Addresses) > 0:
+		for _, address := range append(file.Addresses, file.IPv6Addresses...) {
+			add(address, "fastly", "", "")
+		}
+		return entries, "fastly", nil
+
+	case len(file.Regions) > 0:
+		for _, region := range file.Regions {
+			for _, cidr := range region.CIDRs {
+				add(cidr.CIDR, "oracle", strings.Join(cidr.Tags, ","), region.Region)
+			}
+		}
+		return entries, "oracle", nil
+	}
+
+	return nil, "", fmt.Errorf("unrecognized range file format")
+}
+
+// parseTextRanges reads a list with one network per line, as published by Cloudflare and Akamai.
+// The provider is taken from the file name, so cloudflare-ips-v4.txt lists Cloudflare ranges.
+func parseTextRanges(reader io.Reader, name string) ([]rangeEntry, string, error) {
+	provider := textProvider(name)
+
+	var entries []rangeEntry
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+
+		prefix, ok := parsePrefix(line)
+		if !ok {
+			return nil, "", fmt.Errorf("line %d: invalid network %q", lineNumber, line)
+		}
+		entries = append(entries, newEntry(prefix, provider, "", ""))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, "", err
+	}
+	return entries, provider, nil
+}
+
+// textProvider returns the provider a text list is named after
+func textProvider(name string) string {
+	base := strings.ToLower(filepath.Base(name))
+	if index := strings.IndexAny(base, "-_."); index > 0 {
+		return base[:index]
+	}
+	return base
+}
+
+// newEntry builds a range entry and classifies it as CDN edge or not
+func newEntry(prefix netip.Prefix, provider, service, region string) rangeEntry {
+	return rangeEntry{
+		prefix:   prefix,
+		provider: provider,
+		service:  service,
+		region:   region,
+		cdn:      cdnProviders[provider] || cdnServices[provider+":"+service],
+	}
+}
+
+// parsePrefix parses a network in CIDR notation or a single address
+func parsePrefix(value string) (netip.Prefix, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return netip.Prefix{}, false
+	}
+
+	if !strings.Contains(value, "/") {
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return netip.Prefix{}, false
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), true
+	}
+
+	prefix, err := netip.ParsePrefix(value)
+	if err != nil {
+		return netip.Prefix{}, false
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), true
+}
@@ -0,0 +1,256 @@
+// internal/cve/cpe.go
+package cve
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// knownProducts maps product names reported by scanners to the vendor:product pairs of their CPEs.
+// Products that moved vendors list every pair they were published under.
+var knownProducts = map[string][]string{
+	"openssh":               {"openbsd:openssh"},
+	"dropbear":              {"dropbear_ssh_project:dropbear_ssh"},
+	"dropbear_sshd":         {"dropbear_ssh_project:dropbear_ssh"},
+	"vsftpd":                {"beasts:vsftpd"},
+	"proftpd":               {"proftpd:proftpd"},
+	"pure-ftpd":             {"pureftpd:pure-ftpd"},
+	"filezilla_server":      {"filezilla-project:filezilla_server"},
+	"exim":                  {"exim:exim"},
+	"postfix":               {"postfix:postfix"},
+	"sendmail":              {"sendmail:sendmail"},
+	"dovecot":               {"dovecot:dovecot"},
+	"microsoft_exchange":    {"microsoft:exchange_server"},
+	"mysql":                 {"oracle:mysql", "mysql:mysql"},
+	"mariadb":               {"mariadb:mariadb"},
+	"postgresql":            {"postgresql:postgresql"},
+	"redis":                 {"redis:redis", "redislabs:redis"},
+	"mongodb":               {"mongodb:mongodb"},
+	"elasticsearch":         {"elastic:elasticsearch", "elasticsearch:elasticsearch"},
+	"kibana":                {"elastic:kibana", "elasticsearch:kibana"},
+	"nginx":                 {"f5:nginx", "nginx:nginx"},
+	"apache":                {"apache:http_server"},
+	"apache_httpd":          {"apache:http_server"},
+	"apache_http_server":    {"apache:http_server"},
+	"httpd":                 {"apache:http_server"},
+	"tomcat":                {"apache:tomcat"},
+	"apache_tomcat":         {"apache:tomcat"},
+	"microsoft_iis":         {"microsoft:internet_information_services", "microsoft:iis"},
+	"iis":                   {"microsoft:internet_information_services", "microsoft:iis"},
+	"lighttpd":              {"lighttpd:lighttpd"},
+	"haproxy":               {"haproxy:haproxy"},
+	"varnish":               {"varnish-cache:varnish", "varnish_cache_project:varnish_cache"},
+	"squid":                 {"squid-cache:squid"},
+	"openssl":               {"openssl:openssl"},
+	"php":                   {"php:php"},
+	"jquery":                {"jquery:jquery"},
+	"wordpress":             {"wordpress:wordpress"},
+	"drupal":                {"drupal:drupal"},
+	"joomla":                {"joomla:joomla\\!"},
+	"jenkins":               {"jenkins:jenkins"},
+	"gitlab":                {"gitlab:gitlab"},
+	"grafana":               {"grafana:grafana"},
+	"jira":                  {"atlassian:jira", "atlassian:jira_server"},
+	"confluence":            {"atlassian:confluence", "atlassian:confluence_server"},
+	"bind":                  {"isc:bind"},
+	"samba":                 {"samba:samba"},
+	"microsoft_terminal":    {"microsoft:remote_desktop_services"},
+	"phpmyadmin":            {"phpmyadmin:phpmyadmin"},
+	"roundcube":             {"roundcube:webmail"},
+	"zimbra":                {"zimbra:collaboration_suite", "synacor:zimbra_collaboration_suite"},
+	"openvpn":               {"openvpn:openvpn"},
+	"node.js":               {"nodejs:node.js"},
+	"express":               {"expressjs:express"},
+	"django":                {"djangoproject:django"},
+	"ruby_on_rails":         {"rubyonrails:rails"},
+	"spring_boot":           {"vmware:spring_boot"},
+	"microsoft_asp.net":     {"microsoft:asp.net"},
+	"bootstrap":             {"getbootstrap:bootstrap"},
+	"angularjs":             {"angularjs:angular.js"},
+	"moment.js":             {"momentjs:moment"},
+	"lodash":                {"lodash:lodash"},
+	"openresty":             {"openresty:openresty"},
+	"caddy":                 {"caddyserver:caddy"},
+	"traefik":               {"traefik:traefik"},
+	"minio":                 {"minio:minio"},
+	"rabbitmq":              {"vmware:rabbitmq", "pivotal_software:rabbitmq"},
+	"memcached":             {"memcached:memcached"},
+	"cyrus":                 {"cmu:cyrus_imap_server"},
+	"courier":               {"courier-mta:courier-imap"},
+	"opensmtpd":             {"openbsd:opensmtpd"},
+	"microsoft_ftp":         {"microsoft:internet_information_services"},
+	"microsoft_ftp_service": {"microsoft:internet_information_services"},
+}
+
+// versionPattern extracts the leading version of a version string, such as "8.9p1" from "8.9p1 Ubuntu 3ubuntu0.1"
+var versionPattern = regexp.MustCompile(`(?i)v?(\d+(?:\.\d+)*(?:[a-z]+\d*)?)`)
+
+// preReleases are version labels that sort before the release they precede
+var preReleases = map[string]bool{
+	"alpha":    true,
+	"beta":     true,
+	"rc":       true,
+	"pre":      true,
+	"dev":      true,
+	"preview":  true,
+	"snapshot": true,
+}
+
+// CPE holds the fields of a CPE name used for matching
+type CPE struct {
+	Part    string
+	Vendor  string
+	Product string
+	Version string
+	Update  string
+}
+
+// Key returns the vendor:product pair of the CPE
+func (c CPE) Key() string {
+	return c.Vendor + ":" + c.Product
+}
+
+// ParseCPE parses a CPE 2.3 formatted string, or a CPE 2.2 URI such as nmap reports
+func ParseCPE(value string) (CPE, bool) {
+	if strings.HasPrefix(value, "cpe:/") {
+		fields := strings.Split(strings.TrimPrefix(value, "cpe:/"), ":")
+		for len(fields) < 5 {
+			fields = append(fields, "*")
+		}
+		return CPE{Part: fields[0], Vendor: fields[1], Product: fields[2], Version: fields[3], Update: fields[4]}, fields[1] != "" && fields[2] != ""
+	}
+
+	if !strings.HasPrefix(value, "cpe:2.3:") {
+		return CPE{}, false
+	}
+
+	// Fields are separated by colons, escaped colons belong to the value
+	var fields []string
+	var current strings.Builder
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			current.WriteRune('\\')
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == ':':
+			fields = append(fields, current.String())
+			current.Reset()
+		default:
+			current.WriteRune(r)
+		}
+	}
+	fields = append(fields, current.String())
+
+	if len(fields) < 7 {
+		return CPE{}, false
+	}
+	return CPE{Part: fields[2], Vendor: fields[3], Product: fields[4], Version: fields[5], Update: fields[6]}, true
+}
+
+// NormalizeVersion extracts the comparable version from a version string reported by a scanner
+func NormalizeVersion(version string) string {
+	match := versionPattern.FindStringSubmatch(version)
+	if match == nil {
+		return ""
+	}
+	return strings.ToLower(match[1])
+}
+
+// CompareVersions compares two versions segment by segment and returns -1, 0 or 1
+func CompareVersions(a, b string) int {
+	as := versionSegments(a)
+	bs := versionSegments(b)
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		if i >= len(as) {
+			return -remainderOrder(bs[i:])
+		}
+		if i >= len(bs) {
+			return remainderOrder(as[i:])
+		}
+
+		an, aErr := strconv.Atoi(as[i])
+		bn, bErr := strconv.Atoi(bs[i])
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				return sign(an - bn)
+			}
+		case aErr == nil:
+			// A number sorts after a label, 1.0.1 is newer than 1.0rc1
+			return 1
+		case bErr == nil:
+			return -1
+		default:
+			if as[i] != bs[i] {
+				return strings.Compare(as[i], bs[i])
+			}
+		}
+	}
+	return 0
+}
+
+// remainderOrder returns how a version compares to its own prefix given the segments it has left:
+// pre-releases sort before it, zeros are equal and anything else sorts after
+func remainderOrder(segments []string) int {
+	for _, segment := range segments {
+		if n, err := strconv.Atoi(segment); err == nil && n == 0 {
+			continue
+		}
+		if preReleases[segment] {
+			return -1
+		}
+		return 1
+	}
+	return 0
+}
+
+// versionSegments splits a version into runs of digits and runs of letters
+func versionSegments(version string) []string {
+	var segments []string
+	var current strings.Builder
+	digits := false
+	for _, r := range strings.ToLower(version) {
+		isDigit := r >= '0' && r <= '9'
+		isLetter := r >= 'a' && r <= 'z'
+		if !isDigit && !isLetter {
+			if current.Len() > 0 {
+				segments = append(segments, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		if current.Len() > 0 && isDigit != digits {
+			segments = append(segments, current.String())
+			current.Reset()
+		}
+		digits = isDigit
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		segments = append(segments, current.String())
+	}
+
+	return segments
+}
+
+// productKey normalizes a product name for lookups
+func productKey(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "_")
+}
+
+func sign(n int) int {
+	switch {
+	case n < 0:
+		return -1
+	case n > 0:
+		return 1
+	default:
+		return 0
+	}
+}
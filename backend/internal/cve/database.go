@@ -0,0 +1,251 @@
+// internal/cve/database.go
+package cve
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Vulnerability is a CVE entry from an NVD feed
+type Vulnerability struct {
+	ID           string   `json:"id"`
+	Description  string   `json:"description"`
+	CVSSScore    float64  `json:"cvss_score"`
+	CVSSVersion  string   `json:"cvss_version,omitempty"`
+	CVSSVector   string   `json:"cvss_vector,omitempty"`
+	References   []string `json:"references,omitempty"`
+	CWEs         []string `json:"cwes,omitempty"`
+	Published    string   `json:"published,omitempty"`
+	LastModified string   `json:"last_modified,omitempty"`
+}
+
+// Component is a versioned piece of software to match against the feeds
+type Component struct {
+	Product string
+	Version string
+	CPEs    []string // Explicit CPEs reported by the scanner, if any
+}
+
+// Match is the result of matching a component
+type Match struct {
+	Vendor          string
+	Product         string
+	Version         string
+	CPE             string
+	Vulnerabilities []*Vulnerability // Sorted by CVSS score, highest first
+}
+
+// Database holds the CVE entries of the loaded feeds, indexed by vendor:product
+type Database struct {
+	Files    []FeedFile
+	LoadedAt time.Time
+
+	vulnerabilities map[string]*Vulnerability
+	rules           map[string][]matchRule
+	products        map[string][]string // product -> vendors
+}
+
+// matchRule is a vulnerable CPE match, either a single version or a version range
+type matchRule struct {
+	vulnerability  *Vulnerability
+	version        string
+	update         string
+	startIncluding string
+	startExcluding string
+	endIncluding   string
+	endExcluding   string
+	key            string
+}
+
+// releasePattern matches the numeric part of a version, used when a CPE places the suffix in the update field
+var releasePattern = regexp.MustCompile(`^\d+(?:\.\d+)*`)
+
+func newDatabase() *Database {
+	return &Database{
+		vulnerabilities: make(map[string]*Vulnerability),
+		rules:           make(map[string][]matchRule),
+		products:        make(map[string][]string),
+	}
+}
+
+func newMatchRule(criteria, startIncluding, startExcluding, endIncluding, endExcluding string) (matchRule, bool) {
+	cpe, ok := ParseCPE(criteria)
+	if !ok {
+		return matchRule{}, false
+	}
+	return matchRule{
+		version:        strings.ToLower(unescape(cpe.Version)),
+		update:         strings.ToLower(unescape(cpe.Update)),
+		startIncluding: startIncluding,
+		startExcluding: startExcluding,
+		endIncluding:   endIncluding,
+		endExcluding:   endExcluding,
+		key:            cpe.Key(),
+	}, true
+}
+
+// add stores a vulnerability with its match rules, a later entry for the same CVE replaces the earlier one
+func (db *Database) add(vulnerability *Vulnerability, rules []matchRule) bool {
+	if len(rules) == 0 {
+		return false
+	}
+
+	// Feeds such as the modified feed update entries of the yearly feeds, rules of
+	// the replaced entry stay indexed but are skipped when matching
+	db.vulnerabilities[vulnerability.ID] = vulnerability
+
+	for _, rule := range rules {
+		rule.vulnerability = vulnerability
+		db.rules[rule.key] = append(db.rules[rule.key], rule)
+		vendor, product, _ := strings.Cut(rule.key, ":")
+		db.addProduct(CPE{Vendor: vendor, Product: product})
+	}
+	return true
+}
+
+// addProduct indexes the vendor of a product, returning whether it was new
+func (db *Database) addProduct(cpe CPE) bool {
+	product := strings.ToLower(cpe.Product)
+	vendor := strings.ToLower(cpe.Vendor)
+	for _, existing := range db.products[product] {
+		if existing == vendor {
+			return false
+		}
+	}
+	db.products[product] = append(db.products[product], vendor)
+	return true
+}
+
+// Size returns the number of CVEs loaded
+func (db *Database) Size() int {
+	return len(db.vulnerabilities)
+}
+
+// Resolve returns the vendor:product pairs a product name reported by a scanner refers to.
+// Trailing words are dropped until a name resolves, so "Apache httpd" and "OpenSSH server" resolve too.
+func (db *Database) Resolve(name string) []string {
+	words := strings.Fields(strings.ToLower(name))
+	for n := len(words); n > 0; n-- {
+		key := productKey(strings.Join(words[:n], " "))
+
+		if keys, ok := knownProducts[key]; ok {
+			return keys
+		}
+
+		// Fall back to the products seen in the feeds and dictionary when the vendor is unambiguous
+		vendors := db.products[key]
+		if len(vendors) == 1 {
+			return []string{vendors[0] + ":" + key}
+		}
+		for _, vendor := range vendors {
+			if vendor == key {
+				return []string{vendor + ":" + key}
+			}
+		}
+	}
+	return nil
+}
+
+// Match returns the CVEs affecting a component, or nil when it can't be resolved to a CPE or has no version
+func (db *Database) Match(component Component) *Match {
+	version := NormalizeVersion(component.Version)
+
+	var keys []string
+	for _, value := range component.CPEs {
+		cpe, ok := ParseCPE(value)
+		if !ok {
+			continue
+		}
+		keys = append(keys, cpe.Key())
+		if version == "" && cpe.Version != "*" && cpe.Version != "-" {
+			version = NormalizeVersion(unescape(cpe.Version))
+		}
+	}
+	if len(keys) == 0 {
+		keys = db.Resolve(component.Product)
+	}
+	if len(keys) == 0 || version == "" {
+		return nil
+	}
+
+	// Report the first pair with matches, or the first pair when nothing matched
+	result := &Match{Version: version}
+	result.Vendor, result.Product, _ = strings.Cut(keys[0], ":")
+	seen := make(map[string]bool)
+	for _, key := range keys {
+		matched := false
+		for _, rule := range db.rules[key] {
+			if db.vulnerabilities[rule.vulnerability.ID] != rule.vulnerability || seen[rule.vulnerability.ID] || !rule.affects(version) {
+				continue
+			}
+			seen[rule.vulnerability.ID] = true
+			result.Vulnerabilities = append(result.Vulnerabilities, rule.vulnerability)
+			matched = true
+		}
+		if matched && result.CPE == "" {
+			result.Vendor, result.Product, _ = strings.Cut(key, ":")
+			result.CPE = cpeName(key, version)
+		}
+	}
+	if result.CPE == "" {
+		result.CPE = cpeName(result.Vendor+":"+result.Product, version)
+	}
+
+	sort.Slice(result.Vulnerabilities, func(i, j int) bool {
+		a, b := result.Vulnerabilities[i], result.Vulnerabilities[j]
+		if a.CVSSScore != b.CVSSScore {
+			return a.CVSSScore > b.CVSSScore
+		}
+		return a.ID > b.ID
+	})
+	return result
+}
+
+// affects reports whether version falls within the rule
+func (r matchRule) affects(version string) bool {
+	if r.startIncluding != "" || r.startExcluding != "" || r.endIncluding != "" || r.endExcluding != "" {
+		if r.startIncluding != "" && CompareVersions(version, r.startIncluding) < 0 {
+			return false
+		}
+		if r.startExcluding != "" && CompareVersions(version, r.startExcluding) <= 0 {
+			return false
+		}
+		if r.endIncluding != "" && CompareVersions(version, r.endIncluding) > 0 {
+			return false
+		}
+		if r.endExcluding != "" && CompareVersions(version, r.endExcluding) >= 0 {
+			return false
+		}
+		return true
+	}
+
+	switch r.version {
+	case "*", "":
+		// Every version is affected
+		return true
+	case "-":
+		// Not applicable, the CPE doesn't describe a version
+		return false
+	}
+
+	// Suffixes such as OpenSSH's p1 are published in the update field
+	if r.update != "*" && r.update != "-" && r.update != "" {
+		return CompareVersions(version, r.version+r.update) == 0
+	}
+	if CompareVersions(version, r.version) == 0 {
+		return true
+	}
+	return CompareVersions(releasePattern.FindString(version), r.version) == 0
+}
+
+// cpeName builds the CPE 2.3 name of an application version
+func cpeName(key, version string) string {
+	return "cpe:2.3:a:" + key + ":" + version + ":*:*:*:*:*:*:*"
+}
+
+// unescape removes the CPE 2.3 quoting from a field value
+func unescape(value string) string {
+	return strings.ReplaceAll(value, "\\", "")
+}
@@ -0,0 +1,428 @@
+// internal/cve/feed.go
+package cve
+
+import (
+	"backend/internal/datadir"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// maxDescriptionLength limits the description kept in memory for each CVE
+const maxDescriptionLength = 500
+
+// FeedFile describes a feed file loaded into the database
+type FeedFile struct {
+	Name            string    `json:"name"`
+	Kind            string    `json:"kind"` // nvd-1.1, nvd-2.0 or cpe-dictionary
+	Size            int64     `json:"size"`
+	ModifiedAt      time.Time `json:"modified_at"`
+	Vulnerabilities int       `json:"vulnerabilities"`
+	Products        int       `json:"products,omitempty"`
+}
+
+// FeedDir returns the directory CVE feeds are loaded from, set through CVE_FEED_DIR
+func FeedDir() string {
+	return datadir.Dir("CVE_FEED_DIR", "nvd")
+}
+
+// IsFeedFile reports whether a file name is an NVD feed (.json) or CPE dictionary (.xml), gzip compressed or not
+var IsFeedFile = datadir.Extensions(".json", ".xml")
+
+// Load reads every NVD JSON feed (1.1 feeds or 2.0 API exports) and CPE dictionary in dir.
+// Files may be gzip compressed.
+func Load(dir string) (*Database, error) {
+	files, err := datadir.Files(dir, IsFeedFile)
+	if err != nil {
+		return nil, err
+	}
+
+	db := newDatabase()
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		feedFile := FeedFile{
+			Name:       file.Name(),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		}
+		if err := db.loadFile(filepath.Join(dir, file.Name()), &feedFile); err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", file.Name(), err)
+		}
+		db.Files = append(db.Files, feedFile)
+	}
+
+	if len(db.vulnerabilities) == 0 {
+		return nil, fmt.Errorf("no CVE feeds found in %s", dir)
+	}
+
+	db.LoadedAt = time.Now()
+	return db, nil
+}
+
+// loadFile loads a single feed or dictionary file, decompressing it when needed
+func (db *Database) loadFile(path string, feedFile *FeedFile) error {
+	reader, name, err := datadir.Open(path)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	if strings.HasSuffix(name, ".xml") {
+		feedFile.Kind = "cpe-dictionary"
+		feedFile.Products, err = db.loadDictionary(reader)
+		return err
+	}
+	return db.loadFeed(reader, feedFile)
+}
+
+// loadFeed streams the CVE items of an NVD JSON feed, detecting the format from its top level key
+func (db *Database) loadFeed(reader io.Reader, feedFile *FeedFile) error {
+	decoder := json.NewDecoder(reader)
+
+	if token, err := decoder.Token(); err != nil {
+		return err
+	} else if delim, ok := token.(json.Delim); !ok || delim != '{' {
+		return fmt.Errorf("expected a JSON object")
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := token.(string)
+
+		if key != "CVE_Items" && key != "vulnerabilities" {
+			// Skip metadata such as CVE_data_timestamp or resultsPerPage
+			var skip json.RawMessage
+			if err := decoder.Decode(&skip); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if token, err := decoder.Token(); err != nil {
+			return err
+		} else if delim, ok := token.(json.Delim); !ok || delim != '[' {
+			return fmt.Errorf("expected %s to be an array", key)
+		}
+
+		for decoder.More() {
+			var vulnerability *Vulnerability
+			var rules []matchRule
+
+			if key == "CVE_Items" {
+				feedFile.Kind = "nvd-1.1"
+				var item nvdItem
+				if err := decoder.Decode(&item); err != nil {
+					return err
+				}
+				vulnerability, rules = item.convert()
+			} else {
+				feedFile.Kind = "nvd-2.0"
+				var item nvdVulnerability
+				if err := decoder.Decode(&item); err != nil {
+					return err
+				}
+				vulnerability, rules = item.CVE.convert()
+			}
+
+			if vulnerability != nil && db.add(vulnerability, rules) {
+				feedFile.Vulnerabilities++
+			}
+		}
+
+		if _, err := decoder.Token(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadDictionary indexes the products of an NVD CPE dictionary so product names can be resolved to vendors
+func (db *Database) loadDictionary(reader io.Reader) (int, error) {
+	decoder := xml.NewDecoder(reader)
+	count := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return count, nil
+		}
+		if err != nil {
+			return count, err
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok || element.Name.Local != "cpe23-item" {
+			continue
+		}
+		for _, attr := range element.Attr {
+			if attr.Name.Local != "name" {
+				continue
+			}
+			if cpe, ok := ParseCPE(attr.Value); ok && db.addProduct(cpe) {
+				count++
+			}
+		}
+	}
+}
+
+// nvdItem is a CVE item of an NVD 1.1 JSON feed
+type nvdItem struct {
+	CVE struct {
+		Meta struct {
+			ID string `json:"ID"`
+		} `json:"CVE_data_meta"`
+		References struct {
+			Data []struct {
+				URL string `json:"url"`
+			} `json:"reference_data"`
+		} `json:"references"`
+		Description struct {
+			Data []nvdDescription `json:"description_data"`
+		} `json:"description"`
+		ProblemType struct {
+			Data []struct {
+				Description []nvdDescription `json:"description"`
+			} `json:"problemtype_data"`
+		} `json:"problemtype"`
+	} `json:"cve"`
+	Configurations struct {
+		Nodes []nvdNode `json:"nodes"`
+	} `json:"configurations"`
+	Impact struct {
+		V3 struct {
+			CVSS struct {
+				BaseScore    float64 `json:"baseScore"`
+				VectorString string  `json:"vectorString"`
+				Version      string  `json:"version"`
+			} `json:"cvssV3"`
+		} `json:"baseMetricV3"`
+		V2 struct {
+			CVSS struct {
+				BaseScore    float64 `json:"baseScore"`
+				VectorString string  `json:"vectorString"`
+			} `json:"cvssV2"`
+		} `json:"baseMetricV2"`
+	} `json:"impact"`
+	Published    string `json:"publishedDate"`
+	LastModified string `json:"lastModifiedDate"`
+}
+
+// nvdNode is a configuration node of an NVD 1.1 feed, nodes nest through children
+type nvdNode struct {
+	Children []nvdNode    `json:"children"`
+	CPEMatch []nvdCPEItem `json:"cpe_match"`
+}
+
+// nvdCPEItem is a CPE match of an NVD 1.1 feed
+type nvdCPEItem struct {
+	Vulnerable            bool   `json:"vulnerable"`
+	CPE                   string `json:"cpe23Uri"`
+	VersionStartIncluding string `json:"versionStartIncluding"`
+	VersionStartExcluding string `json:"versionStartExcluding"`
+	VersionEndIncluding   string `json:"versionEndIncluding"`
+	VersionEndExcluding   string `json:"versionEndExcluding"`
+}
+
+type nvdDescription struct {
+	Lang  string `json:"lang"`
+	Value string `json:"value"`
+}
+
+func (item nvdItem) convert() (*Vulnerability, []matchRule) {
+	vulnerability := &Vulnerability{
+		ID:           item.CVE.Meta.ID,
+		Description:  englishDescription(item.CVE.Description.Data),
+		Published:    item.Published,
+		LastModified: item.LastModified,
+	}
+	if vulnerability.ID == "" || strings.HasPrefix(vulnerability.Description, "** REJECT **") {
+		return nil, nil
+	}
+
+	if v3 := item.Impact.V3.CVSS; v3.VectorString != "" {
+		vulnerability.CVSSScore = v3.BaseScore
+		vulnerability.CVSSVersion = v3.Version
+		vulnerability.CVSSVector = v3.VectorString
+	} else if v2 := item.Impact.V2.CVSS; v2.VectorString != "" {
+		vulnerability.CVSSScore = v2.BaseScore
+		vulnerability.CVSSVersion = "2.0"
+		vulnerability.CVSSVector = v2.VectorString
+	}
+
+	for _, reference := range item.CVE.References.Data {
+		vulnerability.References = append(vulnerability.References, reference.URL)
+	}
+
+	for _, problemType := range item.CVE.ProblemType.Data {
+		vulnerability.CWEs = appendWeaknesses(vulnerability.CWEs, problemType.Description)
+	}
+
+	var rules []matchRule
+	var walk func(nodes []nvdNode)
+	walk = func(nodes []nvdNode) {
+		for _, node := range nodes {
+			for _, match := range node.CPEMatch {
+				if !match.Vulnerable {
+					continue
+				}
+				if rule, ok := newMatchRule(match.CPE, match.VersionStartIncluding, match.VersionStartExcluding,
+					match.VersionEndIncluding, match.VersionEndExcluding); ok {
+					rules = append(rules, rule)
+				}
+			}
+			walk(node.Children)
+		}
+	}
+	walk(item.Configurations.Nodes)
+
+	return vulnerability, rules
+}
+
+// nvdVulnerability is an entry of an NVD 2.0 API response or feed
+type nvdVulnerability struct {
+	CVE nvdCVE `json:"cve"`
+}
+
+type nvdCVE struct {
+	ID           string           `json:"id"`
+	Published    string           `json:"published"`
+	LastModified string           `json:"lastModified"`
+	Status       string           `json:"vulnStatus"`
+	Descriptions []nvdDescription `json:"descriptions"`
+	Metrics      struct {
+		V40 []nvdMetric `json:"cvssMetricV40"`
+		V31 []nvdMetric `json:"cvssMetricV31"`
+		V30 []nvdMetric `json:"cvssMetricV30"`
+		V2  []nvdMetric `json:"cvssMetricV2"`
+	} `json:"metrics"`
+	Configurations []struct {
+		Nodes []struct {
+			CPEMatch []struct {
+				Vulnerable            bool   `json:"vulnerable"`
+				Criteria              string `json:"criteria"`
+				VersionStartIncluding string `json:"versionStartIncluding"`
+				VersionStartExcluding string `json:"versionStartExcluding"`
+				VersionEndIncluding   string `json:"versionEndIncluding"`
+				VersionEndExcluding   string `json:"versionEndExcluding"`
+			} `json:"cpeMatch"`
+		} `json:"nodes"`
+	} `json:"configurations"`
+	References []struct {
+		URL string `json:"url"`
+	} `json:"references"`
+	Weaknesses []struct {
+		Description []nvdDescription `json:"description"`
+	} `json:"weaknesses"`
+}
+
+type nvdMetric struct {
+	Type     string `json:"type"`
+	CVSSData struct {
+		Version      string  `json:"version"`
+		BaseScore    float64 `json:"baseScore"`
+		VectorString string  `json:"vectorString"`
+	} `json:"cvssData"`
+}
+
+func (item nvdCVE) convert() (*Vulnerability, []matchRule) {
+	if item.ID == "" || item.Status == "Rejected" {
+		return nil, nil
+	}
+
+	vulnerability := &Vulnerability{
+		ID:           item.ID,
+		Description:  englishDescription(item.Descriptions),
+		Published:    item.Published,
+		LastModified: item.LastModified,
+	}
+
+	// Prefer the newest CVSS version, and the primary score from NVD within it
+	for _, metrics := range [][]nvdMetric{item.Metrics.V40, item.Metrics.V31, item.Metrics.V30, item.Metrics.V2} {
+		if len(metrics) == 0 {
+			continue
+		}
+		metric := metrics[0]
+		for _, candidate := range metrics {
+			if candidate.Type == "Primary" {
+				metric = candidate
+				break
+			}
+		}
+		vulnerability.CVSSScore = metric.CVSSData.BaseScore
+		vulnerability.CVSSVersion = metric.CVSSData.Version
+		vulnerability.CVSSVector = metric.CVSSData.VectorString
+		break
+	}
+
+	for _, reference := range item.References {
+		vulnerability.References = append(vulnerability.References, reference.URL)
+	}
+
+	for _, weakness := range item.Weaknesses {
+		vulnerability.CWEs = appendWeaknesses(vulnerability.CWEs, weakness.Description)
+	}
+
+	var rules []matchRule
+	for _, configuration := range item.Configurations {
+		for _, node := range configuration.Nodes {
+			for _, match := range node.CPEMatch {
+				if !match.Vulnerable {
+					continue
+				}
+				if rule, ok := newMatchRule(match.Criteria, match.VersionStartIncluding, match.VersionStartExcluding,
+					match.VersionEndIncluding, match.VersionEndExcluding); ok {
+					rules = append(rules, rule)
+				}
+			}
+		}
+	}
+
+	return vulnerability, rules
+}
+
+// englishDescription returns the English description, truncated to maxDescriptionLength
+func englishDescription(descriptions []nvdDescription) string {
+	description := ""
+	for _, d := range descriptions {
+		if d.Lang == "en" {
+			description = d.Value
+			break
+		}
+	}
+	if description == "" && len(descriptions) > 0 {
+		description = descriptions[0].Value
+	}
+	if len(description) > maxDescriptionLength {
+		description = description[:maxDescriptionLength] + "..."
+	}
+	return description
+}
+
+// appendWeaknesses adds the CWE ids of a problem type or weakness entry, skipping the
+// NVD-CWE-Other and NVD-CWE-noinfo placeholders
+func appendWeaknesses(cwes []string, descriptions []nvdDescription) []string {
+	for _, d := range descriptions {
+		if !strings.HasPrefix(d.Value, "CWE-") {
+			continue
+		}
+		duplicate := false
+		for _, cwe := range cwes {
+			duplicate = duplicate || cwe == d.Value
+		}
+		if !duplicate {
+			cwes = append(cwes, d.Value)
+		}
+	}
+	return cwes
+}
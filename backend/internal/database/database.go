@@ -28,7 +28,7 @@ func Migrate(db *gorm.DB) error {
 		return fmt.Errorf("failed to enable UUID extension: %w", err)
 	}
 
-	return db.AutoMigrate(
+	err = db.AutoMigrate(
 		&models.Project{},
 		&models.Target{},
 		&models.ScanConfig{},
@@ -45,7 +45,33 @@ func Migrate(db *gorm.DB) error {
 		&models.Service{},
 		&models.DNSRecord{},
 		&models.Certificate{},
+		&models.NucleiTemplate{},
 	)
+	if err != nil {
+		return err
+	}
+
+	return migrateScannerTypeCheck(db)
+}
+
+// migrateScannerTypeCheck recreates the scanner type check of scan configs. AutoMigrate only creates
+// check constraints along with their table, so databases created before a scanner type was added
+// would otherwise keep rejecting it.
+func migrateScannerTypeCheck(db *gorm.DB) error {
+	const constraint = "chk_scan_configs_scanner_type"
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		migrator := tx.Migrator()
+		if migrator.HasConstraint(&models.ScanConfig{}, constraint) {
+			if err := migrator.DropConstraint(&models.ScanConfig{}, constraint); err != nil {
+				return fmt.Errorf("failed to drop %s: %w", constraint, err)
+			}
+		}
+		if err := migrator.CreateConstraint(&models.ScanConfig{}, constraint); err != nil {
+			return fmt.Errorf("failed to create %s: %w", constraint, err)
+		}
+		return nil
+	})
 }
 
 func enableUUIDExtension(db *gorm.DB) error {
@@ -7,4 +7,97 @@ VALUES
         '{"scan_type": "service", "port_range": "1-65535", "timing": "4"}'::jsonb,
         true,
         current_timestamp
-    );
\ No newline at end of file
+    ),
+    (
+        'Native Port Scan',
+        'portscan',
+        '{"port_range": "1-1000", "udp_port_range": "53,123,161", "concurrency": 100, "rate": 500, "timeout": 1500}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'NSE Script Scan',
+        'nmap',
+        '{"scan_type": "service", "port_range": "21,22,80,139,443,445,8080,8443", "scripts": ["ssl-enum-ciphers", "http-title", "smb-security-mode"], "os_detection": true}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'Email Security',
+        'dns',
+        '{"record_types": ["MX", "TXT"], "zone_transfer": false, "email_security": true}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'Subdomain Takeover',
+        'takeover',
+        '{"check_http": true, "timeout": 10}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'TLS Inspection',
+        'tls',
+        '{"ports": "443,8443", "enumerate_ciphers": true, "expiry_warning_days": 30}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'Ping Sweep',
+        'ping',
+        '{"method": "auto", "count": 2, "timeout": 2, "tcp_ports": "80,443,22,445,3389"}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    -- Example of mapping the JSON lines output of a tool, inactive since inhouse-scan is a placeholder.
+    -- Point command at a real executable listed in COMMAND_SCANNER_ALLOWLIST before activating it.
+    (
+        'In-house Command (example)',
+        'command',
+        '{"command": "inhouse-scan --host {{host}} --json", "output_format": "jsonl", "timeout": 600, "mapping": {"findings": {"title": "{{name}}", "severity": "{{severity}}", "finding_type": "inhouse_{{check}}", "description": "{{description}}"}}}'::jsonb,
+        false,
+        current_timestamp
+    ),
+    (
+        'Web Crawler',
+        'crawler',
+        '{"max_depth": 3, "max_pages": 200, "rate_limit": 10, "parse_scripts": true}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'Content Discovery',
+        'contentdiscovery',
+        '{"extensions": ["php", "bak"], "concurrency": 20, "auto_calibrate": true}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'Banner Grab',
+        'banner',
+        '{"timeout": 5}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'SSH Audit',
+        'ssh',
+        '{"timeout": 10, "auth_methods": true}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'SMTP Audit',
+        'smtp',
+        '{"ports": "25,465,587", "check_relay": true, "check_enumeration": true}'::jsonb,
+        true,
+        current_timestamp
+    ),
+    (
+        'Virtual Host Discovery',
+        'vhost',
+        '{"include_project_domains": true, "concurrency": 20}'::jsonb,
+        true,
+        current_timestamp
+    );
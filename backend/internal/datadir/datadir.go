@@ -0,0 +1,111 @@
+// internal/datadir/datadir.go
+package datadir
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Dir returns the directory set through envVar, or ~/.zecas/<name> when it isn't set
+func Dir(envVar string, name string) string {
+	if dir := os.Getenv(envVar); dir != "" {
+		return dir
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return filepath.Join("/opt/zecas", name) // Fallback
+	}
+	return filepath.Join(homeDir, ".zecas", name)
+}
+
+// Extensions returns a match for file names ending in one of the extensions, gzip compressed or not
+func Extensions(extensions ...string) func(name string) bool {
+	return func(name string) bool {
+		name = strings.TrimSuffix(strings.ToLower(name), ".gz")
+		for _, extension := range extensions {
+			if strings.HasSuffix(name, extension) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Files lists the files in dir whose name is accepted by match, sorted by name
+func Files(dir string, match func(name string) bool) ([]os.DirEntry, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []os.DirEntry
+	for _, entry := range entries {
+		if !entry.IsDir() && match(entry.Name()) {
+			files = append(files, entry)
+		}
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	return files, nil
+}
+
+// Fingerprint identifies the current contents of dir by the names, sizes and modification times
+// of the files accepted by match
+func Fingerprint(dir string, match func(name string) bool) (string, error) {
+	files, err := Files(dir, match)
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.New()
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(hash, "%s:%d:%d\n", file.Name(), info.Size(), info.ModTime().UnixNano())
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// gzipFile closes the decompressor along with the file it reads
+type gzipFile struct {
+	*gzip.Reader
+	file *os.File
+}
+
+func (f *gzipFile) Close() error {
+	f.Reader.Close()
+	return f.file.Close()
+}
+
+// Open opens a file, decompressing it when its name ends in .gz. Returns the lower case file
+// name without the .gz suffix, so callers can pick a parser from the extension.
+func Open(path string) (io.ReadCloser, string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+
+	name := strings.ToLower(filepath.Base(path))
+	if !strings.HasSuffix(name, ".gz") {
+		return file, name, nil
+	}
+
+	gzipReader, err := gzip.NewReader(file)
+	if err != nil {
+		file.Close()
+		return nil, "", err
+	}
+	return &gzipFile{Reader: gzipReader, file: file}, strings.TrimSuffix(name, ".gz"), nil
+}
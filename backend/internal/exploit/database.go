@@ -0,0 +1,180 @@
+// internal/exploit/database.go
+package exploit
+
+import (
+	"backend/internal/datadir"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of data files
+const (
+	KindEPSS = "epss"
+	KindKEV  = "kev"
+)
+
+// DataFile describes a data file loaded into the database
+type DataFile struct {
+	Name       string    `json:"name"`
+	Kind       string    `json:"kind"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	Entries    int       `json:"entries"`
+	ScoreDate  string    `json:"score_date,omitempty"` // Date the EPSS scores were published
+}
+
+// Score is what the loaded data files know about a CVE
+type Score struct {
+	HasEPSS        bool
+	EPSS           float64 // Probability of exploitation in the next 30 days
+	Percentile     float64
+	KnownExploited bool
+	DateAdded      string // Date the CVE was added to the KEV catalog
+	Ransomware     bool   // Known to be used in ransomware campaigns
+}
+
+// kevEntry is a CVE listed in the KEV catalog
+type kevEntry struct {
+	dateAdded  string
+	ransomware bool
+}
+
+// epssEntry is the EPSS score of a CVE
+type epssEntry struct {
+	score      float64
+	percentile float64
+}
+
+// Database holds the EPSS scores and KEV entries of every loaded file, indexed by CVE id
+type Database struct {
+	Files    []DataFile
+	LoadedAt time.Time
+
+	epss map[string]epssEntry
+	kev  map[string]kevEntry
+}
+
+// DataDir returns the directory EPSS and KEV files are loaded from, set through EXPLOIT_DATA_DIR
+func DataDir() string {
+	return datadir.Dir("EXPLOIT_DATA_DIR", "exploits")
+}
+
+// IsDataFile reports whether a file name is an EPSS or KEV file (.csv or .json), gzip compressed or not
+var IsDataFile = datadir.Extensions(".csv", ".json")
+
+// Load reads every data file in dir. CSV files are recognized by their header: the daily EPSS
+// scores from FIRST or the CISA KEV catalog. JSON files hold the KEV catalog. Files may be gzip compressed.
+// When several EPSS files are present, the scores of the most recent one win.
+func Load(dir string) (*Database, error) {
+	files, err := datadir.Files(dir, IsDataFile)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &Database{
+		epss: make(map[string]epssEntry),
+		kev:  make(map[string]kevEntry),
+	}
+
+	type epssFile struct {
+		index  int
+		date   string
+		scores map[string]epssEntry
+	}
+	var epssFiles []epssFile
+
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := loadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", file.Name(), err)
+		}
+
+		dataFile := DataFile{
+			Name:       file.Name(),
+			Kind:       parsed.kind,
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+			ScoreDate:  parsed.scoreDate,
+		}
+		switch parsed.kind {
+		case KindEPSS:
+			dataFile.Entries = len(parsed.epss)
+			epssFiles = append(epssFiles, epssFile{index: len(db.Files), date: parsed.scoreDate, scores: parsed.epss})
+		case KindKEV:
+			dataFile.Entries = len(parsed.kev)
+			for id, entry := range parsed.kev {
+				db.kev[id] = entry
+			}
+		}
+		db.Files = append(db.Files, dataFile)
+	}
+
+	// Apply the oldest scores first so newer files overwrite them
+	sort.SliceStable(epssFiles, func(i, j int) bool {
+		return epssFiles[i].date < epssFiles[j].date
+	})
+	for _, file := range epssFiles {
+		for id, entry := range file.scores {
+			db.epss[id] = entry
+		}
+	}
+
+	if len(db.epss) == 0 && len(db.kev) == 0 {
+		return nil, fmt.Errorf("no EPSS scores or KEV entries found in %s", dir)
+	}
+
+	db.LoadedAt = time.Now()
+	return db, nil
+}
+
+// loadFile reads a single data file, decompressing it when needed
+func loadFile(path string) (*parsedFile, error) {
+	reader, name, err := datadir.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	if strings.HasSuffix(name, ".json") {
+		return parseKEVJSON(reader)
+	}
+	return parseCSV(reader)
+}
+
+// EPSSCount returns the number of CVEs with an EPSS score
+func (db *Database) EPSSCount() int {
+	return len(db.epss)
+}
+
+// KEVCount returns the number of CVEs in the KEV catalog
+func (db *Database) KEVCount() int {
+	return len(db.kev)
+}
+
+// Lookup returns what is known about a CVE, and whether it appears in any loaded file
+func (db *Database) Lookup(cveID string) (Score, bool) {
+	id := strings.ToUpper(strings.TrimSpace(cveID))
+
+	var score Score
+	epss, hasEPSS := db.epss[id]
+	if hasEPSS {
+		score.HasEPSS = true
+		score.EPSS = epss.score
+		score.Percentile = epss.percentile
+	}
+	kev, known := db.kev[id]
+	if known {
+		score.KnownExploited = true
+		score.DateAdded = kev.dateAdded
+		score.Ransomware = kev.ransomware
+	}
+	return score, hasEPSS || known
+}
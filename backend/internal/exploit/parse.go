@@ -0,0 +1,148 @@
+// internal/exploit/parse.go
+package exploit
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// parsedFile holds the entries read from a single data file
+type parsedFile struct {
+	kind      string
+	scoreDate string
+	epss      map[string]epssEntry
+	kev       map[string]kevEntry
+}
+
+// kevCatalog holds the fields of the CISA KEV JSON feed
+type kevCatalog struct {
+	Vulnerabilities []struct {
+		CVEID      string `json:"cveID"`
+		DateAdded  string `json:"dateAdded"`
+		Ransomware string `json:"knownRansomwareCampaignUse"`
+	} `json:"vulnerabilities"`
+}
+
+// parseKEVJSON reads the KEV catalog as published by CISA in known_exploited_vulnerabilities.json
+func parseKEVJSON(reader io.Reader) (*parsedFile, error) {
+	var catalog kevCatalog
+	if err := json.NewDecoder(reader).Decode(&catalog); err != nil {
+		return nil, err
+	}
+	if catalog.Vulnerabilities == nil {
+		return nil, errors.New("not a KEV catalog, the vulnerabilities list is missing")
+	}
+
+	parsed := &parsedFile{kind: KindKEV, kev: make(map[string]kevEntry)}
+	for _, vulnerability := range catalog.Vulnerabilities {
+		addKEV(parsed, vulnerability.CVEID, vulnerability.DateAdded, vulnerability.Ransomware)
+	}
+	return parsed, nil
+}
+
+// parseCSV reads the daily EPSS scores (cve,epss,percentile, preceded by a #model_version comment)
+// or the KEV catalog in its CSV form, recognized by their header
+func parseCSV(reader io.Reader) (*parsedFile, error) {
+	buffered := bufio.NewReader(reader)
+
+	// The EPSS files start with a comment holding the model version and score date
+	scoreDate := ""
+	for {
+		peek, err := buffered.Peek(1)
+		if err != nil || peek[0] != '#' {
+			break
+		}
+		line, err := buffered.ReadString('\n')
+		for _, field := range strings.Split(strings.TrimSpace(strings.TrimPrefix(line, "#")), ",") {
+			if key, value, ok := strings.Cut(field, ":"); ok && strings.TrimSpace(key) == "score_date" {
+				scoreDate, _, _ = strings.Cut(strings.TrimSpace(value), "T")
+			}
+		}
+		if err != nil {
+			break
+		}
+	}
+
+	csvReader := csv.NewReader(buffered)
+	csvReader.FieldsPerRecord = -1
+	csvReader.ReuseRecord = true
+
+	header, err := csvReader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read header: %v", err)
+	}
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var parsed *parsedFile
+	switch {
+	case hasColumns(columns, "cve", "epss"):
+		parsed = &parsedFile{kind: KindEPSS, scoreDate: scoreDate, epss: make(map[string]epssEntry)}
+	case hasColumns(columns, "cveid", "dateadded"):
+		parsed = &parsedFile{kind: KindKEV, kev: make(map[string]kevEntry)}
+	default:
+		return nil, errors.New("unrecognized CSV file, expected EPSS scores or the KEV catalog")
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if parsed.kind == KindKEV {
+			addKEV(parsed, field(record, "cveid"), field(record, "dateadded"), field(record, "knownransomwarecampaignuse"))
+			continue
+		}
+
+		id := strings.ToUpper(field(record, "cve"))
+		score, err := strconv.ParseFloat(field(record, "epss"), 64)
+		if !strings.HasPrefix(id, "CVE-") || err != nil {
+			continue
+		}
+		percentile, _ := strconv.ParseFloat(field(record, "percentile"), 64)
+		parsed.epss[id] = epssEntry{score: score, percentile: percentile}
+	}
+
+	return parsed, nil
+}
+
+// addKEV records a CVE of the KEV catalog
+func addKEV(parsed *parsedFile, cveID string, dateAdded string, ransomware string) {
+	id := strings.ToUpper(strings.TrimSpace(cveID))
+	if !strings.HasPrefix(id, "CVE-") {
+		return
+	}
+	parsed.kev[id] = kevEntry{
+		dateAdded:  strings.TrimSpace(dateAdded),
+		ransomware: strings.EqualFold(strings.TrimSpace(ransomware), "known"),
+	}
+}
+
+// hasColumns reports whether a CSV header contains every column
+func hasColumns(columns map[string]int, names ...string) bool {
+	for _, name := range names {
+		if _, ok := columns[name]; !ok {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,253 @@
+// internal/geoip/database.go
+package geoip
+
+import (
+	"backend/internal/datadir"
+	"fmt"
+	"net/netip"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatabaseFile describes a database file loaded for lookups
+type DatabaseFile struct {
+	Name         string     `json:"name"`
+	Kind         string     `json:"kind"` // mmdb or ip2asn
+	DatabaseType string     `json:"database_type,omitempty"`
+	Size         int64      `json:"size"`
+	ModifiedAt   time.Time  `json:"modified_at"`
+	BuiltAt      *time.Time `json:"built_at,omitempty"`
+	Ranges       int        `json:"ranges,omitempty"`
+}
+
+// Result is what the databases know about an IP address
+type Result struct {
+	ASN          uint   `json:"asn,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Country      string `json:"country,omitempty"` // ISO 3166-1 alpha-2 code
+	CountryName  string `json:"country_name,omitempty"`
+	Network      string `json:"network,omitempty"`
+}
+
+// Database answers lookups from every loaded file, mmdb databases take precedence over ip2asn tables per field
+type Database struct {
+	Files    []DatabaseFile
+	LoadedAt time.Time
+
+	readers []*mmdbReader
+	tables  []*asnTable
+}
+
+// DatabaseDir returns the directory databases are loaded from, set through GEOIP_DB_DIR
+func DatabaseDir() string {
+	return datadir.Dir("GEOIP_DB_DIR", "geoip")
+}
+
+// IsDatabaseFile reports whether a file name is an mmdb database or ip2asn table
+func IsDatabaseFile(name string) bool {
+	return fileKind(name) != ""
+}
+
+// fileKind returns the kind of database a file name refers to, or an empty string for other files.
+// mmdb files are read into memory as they are, so only ip2asn tables may be compressed.
+func fileKind(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(name, ".mmdb"):
+		return "mmdb"
+	case strings.HasSuffix(strings.TrimSuffix(name, ".gz"), ".tsv"):
+		return "ip2asn"
+	default:
+		return ""
+	}
+}
+
+// Load reads every MaxMind format database (.mmdb) and ip2asn table (.tsv, optionally gzip compressed) in dir
+func Load(dir string) (*Database, error) {
+	files, err := datadir.Files(dir, IsDatabaseFile)
+	if err != nil {
+		return nil, err
+	}
+
+	db := &Database{}
+	for _, file := range files {
+		info, err := file.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		path := filepath.Join(dir, file.Name())
+		databaseFile := DatabaseFile{
+			Name:       file.Name(),
+			Kind:       fileKind(file.Name()),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		}
+
+		switch databaseFile.Kind {
+		case "mmdb":
+			reader, err := openMMDB(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s: %v", file.Name(), err)
+			}
+			databaseFile.DatabaseType = reader.databaseType
+			if reader.buildEpoch > 0 {
+				builtAt := time.Unix(int64(reader.buildEpoch), 0).UTC()
+				databaseFile.BuiltAt = &builtAt
+			}
+			db.readers = append(db.readers, reader)
+		case "ip2asn":
+			table, err := loadASNTable(path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load %s: %v", file.Name(), err)
+			}
+			databaseFile.Ranges = len(table.ranges)
+			db.tables = append(db.tables, table)
+		}
+		db.Files = append(db.Files, databaseFile)
+	}
+
+	if len(db.Files) == 0 {
+		return nil, fmt.Errorf("no GeoIP or ASN databases found in %s", dir)
+	}
+
+	db.LoadedAt = time.Now()
+	return db, nil
+}
+
+// Lookup returns what the databases know about an IP address, or nil when none of them cover it
+func (db *Database) Lookup(ip string) (*Result, error) {
+	addr, err := netip.ParseAddr(strings.TrimSpace(ip))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IP address %q", ip)
+	}
+	addr = addr.Unmap()
+
+	result := &Result{}
+	countryNetwork := ""
+
+	for _, reader := range db.readers {
+		record, network, err := reader.lookup(addr)
+		if err != nil {
+			return nil, err
+		}
+		if record == nil {
+			continue
+		}
+
+		asn, organization := recordASN(record)
+		country, countryName := recordCountry(record)
+		if asn != 0 && result.ASN == 0 {
+			result.ASN = asn
+			result.Network = network.String()
+		}
+		if organization != "" && result.Organization == "" {
+			result.Organization = organization
+		}
+		if country != "" && result.Country == "" {
+			result.Country = country
+			countryNetwork = network.String()
+		}
+		if countryName != "" && result.CountryName == "" {
+			result.CountryName = countryName
+		}
+	}
+
+	for _, table := range db.tables {
+		row := table.lookup(addr)
+		if row == nil {
+			continue
+		}
+		if result.ASN == 0 {
+			result.ASN = row.asn
+			result.Network = row.network(addr).String()
+		}
+		if result.Organization == "" && row.asn == result.ASN {
+			result.Organization = row.organization
+		}
+		if result.Country == "" {
+			result.Country = row.country
+		}
+	}
+
+	// Country databases are the only source of a network for addresses without an AS
+	if result.Network == "" {
+		result.Network = countryNetwork
+	}
+
+	if *result == (Result{}) {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// recordASN reads the AS number and organization of an mmdb record, in the MaxMind or IPinfo layout
+func recordASN(record map[string]interface{}) (uint, string) {
+	var asn uint
+	switch value := record["autonomous_system_number"].(type) {
+	case uint64:
+		asn = uint(value)
+	case string:
+		asn = ParseASN(value)
+	}
+	if asn == 0 {
+		switch value := record["asn"].(type) {
+		case uint64:
+			asn = uint(value)
+		case string:
+			asn = ParseASN(value)
+		}
+	}
+
+	for _, key := range []string{"autonomous_system_organization", "as_name", "organization", "isp"} {
+		if organization, ok := record[key].(string); ok && organization != "" {
+			return asn, organization
+		}
+	}
+	return asn, ""
+}
+
+// recordCountry reads the country code and English name of an mmdb record, in the MaxMind or IPinfo layout
+func recordCountry(record map[string]interface{}) (string, string) {
+	for _, key := range []string{"country", "registered_country"} {
+		country, ok := record[key].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		code, _ := country["iso_code"].(string)
+		name := ""
+		if names, ok := country["names"].(map[string]interface{}); ok {
+			name, _ = names["en"].(string)
+		}
+		if code != "" {
+			return strings.ToUpper(code), name
+		}
+	}
+
+	// IPinfo stores the code in country_code, or in country next to country_name
+	code, _ := record["country_code"].(string)
+	name, _ := record["country_name"].(string)
+	if country, ok := record["country"].(string); ok {
+		if code == "" && len(country) == 2 {
+			code = country
+		} else if name == "" {
+			name = country
+		}
+	}
+	return strings.ToUpper(code), name
+}
+
+// ParseASN parses an AS number written as "AS13335" or "13335", returning 0 when it's invalid
+func ParseASN(value string) uint {
+	value = strings.TrimSpace(value)
+	if len(value) > 2 && strings.EqualFold(value[:2], "AS") {
+		value = value[2:]
+	}
+	asn, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0
+	}
+	return uint(asn)
+}
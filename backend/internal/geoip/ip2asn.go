@@ -0,0 +1,159 @@
+// internal/geoip/ip2asn.go
+package geoip
+
+import (
+	"backend/internal/datadir"
+	"bufio"
+	"fmt"
+	"net/netip"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// asnRange is a row of an ip2asn table, an address range announced by an AS
+type asnRange struct {
+	start        netip.Addr
+	end          netip.Addr
+	asn          uint
+	country      string
+	organization string
+}
+
+// asnTable holds the ranges of an ip2asn TSV file, sorted by start address
+type asnTable struct {
+	ranges []asnRange
+}
+
+// loadASNTable reads an ip2asn TSV file (ip2asn-v4, -v6, -combined or -v4-u32), gzip compressed or not.
+// Each line holds the range start, range end, AS number, country code and AS description separated by tabs.
+func loadASNTable(path string) (*asnTable, error) {
+	reader, _, err := datadir.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	table := &asnTable{}
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Split(line, "\t")
+		if len(fields) < 5 {
+			return nil, fmt.Errorf("line %d: expected 5 tab separated fields", lineNumber)
+		}
+
+		start, err := parseRangeAddr(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		end, err := parseRangeAddr(fields[1])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", lineNumber, err)
+		}
+		asn, err := strconv.ParseUint(fields[2], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid AS number %q", lineNumber, fields[2])
+		}
+
+		// AS 0 marks ranges that aren't routed
+		if asn == 0 || start.Is4() != end.Is4() || end.Less(start) {
+			continue
+		}
+
+		country := strings.ToUpper(strings.TrimSpace(fields[3]))
+		if country == "NONE" {
+			country = ""
+		}
+
+		table.ranges = append(table.ranges, asnRange{
+			start:        start,
+			end:          end,
+			asn:          uint(asn),
+			country:      country,
+			organization: strings.TrimSpace(fields[4]),
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(table.ranges, func(i, j int) bool {
+		return table.ranges[i].start.Less(table.ranges[j].start)
+	})
+	return table, nil
+}
+
+// lookup returns the range containing addr, or nil when it isn't announced
+func (t *asnTable) lookup(addr netip.Addr) *asnRange {
+	addr = addr.Unmap()
+
+	// Find the last range starting at or before addr
+	index := sort.Search(len(t.ranges), func(i int) bool {
+		return addr.Less(t.ranges[i].start)
+	}) - 1
+	if index < 0 {
+		return nil
+	}
+
+	row := &t.ranges[index]
+	if row.start.Is4() != addr.Is4() || row.end.Less(addr) {
+		return nil
+	}
+	return row
+}
+
+// network returns the largest CIDR block within the range that contains addr, since
+// ip2asn ranges don't have to be aligned to a prefix
+func (r *asnRange) network(addr netip.Addr) netip.Prefix {
+	addr = addr.Unmap()
+	for bits := 0; bits <= addr.BitLen(); bits++ {
+		prefix, err := addr.Prefix(bits)
+		if err != nil {
+			break
+		}
+		if !prefix.Addr().Less(r.start) && !r.end.Less(lastAddr(prefix)) {
+			return prefix
+		}
+	}
+	return netip.PrefixFrom(addr, addr.BitLen())
+}
+
+// parseRangeAddr parses an address of an ip2asn range, the u32 variant stores IPv4 addresses as integers
+func parseRangeAddr(value string) (netip.Addr, error) {
+	value = strings.TrimSpace(value)
+	if number, err := strconv.ParseUint(value, 10, 32); err == nil {
+		return netip.AddrFrom4([4]byte{byte(number >> 24), byte(number >> 16), byte(number >> 8), byte(number)}), nil
+	}
+	addr, err := netip.ParseAddr(value)
+	if err != nil {
+		return netip.Addr{}, fmt.Errorf("invalid address %q", value)
+	}
+	return addr.Unmap(), nil
+}
+
+// lastAddr returns the highest address of a prefix
+func lastAddr(prefix netip.Prefix) netip.Addr {
+	addr := prefix.Masked().Addr()
+	bits := prefix.Bits()
+
+	if addr.Is4() {
+		b := addr.As4()
+		for i := bits; i < 32; i++ {
+			b[i/8] |= 1 << (7 - uint(i%8))
+		}
+		return netip.AddrFrom4(b)
+	}
+
+	b := addr.As16()
+	for i := bits; i < 128; i++ {
+		b[i/8] |= 1 << (7 - uint(i%8))
+	}
+	return netip.AddrFrom16(b)
+}
@@ -0,0 +1,390 @@
+// internal/geoip/mmdb.go
+package geoip
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"os"
+)
+
+// mmdbMetadataMarker precedes the metadata map at the end of a MaxMind DB file
+var mmdbMetadataMarker = []byte("\xab\xcd\xefMaxMind.com")
+
+// mmdbDataSectionSeparator is the size of the zeroed gap between the search tree and the data section
+const mmdbDataSectionSeparator = 16
+
+// mmdbMaxDepth bounds the nesting of decoded values so corrupt files can't recurse forever
+const mmdbMaxDepth = 32
+
+// errCorruptDatabase is returned when a MaxMind DB file doesn't follow the format
+var errCorruptDatabase = errors.New("corrupt MaxMind DB file")
+
+// mmdbReader reads MaxMind DB (mmdb) files as used by GeoLite2, GeoIP2, DB-IP and IPinfo
+type mmdbReader struct {
+	buffer       []byte
+	data         []byte
+	databaseType string
+	ipVersion    int
+	nodeCount    uint
+	recordSize   uint
+	buildEpoch   uint64
+	ipv4Start    uint
+	ipv4Depth    int
+}
+
+// openMMDB reads a MaxMind DB file into memory and parses its metadata
+func openMMDB(path string) (*mmdbReader, error) {
+	buffer, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// The metadata starts after the last marker, which is within the last 128KiB of the file
+	searchStart := 0
+	if len(buffer) > 128*1024 {
+		searchStart = len(buffer) - 128*1024
+	}
+	index := bytes.LastIndex(buffer[searchStart:], mmdbMetadataMarker)
+	if index < 0 {
+		return nil, fmt.Errorf("not a MaxMind DB file")
+	}
+	metadataStart := searchStart + index + len(mmdbMetadataMarker)
+
+	decoder := mmdbDecoder{data: buffer[metadataStart:]}
+	value, _, err := decoder.decode(0, 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metadata: %w", err)
+	}
+	metadata, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("invalid metadata")
+	}
+
+	reader := &mmdbReader{buffer: buffer}
+	reader.databaseType, _ = metadata["database_type"].(string)
+	reader.ipVersion = int(toUint(metadata["ip_version"]))
+	reader.nodeCount = uint(toUint(metadata["node_count"]))
+	reader.recordSize = uint(toUint(metadata["record_size"]))
+	reader.buildEpoch = toUint(metadata["build_epoch"])
+
+	if reader.recordSize != 24 && reader.recordSize != 28 && reader.recordSize != 32 {
+		return nil, fmt.Errorf("unsupported record size %d", reader.recordSize)
+	}
+
+	treeSize := reader.nodeCount * reader.recordSize / 4
+	if treeSize+mmdbDataSectionSeparator > uint(metadataStart) {
+		return nil, errCorruptDatabase
+	}
+	reader.data = buffer[treeSize+mmdbDataSectionSeparator : metadataStart-len(mmdbMetadataMarker)]
+
+	// IPv4 addresses live under ::/96 in IPv6 trees
+	if reader.ipVersion == 6 {
+		node := uint(0)
+		depth := 0
+		for ; depth < 96 && node < reader.nodeCount; depth++ {
+			if node, err = reader.readNode(node, 0); err != nil {
+				return nil, err
+			}
+		}
+		reader.ipv4Start = node
+		reader.ipv4Depth = depth
+	}
+
+	return reader, nil
+}
+
+// lookup returns the record of the network containing addr, and that network
+func (r *mmdbReader) lookup(addr netip.Addr) (map[string]interface{}, netip.Prefix, error) {
+	addr = addr.Unmap()
+
+	var ip []byte
+	node := uint(0)
+	offsetBits := 0
+	if addr.Is4() {
+		if r.ipVersion == 6 {
+			node = r.ipv4Start
+			offsetBits = r.ipv4Depth
+		}
+		ip4 := addr.As4()
+		ip = ip4[:]
+	} else {
+		if r.ipVersion == 4 {
+			return nil, netip.Prefix{}, nil
+		}
+		ip16 := addr.As16()
+		ip = ip16[:]
+	}
+
+	bitCount := len(ip) * 8
+	depth := 0
+	var err error
+	for ; depth < bitCount && node < r.nodeCount; depth++ {
+		bit := uint(ip[depth>>3]>>(7-uint(depth&7))) & 1
+		if node, err = r.readNode(node, bit); err != nil {
+			return nil, netip.Prefix{}, err
+		}
+	}
+
+	// IPv4 lookups that stopped within ::/96 still describe the IPv4 address
+	prefixBits := depth
+	if addr.Is4() && r.ipVersion == 6 && offsetBits < 96 {
+		prefixBits = 0
+	}
+	network, _ := addr.Prefix(prefixBits)
+
+	if node == r.nodeCount {
+		// Not found
+		return nil, network, nil
+	}
+	if node < r.nodeCount {
+		return nil, network, errCorruptDatabase
+	}
+
+	offset := node - r.nodeCount - mmdbDataSectionSeparator
+	if offset >= uint(len(r.data)) {
+		return nil, network, errCorruptDatabase
+	}
+
+	decoder := mmdbDecoder{data: r.data}
+	value, _, err := decoder.decode(offset, 0)
+	if err != nil {
+		return nil, network, err
+	}
+	record, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, network, nil
+	}
+	return record, network, nil
+}
+
+// readNode returns the left (bit 0) or right (bit 1) record of a search tree node
+func (r *mmdbReader) readNode(node uint, bit uint) (uint, error) {
+	size := r.recordSize / 4
+	offset := node * size
+	if offset+size > uint(len(r.buffer)) {
+		return 0, errCorruptDatabase
+	}
+	b := r.buffer[offset : offset+size]
+
+	switch r.recordSize {
+	case 24:
+		if bit == 0 {
+			return uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3])<<16 | uint(b[4])<<8 | uint(b[5]), nil
+	case 28:
+		// The middle byte holds the high nibble of both records
+		if bit == 0 {
+			return uint(b[3]&0xf0)<<20 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2]), nil
+		}
+		return uint(b[3]&0x0f)<<24 | uint(b[4])<<16 | uint(b[5])<<8 | uint(b[6]), nil
+	default:
+		if bit == 0 {
+			return uint(binary.BigEndian.Uint32(b[0:4])), nil
+		}
+		return uint(binary.BigEndian.Uint32(b[4:8])), nil
+	}
+}
+
+// mmdbDecoder decodes values of the MaxMind DB data section
+type mmdbDecoder struct {
+	data []byte
+}
+
+// decode returns the value at offset and the offset following it
+func (d *mmdbDecoder) decode(offset uint, depth int) (interface{}, uint, error) {
+	if depth > mmdbMaxDepth {
+		return nil, 0, errCorruptDatabase
+	}
+
+	dataType, size, offset, err := d.controlByte(offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Pointers point at values elsewhere in the data section, decoding continues after the pointer
+	if dataType == 1 {
+		pointer, next, err := d.pointer(size, offset)
+		if err != nil {
+			return nil, 0, err
+		}
+		value, _, err := d.decode(pointer, depth+1)
+		return value, next, err
+	}
+
+	end := offset + size
+	if dataType != 7 && dataType != 11 && dataType != 14 && end > uint(len(d.data)) {
+		return nil, 0, errCorruptDatabase
+	}
+
+	switch dataType {
+	case 2:
+		return string(d.data[offset:end]), end, nil
+	case 3:
+		if size != 8 {
+			return nil, 0, errCorruptDatabase
+		}
+		return math.Float64frombits(binary.BigEndian.Uint64(d.data[offset:end])), end, nil
+	case 4:
+		return append([]byte(nil), d.data[offset:end]...), end, nil
+	case 5, 6, 9:
+		if size > 8 {
+			return nil, 0, errCorruptDatabase
+		}
+		var value uint64
+		for _, b := range d.data[offset:end] {
+			value = value<<8 | uint64(b)
+		}
+		return value, end, nil
+	case 8:
+		if size > 4 {
+			return nil, 0, errCorruptDatabase
+		}
+		var value uint32
+		for _, b := range d.data[offset:end] {
+			value = value<<8 | uint32(b)
+		}
+		return int64(int32(value)), end, nil
+	case 10:
+		return new(big.Int).SetBytes(d.data[offset:end]), end, nil
+	case 7:
+		record := make(map[string]interface{}, d.capacity(offset, size))
+		for i := uint(0); i < size; i++ {
+			key, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			keyString, ok := key.(string)
+			if !ok {
+				return nil, 0, errCorruptDatabase
+			}
+			value, next, err := d.decode(next, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			record[keyString] = value
+			offset = next
+		}
+		return record, offset, nil
+	case 11:
+		list := make([]interface{}, 0, d.capacity(offset, size))
+		for i := uint(0); i < size; i++ {
+			value, next, err := d.decode(offset, depth+1)
+			if err != nil {
+				return nil, 0, err
+			}
+			list = append(list, value)
+			offset = next
+		}
+		return list, offset, nil
+	case 14:
+		return size != 0, offset, nil
+	case 15:
+		if size != 4 {
+			return nil, 0, errCorruptDatabase
+		}
+		return float64(math.Float32frombits(binary.BigEndian.Uint32(d.data[offset:end]))), end, nil
+	default:
+		return nil, 0, fmt.Errorf("%w: unknown data type %d", errCorruptDatabase, dataType)
+	}
+}
+
+// capacity bounds the entry count read from the file by the bytes left after offset, since every
+// entry takes at least one byte, so a corrupt size can't force a huge allocation
+func (d *mmdbDecoder) capacity(offset uint, size uint) uint {
+	if remaining := uint(len(d.data)) - min(offset, uint(len(d.data))); size > remaining {
+		return remaining
+	}
+	return size
+}
+
+// controlByte reads the type and size of the value at offset, returning the offset of its payload
+func (d *mmdbDecoder) controlByte(offset uint) (uint, uint, uint, error) {
+	if offset >= uint(len(d.data)) {
+		return 0, 0, 0, errCorruptDatabase
+	}
+	control := d.data[offset]
+	offset++
+
+	dataType := uint(control >> 5)
+	if dataType == 0 {
+		// Extended types store the type in the next byte
+		if offset >= uint(len(d.data)) {
+			return 0, 0, 0, errCorruptDatabase
+		}
+		dataType = 7 + uint(d.data[offset])
+		offset++
+	}
+
+	size := uint(control & 0x1f)
+	if dataType == 1 {
+		// The size bits of a pointer are interpreted by pointer
+		return dataType, size, offset, nil
+	}
+
+	if size >= 29 {
+		extra := size - 28
+		if offset+extra > uint(len(d.data)) {
+			return 0, 0, 0, errCorruptDatabase
+		}
+		var value uint
+		for _, b := range d.data[offset : offset+extra] {
+			value = value<<8 | uint(b)
+		}
+		switch size {
+		case 29:
+			size = 29 + value
+		case 30:
+			size = 285 + value
+		default:
+			size = 65821 + value
+		}
+		offset += extra
+	}
+
+	return dataType, size, offset, nil
+}
+
+// pointer resolves a pointer whose size bits were read by controlByte
+func (d *mmdbDecoder) pointer(size uint, offset uint) (uint, uint, error) {
+	length := (size>>3)&0x3 + 1
+	if offset+length > uint(len(d.data)) {
+		return 0, 0, errCorruptDatabase
+	}
+	b := d.data[offset : offset+length]
+
+	var pointer uint
+	switch length {
+	case 1:
+		pointer = (size&0x7)<<8 | uint(b[0])
+	case 2:
+		pointer = ((size&0x7)<<16 | uint(b[0])<<8 | uint(b[1])) + 2048
+	case 3:
+		pointer = ((size&0x7)<<24 | uint(b[0])<<16 | uint(b[1])<<8 | uint(b[2])) + 526336
+	default:
+		pointer = uint(binary.BigEndian.Uint32(b))
+	}
+	return pointer, offset + length, nil
+}
+
+// toUint converts a decoded unsigned value
+func toUint(value interface{}) uint64 {
+	switch v := value.(type) {
+	case uint64:
+		return v
+	case int64:
+		if v > 0 {
+			return uint64(v)
+		}
+	case float64:
+		if v > 0 {
+			return uint64(v)
+		}
+	}
+	return 0
+}
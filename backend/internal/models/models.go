@@ -34,6 +34,30 @@ func (j *JSONB) Scan(value interface{}) error {
 	return json.Unmarshal(bytes, j)
 }
 
+// StringArray type for PostgreSQL jsonb columns holding a list of strings
+type StringArray []string
+
+// Value for implementing driver.Valuer
+func (a StringArray) Value() (driver.Value, error) {
+	if a == nil {
+		return "[]", nil
+	}
+	return json.Marshal(a)
+}
+
+// Scan for implementing sql.Scanner
+func (a *StringArray) Scan(value interface{}) error {
+	if value == nil {
+		*a = nil
+		return nil
+	}
+	bytes, ok := value.([]byte)
+	if !ok {
+		return errors.New("type assertion to []byte failed")
+	}
+	return json.Unmarshal(bytes, a)
+}
+
 // Status type for enum values
 type Status string
 
@@ -58,14 +82,19 @@ const (
 
 // DNS Record Type enum values
 const (
-	RecordTypeA     = "A"
-	RecordTypeAAAA  = "AAAA"
-	RecordTypeCNAME = "CNAME"
-	RecordTypeANAME = "ANAME"
-	RecordTypeSOA   = "SOA"
-	RecordTypeNS    = "NS"
-	RecordTypeMX    = "MX"
-	RecordTypeTXT   = "TXT"
+	RecordTypeA      = "A"
+	RecordTypeAAAA   = "AAAA"
+	RecordTypeCNAME  = "CNAME"
+	RecordTypeANAME  = "ANAME"
+	RecordTypeSOA    = "SOA"
+	RecordTypeNS     = "NS"
+	RecordTypeMX     = "MX"
+	RecordTypeTXT    = "TXT"
+	RecordTypePTR    = "PTR"
+	RecordTypeSRV    = "SRV"
+	RecordTypeCAA    = "CAA"
+	RecordTypeDS     = "DS"
+	RecordTypeDNSKEY = "DNSKEY"
 )
 
 // TargetType enum values
@@ -81,6 +110,7 @@ const (
 	RelationParentOf     = "parent_of"
 	RelationChildOf      = "child_of"
 	RelationHostsService = "hosts_service"
+	RelationHostedOn     = "hosted_on" // Virtual host served by an address its DNS doesn't point to
 )
 
 // Project represents a scanning project
@@ -164,7 +194,7 @@ type Application struct {
 type ScanConfig struct {
 	ID          uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
 	Name        string    `json:"name" gorm:"type:varchar(255);not null"`
-	ScannerType string    `json:"scanner_type" gorm:"type:varchar(50);not null;check:scanner_type IN ('nmap', 'dns', 'subdomain', 'nuclei', 'httpx', 'testSSL')"`
+	ScannerType string    `json:"scanner_type" gorm:"type:varchar(50);not null;check:scanner_type IN ('nmap', 'dns', 'subdomain', 'nuclei', 'httpx', 'testSSL', 'portscan', 'takeover', 'tls', 'ping', 'command', 'crawler', 'contentdiscovery', 'banner', 'ssh', 'smtp', 'vhost') OR scanner_type LIKE 'plugin:%'"`
 	Parameters  JSONB     `json:"parameters" gorm:"type:jsonb;default:'{}'::jsonb"`
 	Active      bool      `json:"active" gorm:"default:true"`
 	Scans       []Scan    `json:"scans,omitempty" gorm:"foreignKey:ScanConfigID"`
@@ -197,11 +227,23 @@ type Finding struct {
 	Description   string     `json:"description" gorm:"type:text"`
 	Severity      string     `json:"severity" gorm:"type:varchar(20);not null;check:severity IN ('critical', 'high', 'medium', 'low', 'info', 'unknown')"`
 	FindingType   string     `json:"finding_type" gorm:"type:varchar(50);not null"`
+	Fingerprint   string     `json:"fingerprint,omitempty" gorm:"type:text;default:''"` // Tells apart findings of the same type on a target, such as the URL of an exposed file
 	Details       JSONB      `json:"details" gorm:"type:jsonb;default:'{}'::jsonb"`
 	DiscoveredAt  time.Time  `json:"discovered_at" gorm:"default:CURRENT_TIMESTAMP"`
 	Verified      bool       `json:"verified" gorm:"default:false"`
 	Fixed         bool       `json:"fixed" gorm:"default:false"`
 	Manual        bool       `json:"manual" gorm:"default:false"`
+
+	// Vulnerability metadata, filled by scanners where known and by the exploit data files
+	CVEIDs         StringArray `json:"cve_ids" gorm:"column:cve_ids;type:jsonb;default:'[]'::jsonb"`
+	CWEIDs         StringArray `json:"cwe_ids" gorm:"column:cwe_ids;type:jsonb;default:'[]'::jsonb"`
+	CVSSVersion    string      `json:"cvss_version,omitempty" gorm:"column:cvss_version;type:varchar(10)"`
+	CVSSVector     string      `json:"cvss_vector,omitempty" gorm:"column:cvss_vector;type:varchar(255)"`
+	CVSSScore      *float64    `json:"cvss_score,omitempty" gorm:"column:cvss_score"`
+	References     StringArray `json:"references" gorm:"type:jsonb;default:'[]'::jsonb"`
+	EPSSScore      *float64    `json:"epss_score,omitempty" gorm:"column:epss_score"`
+	EPSSPercentile *float64    `json:"epss_percentile,omitempty" gorm:"column:epss_percentile"`
+	KnownExploited bool        `json:"known_exploited" gorm:"default:false"` // Listed in the CISA KEV catalog
 }
 
 // Report represents a generated report for a project
@@ -281,6 +323,22 @@ type Certificate struct {
 	DiscoveredAt  time.Time  `json:"discovered_at" gorm:"default:CURRENT_TIMESTAMP"`
 }
 
+// NucleiTemplate is a custom nuclei template uploaded through the API, workers write it to disk before a scan
+type NucleiTemplate struct {
+	ID          uuid.UUID   `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	TemplateID  string      `json:"template_id" gorm:"type:varchar(255);not null;uniqueIndex"` // The id field of the template
+	Name        string      `json:"name" gorm:"type:varchar(255);not null"`
+	Author      string      `json:"author" gorm:"type:varchar(255)"`
+	Severity    string      `json:"severity" gorm:"type:varchar(50);not null"`
+	Description string      `json:"description" gorm:"type:text"`
+	Tags        StringArray `json:"tags" gorm:"type:jsonb;default:'[]'::jsonb"`
+	Protocols   StringArray `json:"protocols" gorm:"type:jsonb;default:'[]'::jsonb"`
+	Content     string      `json:"content,omitempty" gorm:"type:text;not null"`
+	Checksum    string      `json:"checksum" gorm:"type:varchar(64);not null"` // SHA-256 of the content
+	CreatedAt   time.Time   `json:"created_at" gorm:"default:CURRENT_TIMESTAMP"`
+	UpdatedAt   time.Time   `json:"updated_at" gorm:"default:CURRENT_TIMESTAMP"`
+}
+
 // Auth Related
 // The following models needs to adhere to the following schema https://authjs.dev/getting-started/adapters/pg?framework=next-js#schema
 
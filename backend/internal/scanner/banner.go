@@ -0,0 +1,768 @@
+// internal/scanner/banner.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// bannerServiceNames maps service names reported by other scanners to the protocol spoken by the grabber
+var bannerServiceNames = map[string]string{
+	"ssh":           "ssh",
+	"ftp":           "ftp",
+	"ftps":          "ftp",
+	"smtp":          "smtp",
+	"smtps":         "smtp",
+	"submission":    "smtp",
+	"pop3":          "pop3",
+	"pop3s":         "pop3",
+	"imap":          "imap",
+	"imaps":         "imap",
+	"mysql":         "mysql",
+	"postgresql":    "postgresql",
+	"redis":         "redis",
+	"ms-wbt-server": "rdp",
+	"rdp":           "rdp",
+}
+
+// bannerPorts maps well-known ports to the protocol spoken by the grabber
+var bannerPorts = map[int]string{
+	21:   "ftp",
+	22:   "ssh",
+	25:   "smtp",
+	110:  "pop3",
+	143:  "imap",
+	465:  "smtp",
+	587:  "smtp",
+	990:  "ftp",
+	993:  "imap",
+	995:  "pop3",
+	2222: "ssh",
+	3306: "mysql",
+	3389: "rdp",
+	5432: "postgresql",
+	6379: "redis",
+}
+
+// bannerTLSPorts are ports where the protocol is wrapped in TLS from the first byte
+var bannerTLSPorts = map[int]bool{
+	465: true,
+	990: true,
+	993: true,
+	995: true,
+}
+
+// bannerTLSNames are service names of protocols wrapped in TLS from the first byte
+var bannerTLSNames = map[string]bool{
+	"ftps":  true,
+	"smtps": true,
+	"pop3s": true,
+	"imaps": true,
+}
+
+// bannerNames are the nmap service names reported for each protocol, in plain and TLS-wrapped form
+var bannerNames = map[string][2]string{
+	"ssh":        {"ssh", "ssh"},
+	"ftp":        {"ftp", "ftps"},
+	"smtp":       {"smtp", "smtps"},
+	"pop3":       {"pop3", "pop3s"},
+	"imap":       {"imap", "imaps"},
+	"mysql":      {"mysql", "mysql"},
+	"postgresql": {"postgresql", "postgresql"},
+	"redis":      {"redis", "redis"},
+	"rdp":        {"ms-wbt-server", "ms-wbt-server"},
+}
+
+// bannerProducts identify the product, and version when the first group matches, from a banner
+var bannerProducts = []struct {
+	product string
+	pattern *regexp.Regexp
+}{
+	{"OpenSSH", regexp.MustCompile(`OpenSSH[_-]([\w.]+)`)},
+	{"Dropbear", regexp.MustCompile(`(?i)dropbear[_-]([\w.]+)`)},
+	{"vsftpd", regexp.MustCompile(`(?i)vsFTPd ([\d.]+)`)},
+	{"ProFTPD", regexp.MustCompile(`ProFTPD ([\w.]+)`)},
+	{"FileZilla Server", regexp.MustCompile(`FileZilla Server(?: version)? ?([\d.]+\w*)?`)},
+	{"Pure-FTPd", regexp.MustCompile(`Pure-FTPd()`)},
+	{"Microsoft FTP Service", regexp.MustCompile(`Microsoft FTP Service()`)},
+	{"Microsoft Exchange", regexp.MustCompile(`Microsoft ESMTP MAIL Service(?:, Version: ([\d.]+))?`)},
+	{"Postfix", regexp.MustCompile(`Postfix()`)},
+	{"Exim", regexp.MustCompile(`Exim ([\d.]+)`)},
+	{"Sendmail", regexp.MustCompile(`Sendmail ([\w.]+)`)},
+	{"OpenSMTPD", regexp.MustCompile(`OpenSMTPD()`)},
+	{"Dovecot", regexp.MustCompile(`Dovecot()`)},
+	{"Courier", regexp.MustCompile(`Courier()`)},
+	{"Cyrus", regexp.MustCompile(`Cyrus (?:IMAP|POP3)?\s*(?:v|server )?([\d.]+)?`)},
+}
+
+// BannerScanner implements the Scanner interface for grabbing protocol banners from services
+type BannerScanner struct {
+	timeout int
+}
+
+// BannerTarget is a service to grab a banner from
+type BannerTarget struct {
+	Host    string
+	Service models.Service
+}
+
+// bannerResult holds what was learned about a service
+type bannerResult struct {
+	protocol string
+	product  string
+	version  string
+	banner   string
+	tls      models.JSONB
+	details  models.JSONB
+}
+
+// NewBannerScanner creates a new banner grabbing scanner
+func NewBannerScanner() *BannerScanner {
+	return &BannerScanner{
+		timeout: 5, // Connect and read timeout in seconds
+	}
+}
+
+// Initialize has nothing to set up since banners are grabbed natively
+func (s *BannerScanner) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// ConvertTarget returns nil since banners are grabbed from services only
+func (s *BannerScanner) ConvertTarget(target models.Target) interface{} {
+	return nil
+}
+
+// ConvertService converts a TCP Service to a format suitable for banner grabbing
+func (s *BannerScanner) ConvertService(service models.Service) interface{} {
+	if service.Protocol != "" && service.Protocol != "tcp" {
+		return nil
+	}
+
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return nil
+	}
+
+	return BannerTarget{Host: host, Service: service}
+}
+
+// Scan grabs the banner of a service and reports it back as an updated service
+func (s *BannerScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	bannerTarget, ok := target.(BannerTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for banner scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Services: []models.Service{},
+	}
+
+	// Configure scan parameters
+	timeout := s.timeout
+	protocol := ""
+	forceTLS := false
+
+	// Override with provided parameters if available
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["protocol"].(string); ok && val != "" {
+		protocol = val
+	}
+	if val, ok := params["tls"].(bool); ok {
+		forceTLS = val
+	}
+
+	service := bannerTarget.Service
+	serviceName := strings.ToLower(service.ServiceName)
+
+	// Pick the protocol from the known service name, then from the port
+	if protocol == "" {
+		protocol = bannerServiceNames[strings.TrimPrefix(serviceName, "ssl/")]
+	}
+	if protocol == "" {
+		protocol = bannerPorts[service.Port]
+	}
+
+	useTLS := forceTLS || bannerTLSPorts[service.Port] || bannerTLSNames[serviceName] || strings.HasPrefix(serviceName, "ssl/")
+
+	result, err := s.grab(ctx, bannerTarget.Host, service.Port, protocol, useTLS, time.Duration(timeout)*time.Second)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("banner grab on %s:%d failed: %w", bannerTarget.Host, service.Port, err)
+	}
+
+	// Unknown services that stay silent may still speak TLS
+	if result.banner == "" && result.protocol == "" && !useTLS {
+		if tlsResult, err := s.grab(ctx, bannerTarget.Host, service.Port, protocol, true, time.Duration(timeout)*time.Second); err == nil {
+			result = tlsResult
+		}
+	}
+
+	if result.banner == "" && result.protocol == "" && result.tls == nil {
+		return scanResults, nil
+	}
+
+	scanResults.Services = append(scanResults.Services, s.createService(bannerTarget, result))
+	return scanResults, nil
+}
+
+// grab connects to the service and runs the probe of the protocol
+func (s *BannerScanner) grab(ctx context.Context, host string, port int, protocol string, useTLS bool, timeout time.Duration) (*bannerResult, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(host, strconv.Itoa(port)))
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	// Abort blocking reads when the scan is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	conn.SetDeadline(time.Now().Add(timeout * 3))
+	result := &bannerResult{details: models.JSONB{}}
+
+	if useTLS {
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		if net.ParseIP(host) == nil {
+			tlsConfig.ServerName = host
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			return nil, fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		result.tls = tlsDetails(tlsConn.ConnectionState())
+		conn = tlsConn
+	}
+
+	reader := bufio.NewReader(conn)
+
+	switch protocol {
+	case "ssh", "ftp", "smtp", "pop3", "imap":
+		err = s.probeText(conn, reader, protocol, timeout, result)
+	case "mysql":
+		err = s.probeMySQL(reader, timeout, conn, result)
+	case "postgresql":
+		err = s.probePostgreSQL(conn, reader, timeout, result)
+	case "redis":
+		err = s.probeRedis(conn, reader, timeout, result)
+	case "rdp":
+		err = s.probeRDP(conn, reader, timeout, result)
+	default:
+		err = s.probeUnknown(conn, reader, timeout, result)
+	}
+	if err != nil && result.banner == "" {
+		if result.tls != nil {
+			// The TLS handshake alone is worth reporting
+			return result, nil
+		}
+		if protocol == "" && isTimeout(err) {
+			return result, nil
+		}
+		return nil, err
+	}
+
+	if result.protocol != "" {
+		names := bannerNames[result.protocol]
+		if result.tls != nil {
+			result.protocol = names[1]
+		} else {
+			result.protocol = names[0]
+		}
+	}
+
+	if result.product == "" {
+		result.product, result.version = identifyProduct(result.banner)
+	}
+
+	return result, nil
+}
+
+// probeText reads the greeting of a line based protocol and asks for its capabilities
+func (s *BannerScanner) probeText(conn net.Conn, reader *bufio.Reader, protocol string, timeout time.Duration, result *bannerResult) error {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	greeting, err := readReply(reader, protocol)
+	if err != nil {
+		return err
+	}
+	result.banner = sanitizeBanner([]byte(greeting))
+	result.protocol = protocol
+
+	conn.SetDeadline(time.Now().Add(timeout))
+	switch protocol {
+	case "ssh":
+		if !strings.HasPrefix(greeting, "SSH-") {
+			return fmt.Errorf("unexpected SSH greeting")
+		}
+		// SSH-protoversion-softwareversion comments
+		parts := strings.SplitN(strings.TrimSpace(greeting), "-", 3)
+		if len(parts) == 3 {
+			result.details["protocol_version"] = parts[1]
+			result.details["software"] = parts[2]
+		}
+		if product, version := identifyProduct(result.banner); product != "" {
+			result.product, result.version = product, version
+		} else if len(parts) == 3 {
+			result.product = strings.Fields(parts[2])[0]
+		}
+	case "ftp":
+		if !strings.HasPrefix(greeting, "220") {
+			break
+		}
+		fmt.Fprint(conn, "FEAT\r\n")
+		if reply, err := readReply(reader, protocol); err == nil && strings.HasPrefix(reply, "211") {
+			result.details["features"] = replyLines(reply, 1)
+		}
+		fmt.Fprint(conn, "QUIT\r\n")
+	case "smtp":
+		if !strings.HasPrefix(greeting, "220") {
+			break
+		}
+		fmt.Fprint(conn, "EHLO zecas.local\r\n")
+		if reply, err := readReply(reader, protocol); err == nil && strings.HasPrefix(reply, "250") {
+			result.details["capabilities"] = replyLines(reply, 1)
+		}
+		fmt.Fprint(conn, "QUIT\r\n")
+	case "pop3":
+		if !strings.HasPrefix(greeting, "+OK") {
+			break
+		}
+		fmt.Fprint(conn, "CAPA\r\n")
+		if reply, err := readReply(reader, "pop3-multi"); err == nil && strings.HasPrefix(reply, "+OK") {
+			result.details["capabilities"] = replyLines(reply, 1)
+		}
+		fmt.Fprint(conn, "QUIT\r\n")
+	case "imap":
+		if !strings.HasPrefix(greeting, "* OK") && !strings.HasPrefix(greeting, "* PREAUTH") {
+			break
+		}
+		fmt.Fprint(conn, "a1 CAPABILITY\r\n")
+		if reply, err := readReply(reader, "imap-tagged"); err == nil {
+			for _, line := range strings.Split(reply, "\n") {
+				if strings.HasPrefix(line, "* CAPABILITY") {
+					result.details["capabilities"] = strings.Fields(strings.TrimPrefix(strings.TrimSpace(line), "* CAPABILITY"))
+				}
+			}
+		}
+		fmt.Fprint(conn, "a2 LOGOUT\r\n")
+	}
+
+	return nil
+}
+
+// probeMySQL reads the initial handshake packet sent by MySQL and MariaDB servers
+func (s *BannerScanner) probeMySQL(reader *bufio.Reader, timeout time.Duration, conn net.Conn, result *bannerResult) error {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return err
+	}
+	length := int(header[0]) | int(header[1])<<8 | int(header[2])<<16
+	if length == 0 || length > 1<<16 {
+		return fmt.Errorf("unexpected MySQL packet length %d", length)
+	}
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(reader, payload); err != nil {
+		return err
+	}
+
+	result.protocol = "mysql"
+	result.product = "MySQL"
+
+	switch payload[0] {
+	case 0x0a:
+		end := strings.IndexByte(string(payload[1:]), 0)
+		if end < 0 {
+			return fmt.Errorf("malformed MySQL handshake")
+		}
+		version := string(payload[1 : 1+end])
+		result.banner = version
+		result.details["protocol_version"] = 10
+		if strings.Contains(version, "MariaDB") {
+			result.product = "MariaDB"
+			version = strings.TrimPrefix(version, "5.5.5-")
+		}
+		result.version = strings.SplitN(version, "-", 2)[0]
+	case 0xff:
+		// The server refuses the client, the error message still identifies it
+		if len(payload) > 3 {
+			result.details["error_code"] = binary.LittleEndian.Uint16(payload[1:3])
+			result.banner = sanitizeBanner(payload[3:])
+		}
+	default:
+		return fmt.Errorf("unexpected MySQL packet type %#x", payload[0])
+	}
+
+	return nil
+}
+
+// probePostgreSQL asks the server whether it supports TLS, which PostgreSQL answers with a single byte
+func (s *BannerScanner) probePostgreSQL(conn net.Conn, reader *bufio.Reader, timeout time.Duration, result *bannerResult) error {
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// SSLRequest: length 8 and the magic code 80877103
+	request := []byte{0, 0, 0, 8, 0x04, 0xd2, 0x16, 0x2f}
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	answer, err := reader.ReadByte()
+	if err != nil {
+		return err
+	}
+
+	switch answer {
+	case 'S':
+		result.details["ssl_supported"] = true
+	case 'N':
+		result.details["ssl_supported"] = false
+	default:
+		return fmt.Errorf("unexpected PostgreSQL SSL response %q", answer)
+	}
+
+	result.protocol = "postgresql"
+	result.product = "PostgreSQL"
+	result.banner = fmt.Sprintf("PostgreSQL (SSL %s)", map[bool]string{true: "supported", false: "not supported"}[answer == 'S'])
+	return nil
+}
+
+// probeRedis requests the server section of INFO, which fails when authentication is required
+func (s *BannerScanner) probeRedis(conn net.Conn, reader *bufio.Reader, timeout time.Duration, result *bannerResult) error {
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := fmt.Fprint(conn, "INFO server\r\n"); err != nil {
+		return err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	line = strings.TrimSpace(line)
+
+	switch {
+	case strings.HasPrefix(line, "$"):
+		size, err := strconv.Atoi(strings.TrimPrefix(line, "$"))
+		if err != nil || size <= 0 || size > 1<<20 {
+			return fmt.Errorf("unexpected Redis reply %q", line)
+		}
+		body := make([]byte, size)
+		if _, err := io.ReadFull(reader, body); err != nil {
+			return err
+		}
+		info := parseRedisInfo(string(body))
+		result.version = info["redis_version"]
+		result.banner = "redis_version:" + result.version
+		result.details["authentication_required"] = false
+		for _, key := range []string{"redis_mode", "os", "arch_bits", "tcp_port"} {
+			if value, ok := info[key]; ok {
+				result.details[key] = value
+			}
+		}
+	case strings.HasPrefix(line, "-"):
+		result.banner = sanitizeBanner([]byte(line))
+		result.details["authentication_required"] = strings.Contains(line, "NOAUTH") || strings.Contains(line, "AUTH")
+	default:
+		return fmt.Errorf("unexpected Redis reply %q", line)
+	}
+
+	result.protocol = "redis"
+	result.product = "Redis"
+	return nil
+}
+
+// probeRDP sends an X.224 connection request and reads the negotiated security protocol
+func (s *BannerScanner) probeRDP(conn net.Conn, reader *bufio.Reader, timeout time.Duration, result *bannerResult) error {
+	conn.SetDeadline(time.Now().Add(timeout))
+
+	// TPKT header, X.224 connection request and an RDP negotiation request for TLS and CredSSP
+	request := []byte{
+		0x03, 0x00, 0x00, 0x13,
+		0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00,
+		0x01, 0x00, 0x08, 0x00, 0x03, 0x00, 0x00, 0x00,
+	}
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+
+	response := make([]byte, 19)
+	n, err := io.ReadAtLeast(reader, response, 11)
+	if err != nil {
+		return err
+	}
+	if response[0] != 0x03 || response[5] != 0xd0 {
+		return fmt.Errorf("unexpected RDP response")
+	}
+
+	result.protocol = "rdp"
+	result.product = "Microsoft Terminal Services"
+	result.banner = "RDP"
+
+	if n >= 19 {
+		code := binary.LittleEndian.Uint32(response[15:19])
+		switch response[11] {
+		case 0x02:
+			selected := map[uint32]string{0: "RDP", 1: "TLS", 2: "CredSSP", 8: "RDSTLS"}[code]
+			if selected == "" {
+				selected = fmt.Sprintf("0x%x", code)
+			}
+			result.details["selected_protocol"] = selected
+			result.banner = fmt.Sprintf("RDP (security: %s)", selected)
+		case 0x03:
+			result.details["negotiation_failure"] = code
+			result.banner = fmt.Sprintf("RDP (negotiation failure %d)", code)
+		}
+	}
+
+	return nil
+}
+
+// probeUnknown reads whatever an unknown service sends first and tries to recognize it
+func (s *BannerScanner) probeUnknown(conn net.Conn, reader *bufio.Reader, timeout time.Duration, result *bannerResult) error {
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	buf := make([]byte, 1024)
+	n, err := reader.Read(buf)
+	if n == 0 {
+		return err
+	}
+	greeting := string(buf[:n])
+	result.banner = sanitizeBanner(buf[:n])
+
+	switch {
+	case strings.HasPrefix(greeting, "SSH-"):
+		result.protocol = "ssh"
+	case strings.HasPrefix(greeting, "+OK"):
+		result.protocol = "pop3"
+	case strings.HasPrefix(greeting, "* OK"), strings.HasPrefix(greeting, "* PREAUTH"):
+		result.protocol = "imap"
+	case strings.HasPrefix(greeting, "220"):
+		lower := strings.ToLower(greeting)
+		if strings.Contains(lower, "smtp") || strings.Contains(lower, "mail") {
+			result.protocol = "smtp"
+		} else if strings.Contains(lower, "ftp") {
+			result.protocol = "ftp"
+		}
+	case n > 5 && buf[4] == 0x0a && int(buf[0])|int(buf[1])<<8|int(buf[2])<<16 == n-4:
+		return s.probeMySQL(bufio.NewReader(strings.NewReader(greeting)), timeout, conn, result)
+	}
+
+	return nil
+}
+
+// createService reports the grabbed banner as an update of the scanned service
+func (s *BannerScanner) createService(target BannerTarget, result *bannerResult) models.Service {
+	service := target.Service
+
+	rawInfo := models.JSONB{
+		"banner":            result.banner,
+		"target_value":      target.Host,
+		"banner_grabbed_at": time.Now().Format(time.RFC3339),
+	}
+	if result.product != "" {
+		rawInfo["product"] = result.product
+	}
+	if result.version != "" {
+		rawInfo["version"] = result.version
+	}
+	if result.tls != nil {
+		rawInfo["tls"] = result.tls
+	}
+	if len(result.details) > 0 {
+		rawInfo["banner_details"] = result.details
+	}
+
+	name := result.protocol
+	if name == "" {
+		name = service.ServiceName
+	}
+
+	return models.Service{
+		ID:          uuid.New(),
+		TargetID:    service.TargetID,
+		Port:        service.Port,
+		Protocol:    "tcp",
+		ServiceName: result.protocol,
+		Version:     result.version,
+		Title:       fmt.Sprintf("%s service on port %d", name, service.Port),
+		Description: s.generateServiceDescription(result, service.Port),
+		Banner:      truncateString(result.banner, 1024),
+		RawInfo:     rawInfo,
+	}
+}
+
+// generateServiceDescription creates a human-readable description of a grabbed service
+func (s *BannerScanner) generateServiceDescription(result *bannerResult, port int) string {
+	desc := fmt.Sprintf("Banner grabbed from port %d", port)
+	if result.product != "" {
+		desc += fmt.Sprintf("\nProduct identified as %s", result.product)
+		if result.version != "" {
+			desc += fmt.Sprintf(" version %s", result.version)
+		}
+	}
+	if result.tls != nil {
+		desc += fmt.Sprintf("\nWrapped in %v", result.tls["version"])
+	}
+	if result.banner != "" {
+		desc += fmt.Sprintf("\nBanner: %s", result.banner)
+	}
+	return desc
+}
+
+// readReply reads a complete reply of a line based protocol, following multi-line continuations
+func readReply(reader *bufio.Reader, protocol string) (string, error) {
+	var lines []string
+	for len(lines) < 100 {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if line != "" {
+				lines = append(lines, line)
+			}
+			if len(lines) > 0 {
+				return strings.Join(lines, ""), nil
+			}
+			return "", err
+		}
+		lines = append(lines, line)
+		trimmed := strings.TrimRight(line, "\r\n")
+
+		switch protocol {
+		case "ftp", "smtp":
+			// Continuation lines use a dash after the code, "250-PIPELINING", the last a space
+			if len(trimmed) >= 4 && trimmed[3] == '-' {
+				continue
+			}
+			if protocol == "ftp" && len(lines) > 1 && !(len(trimmed) >= 4 && trimmed[3] == ' ') {
+				continue
+			}
+		case "pop3-multi":
+			if trimmed != "." && !strings.HasPrefix(trimmed, "-ERR") {
+				continue
+			}
+		case "imap-tagged":
+			if !strings.HasPrefix(trimmed, "a1 ") {
+				continue
+			}
+		}
+		return strings.Join(lines, ""), nil
+	}
+	return strings.Join(lines, ""), nil
+}
+
+// replyLines returns the lines of a multi-line reply without the status line and reply codes
+func replyLines(reply string, skip int) []string {
+	var lines []string
+	for i, line := range strings.Split(strings.TrimSpace(reply), "\n") {
+		if i < skip {
+			continue
+		}
+		line = strings.TrimSpace(line)
+		if len(line) >= 4 && (line[3] == '-' || line[3] == ' ') && isDigits(line[:3]) {
+			line = strings.TrimSpace(line[4:])
+		}
+		if line == "" || line == "." || strings.HasPrefix(strings.ToUpper(line), "END") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// identifyProduct matches a banner against the known products
+func identifyProduct(banner string) (string, string) {
+	for _, known := range bannerProducts {
+		if match := known.pattern.FindStringSubmatch(banner); match != nil {
+			version := ""
+			if len(match) > 1 {
+				version = match[1]
+			}
+			return known.product, version
+		}
+	}
+	return "", ""
+}
+
+// parseRedisInfo parses the key:value lines of a Redis INFO reply
+func parseRedisInfo(body string) map[string]string {
+	info := make(map[string]string)
+	for _, line := range strings.Split(body, "\n") {
+		key, value, found := strings.Cut(strings.TrimSpace(line), ":")
+		if found && !strings.HasPrefix(key, "#") {
+			info[key] = value
+		}
+	}
+	return info
+}
+
+// tlsDetails summarizes a TLS connection
+func tlsDetails(state tls.ConnectionState) models.JSONB {
+	details := models.JSONB{
+		"version": tls.VersionName(state.Version),
+		"cipher":  tls.CipherSuiteName(state.CipherSuite),
+	}
+	if len(state.PeerCertificates) > 0 {
+		cert := state.PeerCertificates[0]
+		details["subject"] = cert.Subject.String()
+		details["issuer"] = cert.Issuer.String()
+		details["not_after"] = cert.NotAfter.Format(time.RFC3339)
+	}
+	return details
+}
+
+func isDigits(value string) bool {
+	for _, c := range value {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return value != ""
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
+
+// Type returns the scanner type identifier
+func (s *BannerScanner) Type() string {
+	return "banner"
+}
+
+// SupportsTargetType returns false since banners are grabbed from services only
+func (s *BannerScanner) SupportsTargetType(targetType string) bool {
+	return false
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *BannerScanner) SupportsServices() bool {
+	return true
+}
+
+// ProbesServices reports that the scanner reads the banner the service sends
+func (s *BannerScanner) ProbesServices(target interface{}, params models.JSONB) bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *BannerScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"timeout":  integerParam("Connect and read timeout in seconds", 1, 0),
+		"protocol": enumParam("Protocol to speak instead of guessing it from the service name and port", "ssh", "ftp", "smtp", "pop3", "imap", "mysql", "postgresql", "redis", "rdp"),
+		"tls":      booleanParam("Wrap the connection in TLS before speaking the protocol"),
+	})
+}
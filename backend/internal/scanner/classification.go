@@ -0,0 +1,121 @@
+// internal/scanner/classification.go
+package scanner
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"backend/internal/models"
+)
+
+// cvePattern matches CVE ids in free text such as script output
+var cvePattern = regexp.MustCompile(`(?i)\bCVE-\d{4}-\d{4,7}\b`)
+
+// cwePattern matches a CWE id written as CWE-<number> or as a plain number
+var cwePattern = regexp.MustCompile(`(?i)^(?:CWE-)?(\d+)$`)
+
+// findingTypeCWEs are the weaknesses behind the finding types of the built-in scanners
+var findingTypeCWEs = map[string][]string{
+	"tls_certificate_expired":       {"CWE-298"},
+	"tls_certificate_not_yet_valid": {"CWE-298"},
+	"tls_certificate_self_signed":   {"CWE-295"},
+	"tls_certificate_untrusted":     {"CWE-295"},
+	"tls_hostname_mismatch":         {"CWE-297"},
+	"tls_weak_key":                  {"CWE-326"},
+	"tls_weak_signature":            {"CWE-328"},
+	"tls_legacy_protocol":           {"CWE-327"},
+	"tls_weak_cipher":               {"CWE-327"},
+	"smtp_starttls_missing":         {"CWE-319"},
+	"smtp_starttls_failed":          {"CWE-319"},
+	"smtp_open_relay":               {"CWE-284"},
+	"smtp_user_enumeration":         {"CWE-204"},
+	"ssh_protocol_v1":               {"CWE-327"},
+	"ssh_weak_kex_algorithm":        {"CWE-327"},
+	"ssh_weak_host_key":             {"CWE-326"},
+	"ssh_weak_cipher":               {"CWE-327"},
+	"ssh_weak_mac":                  {"CWE-328"},
+	"ssh_password_authentication":   {"CWE-307"},
+	"ssh_host_key_reuse":            {"CWE-321"},
+	"dns_zone_transfer":             {"CWE-200"},
+	"exposed_vcs_repository":        {"CWE-527"},
+	"exposed_sensitive_file":        {"CWE-538"},
+	"exposed_backup_file":           {"CWE-530"},
+	"exposed_debug_endpoint":        {"CWE-215"},
+	"security_headers":              {"CWE-693"},
+}
+
+// Classify normalizes the vulnerability fields of a finding and adds the weakness of its finding type
+// when the scanner didn't name one
+func Classify(finding *models.Finding) {
+	finding.CVEIDs = normalizeCVEIDs(finding.CVEIDs)
+	finding.CWEIDs = normalizeCWEIDs(finding.CWEIDs)
+	if len(finding.CWEIDs) == 0 {
+		finding.CWEIDs = append(models.StringArray(nil), findingTypeCWEs[finding.FindingType]...)
+	}
+	if finding.CVSSVersion == "" {
+		finding.CVSSVersion = cvssVersion(finding.CVSSVector)
+	}
+}
+
+// extractCVEIDs returns the distinct CVE ids mentioned in text
+func extractCVEIDs(text string) models.StringArray {
+	return normalizeCVEIDs(cvePattern.FindAllString(text, -1))
+}
+
+// normalizeCVEIDs upper cases and deduplicates CVE ids, dropping anything that isn't one
+func normalizeCVEIDs(ids []string) models.StringArray {
+	var result models.StringArray
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		id = strings.ToUpper(strings.TrimSpace(id))
+		if !cvePattern.MatchString(id) || seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// normalizeCWEIDs writes CWE ids as CWE-<number> and deduplicates them
+func normalizeCWEIDs(ids []string) models.StringArray {
+	var result models.StringArray
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		match := cwePattern.FindStringSubmatch(strings.TrimSpace(id))
+		if match == nil {
+			continue
+		}
+		id = "CWE-" + match[1]
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		result = append(result, id)
+	}
+	return result
+}
+
+// cvssVersion returns the CVSS version a vector is written in. Version 3 and 4 vectors start
+// with their version, version 2 vectors are recognized by their Au metric.
+func cvssVersion(vector string) string {
+	vector = strings.TrimSpace(vector)
+	if strings.HasPrefix(vector, "CVSS:") {
+		version, _, _ := strings.Cut(strings.TrimPrefix(vector, "CVSS:"), "/")
+		return version
+	}
+	if strings.Contains(vector, "Au:") {
+		return "2.0"
+	}
+	return ""
+}
+
+// setCVSS sets the CVSS score and vector of a finding, ignoring scores outside of 0 to 10
+func setCVSS(finding *models.Finding, score string, vector string) {
+	if value, err := strconv.ParseFloat(strings.TrimSpace(score), 64); err == nil && value >= 0 && value <= 10 {
+		finding.CVSSScore = &value
+	}
+	finding.CVSSVector = strings.TrimSpace(vector)
+	finding.CVSSVersion = cvssVersion(finding.CVSSVector)
+}
@@ -0,0 +1,1097 @@
+// internal/scanner/command.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+)
+
+// maxCommandRecords caps how many output records a single command run may produce
+const maxCommandRecords = 10000
+
+// commandPlaceholder matches {{name}} placeholders in command and mapping templates
+var commandPlaceholder = regexp.MustCompile(`\{\{\s*([^{}]+?)\s*\}\}`)
+
+// CommandScanner implements the Scanner interface for running arbitrary command-line tools.
+// The command, its output format and how output records map to results are all taken from
+// the scan config parameters:
+//
+//	command        command line with {{target}}, {{host}}, {{port}}, {{url}}, {{protocol}},
+//	               {{service}}, {{target_type}} and {{output_file}} placeholders
+//	args           the command as a list of arguments, used instead of command
+//	output_format  json, jsonl, xml or lines (default json)
+//	records_path   dotted path to the record list in JSON output, or the record element name in XML output
+//	line_pattern   regular expression with named groups used to split lines into fields
+//	timeout        command timeout in seconds
+//	mapping        field mappings for findings, services, targets and dns_records
+type CommandScanner struct {
+	timeout   int
+	allowlist []string
+}
+
+// CommandTarget is the host, and optionally the service, a command is run against
+type CommandTarget struct {
+	Value      string
+	TargetType string
+	Port       int
+	Protocol   string
+	Service    string
+	IsService  bool
+}
+
+// NewCommandScanner creates a new command scanner
+func NewCommandScanner() *CommandScanner {
+	// Executables scan configs may run, nothing runs without an allowlist
+	var allowlist []string
+	for _, entry := range strings.Split(os.Getenv("COMMAND_SCANNER_ALLOWLIST"), ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			allowlist = append(allowlist, entry)
+		}
+	}
+
+	return &CommandScanner{
+		timeout:   600, // Default timeout in seconds (10 minutes)
+		allowlist: allowlist,
+	}
+}
+
+// Initialize checks that the worker allows any commands, the command itself is part of the scan parameters
+func (s *CommandScanner) Initialize(ctx context.Context) error {
+	if len(s.allowlist) == 0 {
+		return errors.New("command scanner is disabled, set COMMAND_SCANNER_ALLOWLIST to the executables scan configs may run")
+	}
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for the command scanner
+func (s *CommandScanner) ConvertTarget(target models.Target) interface{} {
+	switch target.TargetType {
+	case models.TargetTypeIP, models.TargetTypeDomain, models.TargetTypeCIDR:
+		return CommandTarget{
+			Value:      target.Value,
+			TargetType: target.TargetType,
+		}
+	default:
+		return nil
+	}
+}
+
+// ConvertService converts a Service to a format suitable for the command scanner
+func (s *CommandScanner) ConvertService(service models.Service) interface{} {
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return nil
+	}
+
+	targetType := models.TargetTypeDomain
+	if net.ParseIP(host) != nil {
+		targetType = models.TargetTypeIP
+	}
+
+	return CommandTarget{
+		Value:      host,
+		TargetType: targetType,
+		Port:       service.Port,
+		Protocol:   service.Protocol,
+		Service:    service.ServiceName,
+		IsService:  true,
+	}
+}
+
+// Scan runs the configured command against the target and maps its output to results
+func (s *CommandScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	cmdTarget, ok := target.(CommandTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for command scanner")
+	}
+
+	// Configure command parameters
+	timeout := s.timeout
+	outputFormat := "json"
+	recordsPath := ""
+	linePattern := ""
+
+	// Override with provided parameters if available
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["output_format"].(string); ok && val != "" {
+		outputFormat = strings.ToLower(val)
+	}
+	if val, ok := params["records_path"].(string); ok {
+		recordsPath = val
+	}
+	if val, ok := params["line_pattern"].(string); ok {
+		linePattern = val
+	}
+
+	mapping, ok := params["mapping"].(map[string]interface{})
+	if !ok || len(mapping) == 0 {
+		return nil, fmt.Errorf("command scanner requires a mapping parameter")
+	}
+
+	var template []string
+	if val, ok := params["args"].([]interface{}); ok && len(val) > 0 {
+		for _, arg := range val {
+			template = append(template, stringifyValue(arg))
+		}
+	} else if val, ok := params["command"].(string); ok && strings.TrimSpace(val) != "" {
+		args, err := splitCommandLine(val)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command: %w", err)
+		}
+		template = args
+	} else {
+		return nil, fmt.Errorf("command scanner requires a command or args parameter")
+	}
+
+	// Commands that write their results to a file get a temporary one
+	vars := s.templateVars(cmdTarget)
+	usesOutputFile := false
+	for _, arg := range template {
+		if strings.Contains(arg, "output_file") && commandPlaceholder.MatchString(arg) {
+			usesOutputFile = true
+		}
+	}
+	if usesOutputFile {
+		tmpFile, err := os.CreateTemp("", "zecas-command-*")
+		if err != nil {
+			return nil, fmt.Errorf("failed to create output file: %w", err)
+		}
+		tmpFile.Close()
+		defer os.Remove(tmpFile.Name())
+		vars["output_file"] = tmpFile.Name()
+	}
+
+	args := make([]string, 0, len(template))
+	for _, arg := range template {
+		rendered, err := renderCommandArg(arg, vars)
+		if err != nil {
+			return nil, err
+		}
+		args = append(args, rendered)
+	}
+
+	executable, err := s.checkAllowed(args[0])
+	if err != nil {
+		return nil, err
+	}
+	args[0] = executable
+
+	output, err := s.runCommand(ctx, args, time.Duration(timeout)*time.Second)
+	if err != nil {
+		return nil, err
+	}
+	if usesOutputFile {
+		output, err = os.ReadFile(vars["output_file"])
+		if err != nil {
+			return nil, fmt.Errorf("failed to read command output file: %w", err)
+		}
+	}
+
+	records, err := parseCommandOutput(output, outputFormat, recordsPath, linePattern)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s output of %s: %w", outputFormat, filepath.Base(args[0]), err)
+	}
+
+	return s.mapRecords(records, mapping, cmdTarget, vars, filepath.Base(args[0])), nil
+}
+
+// templateVars returns the placeholder values describing the scanned target
+func (s *CommandScanner) templateVars(target CommandTarget) map[string]string {
+	vars := map[string]string{
+		"target":      target.Value,
+		"host":        target.Value,
+		"target_type": target.TargetType,
+		"port":        "",
+		"protocol":    target.Protocol,
+		"service":     target.Service,
+		"url":         "http://" + target.Value,
+	}
+
+	if target.Port > 0 {
+		scheme := "http"
+		if strings.Contains(target.Service, "https") || strings.Contains(target.Service, "ssl") ||
+			target.Port == 443 || target.Port == 8443 {
+			scheme = "https"
+		}
+		vars["port"] = strconv.Itoa(target.Port)
+		vars["target"] = net.JoinHostPort(target.Value, vars["port"])
+		vars["url"] = fmt.Sprintf("%s://%s", scheme, vars["target"])
+	}
+
+	return vars
+}
+
+// checkAllowed verifies the executable exists and is permitted by COMMAND_SCANNER_ALLOWLIST, and returns
+// its absolute path. Executables are compared with the files the allowlist entries resolve to, so an
+// allowed name only permits the executable it resolves to on the worker.
+func (s *CommandScanner) checkAllowed(executable string) (string, error) {
+	if len(s.allowlist) == 0 {
+		return "", errors.New("command scanner is disabled, COMMAND_SCANNER_ALLOWLIST is not set")
+	}
+
+	path, info, err := resolveExecutable(executable)
+	if err != nil {
+		return "", fmt.Errorf("command %s not available: %w", executable, err)
+	}
+
+	for _, entry := range s.allowlist {
+		_, allowed, err := resolveExecutable(entry)
+		if err == nil && os.SameFile(info, allowed) {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("command %s is not in COMMAND_SCANNER_ALLOWLIST", executable)
+}
+
+// resolveExecutable returns the absolute path of the executable a command name runs and its file info
+func resolveExecutable(name string) (string, os.FileInfo, error) {
+	path, err := exec.LookPath(name)
+	if err != nil {
+		return "", nil, err
+	}
+	path, err = filepath.Abs(path)
+	if err != nil {
+		return "", nil, err
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", nil, err
+	}
+	return path, info, nil
+}
+
+// runCommand executes the command and returns its standard output
+func (s *CommandScanner) runCommand(ctx context.Context, args []string, timeout time.Duration) ([]byte, error) {
+	cmdCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(cmdCtx, args[0], args[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	err := cmd.Run()
+	if err != nil {
+		if cmdCtx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("command %s timed out after %s", filepath.Base(args[0]), timeout)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		// Many tools exit non-zero when they find something, so only fail without output
+		if stdout.Len() == 0 {
+			return nil, fmt.Errorf("command %s failed: %w (%s)", filepath.Base(args[0]), err, lastLines(stderr.String(), 5))
+		}
+		log.Printf("Command %s exited with %v, parsing its output anyway", filepath.Base(args[0]), err)
+	}
+
+	return stdout.Bytes(), nil
+}
+
+// mapRecords turns output records into results using the configured mapping
+func (s *CommandScanner) mapRecords(records []interface{}, mapping map[string]interface{}, target CommandTarget, vars map[string]string, commandName string) *models.ScanResults {
+	scanResults := &models.ScanResults{
+		Findings:        []models.Finding{},
+		NewTargets:      []models.Target{},
+		TargetRelations: []models.TargetRelation{},
+		Services:        []models.Service{},
+		DNSRecords:      []models.DNSRecord{},
+	}
+
+	findingMappings := mappingList(mapping["findings"])
+	serviceMappings := mappingList(mapping["services"])
+	targetMappings := mappingList(mapping["targets"])
+	dnsMappings := mappingList(mapping["dns_records"])
+
+	targetIDs := make(map[string]uuid.UUID)
+	serviceIDs := make(map[string]uuid.UUID)
+
+	// Targets first so services on discovered hosts can be linked to them
+	for _, record := range records {
+		for _, m := range targetMappings {
+			if !mappingApplies(m, record, vars) {
+				continue
+			}
+			newTarget, relation, ok := s.mapTarget(m, record, target, vars, commandName)
+			if !ok {
+				continue
+			}
+			key := newTarget.TargetType + "|" + newTarget.Value
+			if _, seen := targetIDs[key]; seen {
+				continue
+			}
+			targetIDs[key] = newTarget.ID
+			targetIDs[newTarget.Value] = newTarget.ID
+			scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
+			scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+		}
+	}
+
+	for _, record := range records {
+		for _, m := range serviceMappings {
+			if !mappingApplies(m, record, vars) {
+				continue
+			}
+			service, ok := s.mapService(m, record, target, vars, commandName)
+			if !ok {
+				continue
+			}
+			host := service.RawInfo["target_value"].(string)
+			if host != target.Value {
+				targetID, known := targetIDs[host]
+				if !known {
+					continue // Services can only be attached to the scanned or a discovered target
+				}
+				service.TargetID = targetID
+			}
+			key := fmt.Sprintf("%s|%d|%s", host, service.Port, service.Protocol)
+			if _, seen := serviceIDs[key]; seen {
+				continue
+			}
+			serviceIDs[key] = service.ID
+			scanResults.Services = append(scanResults.Services, service)
+		}
+	}
+
+	for _, record := range records {
+		for _, m := range dnsMappings {
+			if !mappingApplies(m, record, vars) {
+				continue
+			}
+			if dnsRecord, ok := s.mapDNSRecord(m, record, vars, commandName); ok {
+				scanResults.DNSRecords = append(scanResults.DNSRecords, dnsRecord)
+			}
+		}
+	}
+
+	for _, record := range records {
+		for _, m := range findingMappings {
+			if !mappingApplies(m, record, vars) {
+				continue
+			}
+			finding := s.mapFinding(m, record, target, vars, commandName)
+
+			// Link the finding to a service reported by the same command
+			if port, ok := mappedInt(m, "port", record, vars); ok && !target.IsService {
+				for _, protocol := range []string{"tcp", "udp"} {
+					if serviceID, exists := serviceIDs[fmt.Sprintf("%s|%d|%s", target.Value, port, protocol)]; exists {
+						id := serviceID
+						finding.ServiceID = &id
+						break
+					}
+				}
+			}
+
+			scanResults.Findings = append(scanResults.Findings, finding)
+		}
+	}
+
+	return scanResults
+}
+
+// mapFinding creates a finding from a record
+func (s *CommandScanner) mapFinding(m map[string]interface{}, record interface{}, target CommandTarget, vars map[string]string, commandName string) models.Finding {
+	title := mappedString(m, "title", record, vars)
+	if title == "" {
+		title = fmt.Sprintf("%s result on %s", commandName, vars["target"])
+	}
+
+	findingType := mappedString(m, "finding_type", record, vars)
+	if findingType == "" {
+		findingType = "command_" + commandName
+	}
+
+	details := models.JSONB{}
+	if detailMapping, ok := m["details"].(map[string]interface{}); ok {
+		for key, expr := range detailMapping {
+			details[key] = renderMappingValue(expr, record, vars)
+		}
+	} else {
+		details["record"] = record
+	}
+	details["command"] = commandName
+	details["host"] = target.Value
+	if target.Port > 0 {
+		details["port"] = target.Port
+	}
+
+	finding := models.Finding{
+		Title:       truncateString(title, 255),
+		Description: mappedString(m, "description", record, vars),
+		Severity:    normalizeSeverity(mappedString(m, "severity", record, vars)),
+		FindingType: sanitizeFindingType(findingType),
+		Details:     details,
+		CVEIDs:      normalizeCVEIDs(mappedStrings(m, "cve_ids", record, vars)),
+		CWEIDs:      normalizeCWEIDs(mappedStrings(m, "cwe_ids", record, vars)),
+		References:  mappedStrings(m, "references", record, vars),
+	}
+	setCVSS(&finding, mappedString(m, "cvss_score", record, vars), mappedString(m, "cvss_vector", record, vars))
+
+	return finding
+}
+
+// mapService creates a service from a record, requiring at least a port
+func (s *CommandScanner) mapService(m map[string]interface{}, record interface{}, target CommandTarget, vars map[string]string, commandName string) (models.Service, bool) {
+	port, ok := mappedInt(m, "port", record, vars)
+	if !ok || port <= 0 || port > 65535 {
+		return models.Service{}, false
+	}
+
+	protocol := strings.ToLower(mappedString(m, "protocol", record, vars))
+	if protocol == "" {
+		protocol = "tcp"
+	}
+
+	host := mappedString(m, "host", record, vars)
+	if host == "" {
+		host = target.Value
+	}
+
+	serviceName := mappedString(m, "service_name", record, vars)
+	if serviceName == "" {
+		if known, exists := wellKnownPorts[port]; exists {
+			serviceName = known
+		} else {
+			serviceName = "unknown"
+		}
+	}
+
+	title := mappedString(m, "title", record, vars)
+	if title == "" {
+		title = fmt.Sprintf("%s service on port %d", serviceName, port)
+	}
+
+	return models.Service{
+		ID:          uuid.New(),
+		TargetID:    uuid.Nil, // Will be set by worker
+		Port:        port,
+		Protocol:    protocol,
+		ServiceName: truncateString(serviceName, 100),
+		Version:     truncateString(mappedString(m, "version", record, vars), 100),
+		Title:       truncateString(title, 255),
+		Description: mappedString(m, "description", record, vars),
+		Banner:      mappedString(m, "banner", record, vars),
+		RawInfo: models.JSONB{
+			"target_value":  host,
+			"discovered_by": commandName,
+			"discovered_at": time.Now().Format(time.RFC3339),
+			"record":        record,
+		},
+	}, true
+}
+
+// mapTarget creates a new target and its relation to the scanned target from a record
+func (s *CommandScanner) mapTarget(m map[string]interface{}, record interface{}, target CommandTarget, vars map[string]string, commandName string) (models.Target, models.TargetRelation, bool) {
+	value := strings.TrimSuffix(strings.ToLower(mappedString(m, "value", record, vars)), ".")
+	if value == "" || value == strings.ToLower(target.Value) {
+		return models.Target{}, models.TargetRelation{}, false
+	}
+
+	targetType := strings.ToLower(mappedString(m, "target_type", record, vars))
+	switch targetType {
+	case models.TargetTypeIP, models.TargetTypeCIDR, models.TargetTypeDomain:
+	case "":
+		if isCIDRValue(value) {
+			targetType = models.TargetTypeCIDR
+		} else if net.ParseIP(value) != nil {
+			targetType = models.TargetTypeIP
+		} else {
+			targetType = models.TargetTypeDomain
+		}
+	default:
+		return models.Target{}, models.TargetRelation{}, false
+	}
+
+	relationType := mappedString(m, "relation", record, vars)
+	if relationType == "" {
+		switch {
+		case target.TargetType == models.TargetTypeDomain && targetType == models.TargetTypeIP:
+			relationType = models.RelationResolvesTo
+		case target.TargetType == models.TargetTypeDomain && targetType == models.TargetTypeDomain:
+			relationType = models.RelationParentOf
+		default:
+			relationType = "contains"
+		}
+	}
+
+	metadata := models.JSONB{
+		"discovered_from": target.Value,
+		"discovery_scan":  "command",
+		"discovered_by":   commandName,
+		"discovered_at":   time.Now().Format(time.RFC3339),
+	}
+	if metadataMapping, ok := m["metadata"].(map[string]interface{}); ok {
+		for key, expr := range metadataMapping {
+			metadata[key] = renderMappingValue(expr, record, vars)
+		}
+	}
+
+	newTarget := models.Target{
+		ID:         uuid.New(),
+		ProjectID:  uuid.Nil, // Will be set by worker
+		TargetType: targetType,
+		Value:      value,
+		Metadata:   metadata,
+	}
+
+	relation := models.TargetRelation{
+		ID:            uuid.New(),
+		SourceID:      uuid.Nil, // Will be set by worker
+		DestinationID: newTarget.ID,
+		RelationType:  relationType,
+		Metadata: models.JSONB{
+			"discovery_method": "command",
+			"command":          commandName,
+		},
+	}
+
+	return newTarget, relation, true
+}
+
+// mapDNSRecord creates a DNS record from a record, requiring a type and value
+func (s *CommandScanner) mapDNSRecord(m map[string]interface{}, record interface{}, vars map[string]string, commandName string) (models.DNSRecord, bool) {
+	recordType := strings.ToUpper(mappedString(m, "record_type", record, vars))
+	recordValue := mappedString(m, "record_value", record, vars)
+	if recordType == "" || recordValue == "" {
+		return models.DNSRecord{}, false
+	}
+
+	details := models.JSONB{
+		"source":  "command",
+		"command": commandName,
+	}
+	if name := mappedString(m, "name", record, vars); name != "" {
+		details["name"] = name
+	}
+	if ttl, ok := mappedInt(m, "ttl", record, vars); ok {
+		details["ttl"] = ttl
+	}
+
+	return models.DNSRecord{
+		ID:           uuid.New(),
+		ProjectID:    uuid.Nil, // Will be set by worker
+		TargetID:     uuid.Nil, // Will be set by worker
+		RecordType:   recordType,
+		RecordValue:  recordValue,
+		Details:      details,
+		DiscoveredAt: time.Now(),
+	}, true
+}
+
+// Type returns the scanner type identifier
+func (s *CommandScanner) Type() string {
+	return "command"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *CommandScanner) SupportsTargetType(targetType string) bool {
+	switch targetType {
+	case models.TargetTypeIP, models.TargetTypeDomain, models.TargetTypeCIDR:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *CommandScanner) SupportsServices() bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *CommandScanner) ParametersSchema() models.JSONB {
+	mapping := map[string]interface{}{
+		"type":        []string{"object", "array"},
+		"description": "Mapping object, or list of mapping objects, applied to every output record",
+	}
+
+	return parametersSchema(map[string]interface{}{
+		"command":       stringParam("Command line with {{target}}, {{host}}, {{port}}, {{url}}, {{protocol}}, {{service}}, {{target_type}} and {{output_file}} placeholders"),
+		"args":          stringListParam("Command as a list of arguments, used instead of command"),
+		"output_format": enumParam("Format of the command output, defaults to json", "json", "jsonl", "xml", "lines"),
+		"records_path":  stringParam("Dotted path to the records in JSON output, or the record element in XML output"),
+		"line_pattern":  stringParam("Regular expression with named groups applied to every output line"),
+		"timeout":       integerParam("Command timeout in seconds", 1, 0),
+		"mapping": map[string]interface{}{
+			"type":        "object",
+			"description": "Templates mapping output records to findings, services, targets and DNS records",
+			"properties": map[string]interface{}{
+				"findings":    mapping,
+				"services":    mapping,
+				"targets":     mapping,
+				"dns_records": mapping,
+			},
+			"additionalProperties": false,
+		},
+	}, "mapping")
+}
+
+// parseCommandOutput splits command output into records according to the output format
+func parseCommandOutput(output []byte, format string, recordsPath string, linePattern string) ([]interface{}, error) {
+	var records []interface{}
+
+	switch format {
+	case "json":
+		if len(bytes.TrimSpace(output)) == 0 {
+			return nil, nil
+		}
+		var doc interface{}
+		if err := json.Unmarshal(output, &doc); err != nil {
+			return nil, err
+		}
+		if recordsPath != "" {
+			value, ok := lookupField(doc, recordsPath)
+			if !ok {
+				return nil, fmt.Errorf("records path %s not found", recordsPath)
+			}
+			doc = value
+		}
+		if list, ok := doc.([]interface{}); ok {
+			records = list
+		} else if doc != nil {
+			records = []interface{}{doc}
+		}
+
+	case "jsonl":
+		scanner := bufio.NewScanner(bytes.NewReader(output))
+		scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" {
+				continue
+			}
+			var record interface{}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				continue // Tools often interleave log lines with results
+			}
+			records = append(records, record)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, err
+		}
+
+	case "xml":
+		list, err := parseXMLRecords(output, recordsPath)
+		if err != nil {
+			return nil, err
+		}
+		records = list
+
+	case "lines":
+		var pattern *regexp.Regexp
+		if linePattern != "" {
+			var err error
+			pattern, err = regexp.Compile(linePattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid line_pattern: %w", err)
+			}
+		}
+		for _, line := range strings.Split(string(output), "\n") {
+			line = strings.TrimSpace(line)
+			if line == "" {
+				continue
+			}
+			record := map[string]interface{}{"line": line}
+			if pattern != nil {
+				match := pattern.FindStringSubmatch(line)
+				if match == nil {
+					continue
+				}
+				for i, name := range pattern.SubexpNames() {
+					if name != "" {
+						record[name] = match[i]
+					}
+				}
+			}
+			records = append(records, record)
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, expected json, jsonl, xml or lines", format)
+	}
+
+	if len(records) > maxCommandRecords {
+		log.Printf("Command produced %d records, only the first %d are used", len(records), maxCommandRecords)
+		records = records[:maxCommandRecords]
+	}
+
+	return records, nil
+}
+
+// parseXMLRecords decodes every element named element, or every child of the root element
+// when element is empty, into a generic map
+func parseXMLRecords(data []byte, element string) ([]interface{}, error) {
+	var records []interface{}
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	depth := 0
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			if (element != "" && t.Name.Local == element) || (element == "" && depth == 1) {
+				record, err := decodeXMLElement(decoder, t)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, record)
+				continue
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+
+	return records, nil
+}
+
+// decodeXMLElement converts an element into a map of its attributes, child elements and text
+func decodeXMLElement(decoder *xml.Decoder, start xml.StartElement) (interface{}, error) {
+	node := map[string]interface{}{}
+	for _, attr := range start.Attr {
+		node[attr.Name.Local] = attr.Value
+	}
+
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := decodeXMLElement(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			if existing, ok := node[t.Name.Local]; ok {
+				if list, isList := existing.([]interface{}); isList {
+					node[t.Name.Local] = append(list, child)
+				} else {
+					node[t.Name.Local] = []interface{}{existing, child}
+				}
+			} else {
+				node[t.Name.Local] = child
+			}
+		case xml.CharData:
+			text.Write(t)
+		case xml.EndElement:
+			content := strings.TrimSpace(text.String())
+			if len(node) == 0 {
+				return content, nil // Plain text elements become strings
+			}
+			if content != "" {
+				node["text"] = content
+			}
+			return node, nil
+		}
+	}
+}
+
+// mappingList accepts a single mapping object or a list of them
+func mappingList(value interface{}) []map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return []map[string]interface{}{v}
+	case []interface{}:
+		var list []map[string]interface{}
+		for _, item := range v {
+			if m, ok := item.(map[string]interface{}); ok {
+				list = append(list, m)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+// mappingApplies evaluates the optional "when" template of a mapping against a record
+func mappingApplies(m map[string]interface{}, record interface{}, vars map[string]string) bool {
+	if _, ok := m["when"]; !ok {
+		return true
+	}
+
+	switch strings.ToLower(mappedString(m, "when", record, vars)) {
+	case "", "false", "0", "null", "[]", "{}":
+		return false
+	default:
+		return true
+	}
+}
+
+// mappedString renders a mapping field as a string
+func mappedString(m map[string]interface{}, key string, record interface{}, vars map[string]string) string {
+	expr, ok := m[key]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(stringifyValue(renderMappingValue(expr, record, vars)))
+}
+
+// mappedStrings renders a mapping field as a list of strings, splitting strings on commas and whitespace
+func mappedStrings(m map[string]interface{}, key string, record interface{}, vars map[string]string) []string {
+	expr, ok := m[key]
+	if !ok {
+		return nil
+	}
+
+	var values []string
+	switch value := renderMappingValue(expr, record, vars).(type) {
+	case []interface{}:
+		for _, item := range value {
+			values = append(values, stringifyValue(item))
+		}
+	default:
+		values = strings.FieldsFunc(stringifyValue(value), func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+	}
+
+	var result []string
+	for _, item := range values {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+// mappedInt renders a mapping field as an integer
+func mappedInt(m map[string]interface{}, key string, record interface{}, vars map[string]string) (int, bool) {
+	value := mappedString(m, key, record, vars)
+	if value == "" {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.Split(value, ".")[0])
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+// renderMappingValue resolves a mapping expression against a record. A string consisting of a
+// single {{path}} placeholder keeps the type of the referenced value, other strings have their
+// placeholders substituted and anything else is used as a literal.
+func renderMappingValue(expr interface{}, record interface{}, vars map[string]string) interface{} {
+	template, ok := expr.(string)
+	if !ok {
+		return expr
+	}
+
+	if match := commandPlaceholder.FindStringSubmatch(template); match != nil && match[0] == template {
+		if value, found := lookupField(record, match[1]); found {
+			return value
+		}
+		return vars[match[1]]
+	}
+
+	return commandPlaceholder.ReplaceAllStringFunc(template, func(placeholder string) string {
+		path := commandPlaceholder.FindStringSubmatch(placeholder)[1]
+		if value, found := lookupField(record, path); found {
+			return stringifyValue(value)
+		}
+		return vars[path]
+	})
+}
+
+// renderCommandArg substitutes the target placeholders in a command argument
+func renderCommandArg(arg string, vars map[string]string) (string, error) {
+	var unknown error
+	rendered := commandPlaceholder.ReplaceAllStringFunc(arg, func(placeholder string) string {
+		name := commandPlaceholder.FindStringSubmatch(placeholder)[1]
+		value, ok := vars[name]
+		if !ok && unknown == nil {
+			unknown = fmt.Errorf("unknown placeholder {{%s}} in command", name)
+		}
+		return value
+	})
+	return rendered, unknown
+}
+
+// lookupField resolves a dotted path such as "info.severity" or "hosts.0.ip" in a decoded record
+func lookupField(record interface{}, path string) (interface{}, bool) {
+	current := record
+	remaining := path
+
+	for remaining != "" {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			// Keys may themselves contain dots, so prefer an exact match on the remaining path
+			if value, ok := node[remaining]; ok {
+				return value, true
+			}
+			key, rest, _ := strings.Cut(remaining, ".")
+			value, ok := node[key]
+			if !ok {
+				return nil, false
+			}
+			current, remaining = value, rest
+		case []interface{}:
+			key, rest, _ := strings.Cut(remaining, ".")
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, false
+			}
+			current, remaining = node[index], rest
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
+// stringifyValue converts a decoded JSON value into its string form
+func stringifyValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprint(v)
+		}
+		return string(encoded)
+	}
+}
+
+// normalizeSeverity maps tool specific severity names onto the finding severities
+func normalizeSeverity(severity string) string {
+	switch strings.ToLower(strings.TrimSpace(severity)) {
+	case "critical", "crit", "fatal":
+		return models.SeverityCritical
+	case "high", "error", "severe":
+		return models.SeverityHigh
+	case "medium", "moderate", "warning", "warn":
+		return models.SeverityMedium
+	case "low", "minor":
+		return models.SeverityLow
+	case "unknown":
+		return models.SeverityUnknown
+	default:
+		return models.SeverityInfo
+	}
+}
+
+// sanitizeFindingType turns a value into a snake_case finding type that fits the database column
+func sanitizeFindingType(value string) string {
+	var b strings.Builder
+	lastUnderscore := false
+	for _, r := range strings.ToLower(value) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			lastUnderscore = false
+		} else if !lastUnderscore {
+			b.WriteRune('_')
+			lastUnderscore = true
+		}
+	}
+	return truncateString(strings.Trim(b.String(), "_"), 50)
+}
+
+// splitCommandLine splits a command line into arguments, honouring quotes and backslash escapes
+func splitCommandLine(line string) ([]string, error) {
+	var args []string
+	var current strings.Builder
+	inArg := false
+	var quote rune
+	escaped := false
+
+	for _, r := range line {
+		switch {
+		case escaped:
+			current.WriteRune(r)
+			escaped = false
+		case r == '\\' && quote != '\'':
+			escaped = true
+			inArg = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '\'' || r == '"':
+			quote = r
+			inArg = true
+		case r == ' ' || r == '\t' || r == '\n':
+			if inArg {
+				args = append(args, current.String())
+				current.Reset()
+				inArg = false
+			}
+		default:
+			current.WriteRune(r)
+			inArg = true
+		}
+	}
+
+	if quote != 0 || escaped {
+		return nil, errors.New("unterminated quote or escape")
+	}
+	if inArg {
+		args = append(args, current.String())
+	}
+	if len(args) == 0 {
+		return nil, errors.New("empty command")
+	}
+
+	return args, nil
+}
+
+// truncateString shortens a string to at most max characters
+func truncateString(value string, max int) string {
+	runes := []rune(value)
+	if len(runes) <= max {
+		return value
+	}
+	return string(runes[:max])
+}
+
+// lastLines returns the last n non-empty lines of a string
+func lastLines(value string, n int) string {
+	lines := strings.Split(strings.TrimSpace(value), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,745 @@
+// internal/scanner/contentdiscovery.go
+package scanner
+
+import (
+	"backend/internal/datadir"
+	"backend/internal/models"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxContentBodySize caps how much of a response body is read to measure it
+const maxContentBodySize = 5 << 20
+
+// defaultContentWordlist is used when no wordlist is configured, it focuses on paths worth reporting
+var defaultContentWordlist = []string{
+	".git/HEAD", ".git/config", ".svn/entries", ".hg/requires", ".bzr/README",
+	".env", ".env.local", ".env.production", ".env.backup", ".htaccess", ".htpasswd",
+	".DS_Store", ".aws/credentials", ".npmrc", ".dockercfg", ".bash_history", "id_rsa",
+	"web.config", "config.json", "config.yml", "config.yaml", "settings.py", "docker-compose.yml",
+	"wp-config.php.bak", "wp-config.php.old", "index.php.bak", "backup", "backups",
+	"backup.zip", "backup.tar.gz", "backup.sql", "dump.sql", "database.sql", "db.sql",
+	"site.zip", "www.zip", "old", "admin", "administrator", "wp-admin", "wp-login.php",
+	"phpmyadmin", "pma", "adminer.php", "manager/html", "cpanel", "webadmin", "console",
+	"dashboard", "login", "server-status", "server-info", "phpinfo.php", "info.php",
+	"actuator", "actuator/env", "actuator/health", "actuator/heapdump", "debug", "trace.axd",
+	"elmah.axd", "_profiler", "metrics", "swagger-ui.html", "swagger.json", "openapi.json",
+	"api-docs", "v2/api-docs", "graphql", "api", "cgi-bin/", "test", "dev", "tmp", "logs",
+	"private", "uploads", "files", "includes",
+}
+
+// contentRule classifies a discovered path as interesting
+type contentRule struct {
+	pattern     *regexp.Regexp
+	body        *regexp.Regexp // Content a successful response must contain, if set
+	category    string
+	label       string
+	findingType string
+	severity    string
+	description string
+}
+
+// contentRules are evaluated in order and the first matching rule wins
+var contentRules = []contentRule{
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)\.git/HEAD$`),
+		body:        regexp.MustCompile(`^(ref: |[0-9a-f]{40})`),
+		category:    "vcs",
+		label:       "Exposed Git repository",
+		findingType: "exposed_vcs_repository",
+		severity:    models.SeverityHigh,
+		description: "The Git metadata directory is served by the web server. The repository, including its history and any committed secrets, can usually be reconstructed from it.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)\.git/config$`),
+		body:        regexp.MustCompile(`\[core\]`),
+		category:    "vcs",
+		label:       "Exposed Git repository",
+		findingType: "exposed_vcs_repository",
+		severity:    models.SeverityHigh,
+		description: "The Git metadata directory is served by the web server. The repository, including its history and any committed secrets, can usually be reconstructed from it.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)\.(git|svn|hg|bzr)(/|$)`),
+		category:    "vcs",
+		label:       "Exposed version control metadata",
+		findingType: "exposed_vcs_repository",
+		severity:    models.SeverityHigh,
+		description: "Version control metadata is served by the web server and may expose source code and history.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)\.env([.\-_][a-z0-9]+)?$`),
+		body:        regexp.MustCompile(`(?m)^\s*[A-Za-z_][A-Za-z0-9_]*\s*=`),
+		category:    "secrets",
+		label:       "Exposed environment file",
+		findingType: "exposed_sensitive_file",
+		severity:    models.SeverityHigh,
+		description: "An environment file is served by the web server. These files commonly contain credentials, API keys and connection strings.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)(\.htpasswd|\.aws/credentials|\.npmrc|\.dockercfg|\.bash_history|id_rsa|id_dsa|id_ecdsa|id_ed25519)$`),
+		category:    "secrets",
+		label:       "Exposed sensitive file",
+		findingType: "exposed_sensitive_file",
+		severity:    models.SeverityHigh,
+		description: "A file that usually holds credentials or private keys is served by the web server.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)(\.htaccess|web\.config|config\.(json|ya?ml|php|inc)|settings\.py|docker-compose\.ya?ml|\.ds_store)$`),
+		category:    "config",
+		label:       "Exposed configuration file",
+		findingType: "exposed_sensitive_file",
+		severity:    models.SeverityMedium,
+		description: "A configuration or metadata file is served by the web server and may reveal internal paths, settings or credentials.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(\.(bak|old|orig|backup|save|swp|tmp|sql|dump|tar|tgz|gz|zip|rar|7z)|~)$`),
+		category:    "backup",
+		label:       "Exposed backup file",
+		findingType: "exposed_backup_file",
+		severity:    models.SeverityMedium,
+		description: "A backup or archive file is served by the web server. Backups often contain source code, database contents or credentials.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)(server-status|server-info|phpinfo\.php|info\.php|actuator(/.*)?|debug|trace\.axd|elmah\.axd|_profiler|metrics)/?$`),
+		category:    "debug",
+		label:       "Exposed debug endpoint",
+		findingType: "exposed_debug_endpoint",
+		severity:    models.SeverityMedium,
+		description: "A debugging or monitoring endpoint is reachable and may leak configuration, environment variables or request data.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)(swagger(-ui)?(\.html|\.json)?|openapi\.json|(v[0-9]/)?api-docs|graphql)/?$`),
+		category:    "api",
+		label:       "Exposed API documentation",
+		findingType: "exposed_api_documentation",
+		severity:    models.SeverityLow,
+		description: "API documentation or a GraphQL endpoint is publicly reachable, describing the attack surface of the application.",
+	},
+	{
+		pattern:     regexp.MustCompile(`(?i)(^|/)(admin|administrator|wp-admin|wp-login\.php|phpmyadmin|pma|adminer(\.php)?|manager/html|cpanel|webadmin|console|dashboard)/?$`),
+		category:    "admin",
+		label:       "Exposed administration interface",
+		findingType: "exposed_admin_interface",
+		severity:    models.SeverityLow,
+		description: "An administration interface is reachable. Management interfaces should not be exposed to untrusted networks.",
+	},
+}
+
+// ContentDiscoveryScanner implements the Scanner interface for brute forcing web content
+type ContentDiscoveryScanner struct {
+	wordlistPath string
+	concurrency  int
+	timeout      int
+	userAgent    string
+}
+
+// ContentTarget holds the candidate base URLs to brute force, the first reachable one is used
+type ContentTarget struct {
+	Host     string
+	BaseURLs []string
+}
+
+// contentResponse describes a response well enough to compare it with wildcard responses
+type contentResponse struct {
+	Path        string `json:"path"`
+	URL         string `json:"url"`
+	StatusCode  int    `json:"status_code"`
+	Size        int    `json:"content_length"`
+	Words       int    `json:"words"`
+	Lines       int    `json:"lines"`
+	ContentType string `json:"content_type,omitempty"`
+	Location    string `json:"redirect_location,omitempty"`
+	Category    string `json:"category,omitempty"`
+	body        []byte
+}
+
+// NewContentDiscoveryScanner creates a new content discovery scanner
+func NewContentDiscoveryScanner() *ContentDiscoveryScanner {
+	return &ContentDiscoveryScanner{
+		wordlistPath: os.Getenv("CONTENT_DISCOVERY_WORDLIST"), // Built-in wordlist when empty
+		concurrency:  20,                                      // Requests in flight
+		timeout:      10,                                      // Request timeout in seconds
+		userAgent:    "Zecas-ContentDiscovery",                // User agent sent with every request
+	}
+}
+
+// Initialize checks that the configured wordlist exists
+func (s *ContentDiscoveryScanner) Initialize(ctx context.Context) error {
+	if s.wordlistPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(s.wordlistPath); err != nil {
+		return fmt.Errorf("wordlist %s is not readable: %w", s.wordlistPath, err)
+	}
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for content discovery
+func (s *ContentDiscoveryScanner) ConvertTarget(target models.Target) interface{} {
+	baseURLs := webTargetURLs(target)
+	if baseURLs == nil {
+		return nil
+	}
+	return ContentTarget{Host: target.Value, BaseURLs: baseURLs}
+}
+
+// ConvertService converts a web Service to a format suitable for content discovery
+func (s *ContentDiscoveryScanner) ConvertService(service models.Service) interface{} {
+	baseURL := webServiceURL(service)
+	if baseURL == "" {
+		return nil
+	}
+	return ContentTarget{Host: service.RawInfo["target_value"].(string), BaseURLs: []string{baseURL}}
+}
+
+// Scan brute forces paths below the base URL and reports the interesting ones
+func (s *ContentDiscoveryScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	contentTarget, ok := target.(ContentTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for content discovery scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings: []models.Finding{},
+	}
+
+	// Configure scan parameters
+	wordlist := s.wordlistPath
+	var words []string
+	var extensions []string
+	matchStatus := []int{200, 204, 301, 302, 307, 308, 401, 403, 405}
+	var filterStatus, filterSize, filterWords []int
+	concurrency := s.concurrency
+	rateLimit := 0.0
+	timeout := s.timeout
+	userAgent := s.userAgent
+	autoCalibrate := true
+
+	// Override with provided parameters if available
+	if val, ok := params["url"].(string); ok && val != "" {
+		if !strings.HasSuffix(val, "/") {
+			val += "/"
+		}
+		contentTarget.BaseURLs = []string{val}
+	}
+	if val, ok := params["wordlist"].(string); ok && val != "" {
+		file, err := wordlistFile(val)
+		if err != nil {
+			return nil, err
+		}
+		wordlist = file
+	}
+	if val, ok := params["words"].([]interface{}); ok {
+		for _, word := range val {
+			if wordStr, ok := word.(string); ok && wordStr != "" {
+				words = append(words, wordStr)
+			}
+		}
+	}
+	if val, ok := params["extensions"].([]interface{}); ok {
+		for _, ext := range val {
+			if extStr, ok := ext.(string); ok && extStr != "" {
+				extensions = append(extensions, "."+strings.TrimPrefix(extStr, "."))
+			}
+		}
+	}
+	if val, ok := params["match_status"].([]interface{}); ok {
+		matchStatus = intList(val)
+	}
+	if val, ok := params["filter_status"].([]interface{}); ok {
+		filterStatus = intList(val)
+	}
+	if val, ok := params["filter_size"].([]interface{}); ok {
+		filterSize = intList(val)
+	}
+	if val, ok := params["filter_words"].([]interface{}); ok {
+		filterWords = intList(val)
+	}
+	if val, ok := params["concurrency"].(float64); ok && val > 0 {
+		concurrency = int(val)
+	}
+	if val, ok := params["rate_limit"].(float64); ok && val > 0 {
+		rateLimit = val
+	}
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["user_agent"].(string); ok && val != "" {
+		userAgent = val
+	}
+	if val, ok := params["auto_calibrate"].(bool); ok {
+		autoCalibrate = val
+	}
+
+	// Explicit words take precedence over the wordlist file
+	if len(words) == 0 {
+		var err error
+		words, err = s.loadWordlist(wordlist)
+		if err != nil {
+			return nil, err
+		}
+	}
+	paths := expandContentPaths(words, extensions)
+
+	httpClient := &http.Client{
+		Timeout: time.Duration(timeout) * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+			MaxIdleConnsPerHost: concurrency,
+		},
+		// Redirects are reported, not followed
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	// Use the first base URL that answers
+	var baseURL *url.URL
+	for _, candidate := range contentTarget.BaseURLs {
+		parsed, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+		if _, err := s.request(ctx, httpClient, parsed, "", userAgent); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		baseURL = parsed
+		break
+	}
+	if baseURL == nil {
+		return nil, fmt.Errorf("no web server reachable on %s", contentTarget.Host)
+	}
+
+	// Learn how the server answers paths that cannot exist
+	var calibration []contentResponse
+	if autoCalibrate {
+		calibration = s.calibrate(ctx, httpClient, baseURL, extensions, userAgent)
+	}
+
+	var limiter <-chan time.Time
+	if rateLimit > 0 {
+		ticker := time.NewTicker(time.Duration(float64(time.Second) / rateLimit))
+		defer ticker.Stop()
+		limiter = ticker.C
+	}
+
+	responses := make([]*contentResponse, len(paths))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(paths); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if limiter != nil {
+					select {
+					case <-ctx.Done():
+						continue
+					case <-limiter:
+					}
+				}
+				if resp, err := s.request(ctx, httpClient, baseURL, paths[i], userAgent); err == nil {
+					responses[i] = resp
+				}
+			}
+		}()
+	}
+
+	for i := range paths {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// Keep responses that pass the filters and don't look like the wildcard responses
+	var hits []contentResponse
+	for _, resp := range responses {
+		if resp == nil {
+			continue
+		}
+		if !containsInt(matchStatus, resp.StatusCode) || containsInt(filterStatus, resp.StatusCode) ||
+			containsInt(filterSize, resp.Size) || containsInt(filterWords, resp.Words) {
+			continue
+		}
+		if isWildcardResponse(*resp, calibration) {
+			continue
+		}
+		hit := *resp
+		hit.Location = strings.ReplaceAll(hit.Location, "FUZZ", hit.Path)
+		hits = append(hits, hit)
+	}
+
+	if len(hits) == 0 {
+		return scanResults, nil
+	}
+
+	for i := range hits {
+		rule := matchContentRule(hits[i])
+		if rule == nil {
+			continue
+		}
+		hits[i].Category = rule.category
+		scanResults.Findings = append(scanResults.Findings, s.createFinding(hits[i], *rule, baseURL))
+	}
+
+	scanResults.Findings = append(scanResults.Findings, s.createSummaryFinding(hits, baseURL, len(paths), calibration))
+
+	return scanResults, nil
+}
+
+// wordlistFile returns the path of a wordlist in WORDLIST_DIR (default ~/.zecas/wordlists), refusing
+// names that would point outside of it so scan configs can't send other files on the worker to a host
+func wordlistFile(name string) (string, error) {
+	dir := datadir.Dir("WORDLIST_DIR", "wordlists")
+	if name != filepath.Base(name) || strings.Contains(name, "\\") || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid wordlist %q, expected the name of a file in %s", name, dir)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// loadWordlist reads a wordlist file, or returns the built-in wordlist when no file is configured
+func (s *ContentDiscoveryScanner) loadWordlist(wordlist string) ([]string, error) {
+	if wordlist == "" {
+		return defaultContentWordlist, nil
+	}
+
+	file, err := os.Open(wordlist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+
+	return words, nil
+}
+
+// calibrate requests random paths to learn what the server returns for content that doesn't exist
+func (s *ContentDiscoveryScanner) calibrate(ctx context.Context, client *http.Client, baseURL *url.URL, extensions []string, userAgent string) []contentResponse {
+	token := randomContentToken()
+	probes := []string{token, token + "/", "." + token, "admin" + token, token + ".bak"}
+	for i, ext := range extensions {
+		if i == 3 {
+			break
+		}
+		probes = append(probes, token+ext)
+	}
+
+	var calibration []contentResponse
+	for _, probe := range probes {
+		resp, err := s.request(ctx, client, baseURL, probe, userAgent)
+		if err != nil {
+			continue
+		}
+		calibration = append(calibration, *resp)
+	}
+	return calibration
+}
+
+// request fetches a path below the base URL and measures the response
+func (s *ContentDiscoveryScanner) request(ctx context.Context, client *http.Client, baseURL *url.URL, contentPath string, userAgent string) (*contentResponse, error) {
+	target := baseURL.ResolveReference(&url.URL{Path: strings.TrimPrefix(contentPath, "/")})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxContentBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" && len(body) > 0 {
+		contentType = http.DetectContentType(body)
+	}
+
+	// Redirects that echo the requested path are compared with the path replaced
+	location := resp.Header.Get("Location")
+	if location != "" && contentPath != "" {
+		location = strings.ReplaceAll(location, contentPath, "FUZZ")
+	}
+
+	return &contentResponse{
+		Path:        contentPath,
+		URL:         target.String(),
+		StatusCode:  resp.StatusCode,
+		Size:        len(body),
+		Words:       len(bytes.Fields(body)),
+		Lines:       bytes.Count(body, []byte("\n")) + 1,
+		ContentType: strings.TrimSpace(strings.Split(contentType, ";")[0]),
+		Location:    location,
+		body:        body,
+	}, nil
+}
+
+// createFinding creates a finding for an interesting path
+func (s *ContentDiscoveryScanner) createFinding(hit contentResponse, rule contentRule, baseURL *url.URL) models.Finding {
+	// Paths that exist but can't be read are only worth noting
+	severity := rule.severity
+	if hit.StatusCode < 200 || hit.StatusCode >= 300 {
+		severity = models.SeverityInfo
+	}
+
+	description := fmt.Sprintf("%s\n\nThe server answered %s with HTTP %d (%d bytes", rule.description, hit.URL, hit.StatusCode, hit.Size)
+	if hit.ContentType != "" {
+		description += ", " + hit.ContentType
+	}
+	description += ")."
+	if hit.Location != "" {
+		description += fmt.Sprintf(" It redirects to %s.", hit.Location)
+	}
+
+	details := models.JSONB{
+		"url":            hit.URL,
+		"base_url":       baseURL.String(),
+		"path":           hit.Path,
+		"category":       rule.category,
+		"status_code":    hit.StatusCode,
+		"content_length": hit.Size,
+		"words":          hit.Words,
+		"lines":          hit.Lines,
+		"content_type":   hit.ContentType,
+	}
+	if hit.Location != "" {
+		details["redirect_location"] = hit.Location
+	}
+
+	return models.Finding{
+		Title:       fmt.Sprintf("%s at %s", rule.label, hit.URL),
+		Description: description,
+		Severity:    severity,
+		FindingType: rule.findingType,
+		Fingerprint: hit.URL,
+		Details:     details,
+	}
+}
+
+// createSummaryFinding lists every discovered path in a single informational finding
+func (s *ContentDiscoveryScanner) createSummaryFinding(hits []contentResponse, baseURL *url.URL, requests int, calibration []contentResponse) models.Finding {
+	sort.Slice(hits, func(i, j int) bool {
+		return hits[i].Path < hits[j].Path
+	})
+
+	var lines []string
+	for _, hit := range hits {
+		lines = append(lines, fmt.Sprintf("- /%s [%d, %d bytes]", strings.TrimPrefix(hit.Path, "/"), hit.StatusCode, hit.Size))
+	}
+
+	var wildcards []models.JSONB
+	for _, cal := range calibration {
+		wildcards = append(wildcards, models.JSONB{
+			"status_code":    cal.StatusCode,
+			"content_length": cal.Size,
+			"words":          cal.Words,
+			"lines":          cal.Lines,
+		})
+	}
+
+	return models.Finding{
+		Title:       fmt.Sprintf("Content discovery found %d paths on %s", len(hits), baseURL.String()),
+		Description: fmt.Sprintf("Brute forcing %d paths on %s found:\n%s", requests, baseURL.String(), strings.Join(lines, "\n")),
+		Severity:    models.SeverityInfo,
+		FindingType: "content_discovery",
+		Fingerprint: baseURL.String(),
+		Details: models.JSONB{
+			"url":         baseURL.String(),
+			"requests":    requests,
+			"paths":       hits,
+			"calibration": wildcards,
+			"scanned_at":  time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// matchContentRule returns the rule classifying a response as interesting, if any
+func matchContentRule(hit contentResponse) *contentRule {
+	for i := range contentRules {
+		rule := &contentRules[i]
+		if !rule.pattern.MatchString(hit.Path) {
+			continue
+		}
+		// A successful response must look like the file it claims to be
+		if rule.body != nil && hit.StatusCode >= 200 && hit.StatusCode < 300 && !rule.body.Match(hit.body) {
+			return nil
+		}
+		return rule
+	}
+	return nil
+}
+
+// isWildcardResponse reports whether a response looks like one of the calibration responses
+func isWildcardResponse(resp contentResponse, calibration []contentResponse) bool {
+	for _, cal := range calibration {
+		if resp.StatusCode != cal.StatusCode {
+			continue
+		}
+		if resp.Location != "" || cal.Location != "" {
+			if resp.Location == cal.Location {
+				return true
+			}
+			continue
+		}
+		if resp.Size == cal.Size || (resp.Words == cal.Words && resp.Lines == cal.Lines) {
+			return true
+		}
+	}
+	return false
+}
+
+// expandContentPaths appends every extension to words that don't have one yet
+func expandContentPaths(words []string, extensions []string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(p string) {
+		if !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+
+	for _, word := range words {
+		word = strings.TrimPrefix(word, "/")
+		if word == "" {
+			continue
+		}
+		add(word)
+		if strings.HasSuffix(word, "/") || path.Ext(word) != "" {
+			continue
+		}
+		for _, ext := range extensions {
+			add(word + ext)
+		}
+	}
+	return paths
+}
+
+// randomContentToken returns a path segment that won't exist on the server
+func randomContentToken() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("zecas%d", time.Now().UnixNano())
+	}
+	return hex.EncodeToString(buf)
+}
+
+// intList converts a decoded JSON list of numbers to ints
+func intList(values []interface{}) []int {
+	var list []int
+	for _, value := range values {
+		if num, ok := value.(float64); ok {
+			list = append(list, int(num))
+		}
+	}
+	return list
+}
+
+func containsInt(list []int, value int) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Type returns the scanner type identifier
+func (s *ContentDiscoveryScanner) Type() string {
+	return "contentdiscovery"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *ContentDiscoveryScanner) SupportsTargetType(targetType string) bool {
+	switch targetType {
+	case models.TargetTypeDomain, models.TargetTypeIP:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *ContentDiscoveryScanner) SupportsServices() bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *ContentDiscoveryScanner) ParametersSchema() models.JSONB {
+	statusList := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"description": description,
+			"items":       map[string]interface{}{"type": "integer", "minimum": 100, "maximum": 599},
+		}
+	}
+	sizeList := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"type":        "array",
+			"description": description,
+			"items":       map[string]interface{}{"type": "integer", "minimum": 0},
+		}
+	}
+
+	return parametersSchema(map[string]interface{}{
+		"url":            map[string]interface{}{"type": "string", "description": "Base URL to brute force instead of the target's root", "pattern": "^https?://"},
+		"wordlist":       stringParam("Name of a wordlist file in the worker's wordlist directory, defaults to a built-in list of sensitive paths"),
+		"words":          stringListParam("Paths to try instead of a wordlist"),
+		"extensions":     stringListParam("Extensions appended to every word without one, such as php or bak"),
+		"match_status":   statusList("Status codes reported, defaults to 200, 204, 301, 302, 307, 308, 401, 403 and 405"),
+		"filter_status":  statusList("Status codes never reported"),
+		"filter_size":    sizeList("Response sizes in bytes never reported"),
+		"filter_words":   sizeList("Response word counts never reported"),
+		"auto_calibrate": booleanParam("Filter responses that look like the answer to a random path, defaults to true"),
+		"concurrency":    integerParam("Requests in flight", 1, 200),
+		"rate_limit":     map[string]interface{}{"type": "number", "description": "Requests per second, unlimited by default", "minimum": 0.1},
+		"timeout":        integerParam("Request timeout in seconds", 1, 0),
+		"user_agent":     stringParam("User agent sent with every request"),
+	})
+}
@@ -0,0 +1,888 @@
+// internal/scanner/crawler.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+)
+
+// maxCrawlBodySize caps how much of a response body is read and parsed
+const maxCrawlBodySize = 2 << 20
+
+// crawlSkipExtensions are static assets that are neither fetched nor recorded
+var crawlSkipExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".svg": true, ".ico": true,
+	".webp": true, ".bmp": true, ".css": true, ".woff": true, ".woff2": true, ".ttf": true,
+	".eot": true, ".otf": true, ".mp4": true, ".mp3": true, ".webm": true, ".avi": true,
+}
+
+// crawlNoFetchExtensions are recorded as endpoints but not downloaded
+var crawlNoFetchExtensions = map[string]bool{
+	".pdf": true, ".zip": true, ".gz": true, ".tar": true, ".rar": true, ".7z": true,
+	".exe": true, ".dmg": true, ".iso": true, ".doc": true, ".docx": true, ".xls": true,
+	".xlsx": true, ".ppt": true, ".pptx": true,
+}
+
+// crawlScriptPath matches quoted absolute paths in JavaScript, such as "/api/v1/users"
+var crawlScriptPath = regexp.MustCompile(`["'\x60](/[A-Za-z0-9_\-./]{2,}(?:\?[A-Za-z0-9_\-=&.]*)?)["'\x60]`)
+
+// crawlSitemapLoc matches the locations listed in a sitemap
+var crawlSitemapLoc = regexp.MustCompile(`(?i)<loc>\s*([^<\s]+)\s*</loc>`)
+
+// CrawlerScanner implements the Scanner interface for mapping web applications
+type CrawlerScanner struct {
+	maxDepth  int
+	maxPages  int
+	rateLimit float64
+	timeout   int
+	userAgent string
+}
+
+// CrawlTarget holds the candidate base URLs of a web application, the first reachable one is crawled
+type CrawlTarget struct {
+	Host     string
+	BaseURLs []string
+}
+
+// crawlEndpoint is a URL discovered while crawling, without its query values
+type crawlEndpoint struct {
+	URL         string   `json:"url"`
+	Method      string   `json:"method"`
+	StatusCode  int      `json:"status_code,omitempty"`
+	ContentType string   `json:"content_type,omitempty"`
+	Parameters  []string `json:"parameters,omitempty"`
+	Source      string   `json:"source"`
+	Depth       int      `json:"depth"`
+}
+
+// crawlForm is an HTML form found on a crawled page
+type crawlForm struct {
+	Page   string           `json:"page"`
+	Action string           `json:"action"`
+	Method string           `json:"method"`
+	Inputs []crawlFormInput `json:"inputs"`
+}
+
+// crawlFormInput is a named field of an HTML form
+type crawlFormInput struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value,omitempty"`
+}
+
+// crawlItem is a queued URL together with how it was found
+type crawlItem struct {
+	url    *url.URL
+	depth  int
+	kind   string
+	source string
+}
+
+// crawlState tracks everything discovered during a single crawl
+type crawlState struct {
+	base        *url.URL
+	includeSubs bool
+	exclude     []*regexp.Regexp
+	queue       []crawlItem
+	queued      map[string]bool
+	endpoints   map[string]*crawlEndpoint
+	order       []string
+	forms       map[string]crawlForm
+	scripts     map[string]bool
+	disallowed  []string
+	title       string
+}
+
+// NewCrawlerScanner creates a new web crawler scanner
+func NewCrawlerScanner() *CrawlerScanner {
+	return &CrawlerScanner{
+		maxDepth:  3,               // Links followed from the start page
+		maxPages:  200,             // Requests made per application
+		rateLimit: 10,              // Requests per second
+		timeout:   10,              // Request timeout in seconds
+		userAgent: "Zecas-Crawler", // User agent sent with every request
+	}
+}
+
+// Initialize has nothing to set up since pages are fetched natively
+func (s *CrawlerScanner) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for the crawler
+func (s *CrawlerScanner) ConvertTarget(target models.Target) interface{} {
+	baseURLs := webTargetURLs(target)
+	if baseURLs == nil {
+		return nil
+	}
+	return CrawlTarget{Host: target.Value, BaseURLs: baseURLs}
+}
+
+// ConvertService converts a web Service to a format suitable for the crawler
+func (s *CrawlerScanner) ConvertService(service models.Service) interface{} {
+	baseURL := webServiceURL(service)
+	if baseURL == "" {
+		return nil
+	}
+	return CrawlTarget{Host: service.RawInfo["target_value"].(string), BaseURLs: []string{baseURL}}
+}
+
+// webTargetURLs returns the candidate base URLs of a domain or IP target, HTTPS first
+func webTargetURLs(target models.Target) []string {
+	switch target.TargetType {
+	case models.TargetTypeDomain, models.TargetTypeIP:
+		host := target.Value
+		if strings.Contains(host, ":") {
+			host = "[" + host + "]" // IPv6 literal
+		}
+		return []string{"https://" + host + "/", "http://" + host + "/"}
+	default:
+		return nil
+	}
+}
+
+// webServiceURL returns the base URL of a TCP service, or an empty string if the host is unknown
+func webServiceURL(service models.Service) string {
+	if service.Protocol != "" && service.Protocol != "tcp" {
+		return ""
+	}
+
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return ""
+	}
+
+	if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6 literal
+	}
+
+	scheme := "http"
+	if strings.Contains(service.ServiceName, "https") || strings.Contains(service.ServiceName, "ssl") ||
+		service.Port == 443 || service.Port == 8443 {
+		scheme = "https"
+	}
+
+	return fmt.Sprintf("%s://%s:%d/", scheme, host, service.Port)
+}
+
+// Scan crawls the web application and records its endpoints and forms
+func (s *CrawlerScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	crawlTarget, ok := target.(CrawlTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for crawler")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings:     []models.Finding{},
+		Applications: []models.Application{},
+	}
+
+	// Configure crawl parameters
+	maxDepth := s.maxDepth
+	maxPages := s.maxPages
+	rateLimit := s.rateLimit
+	timeout := s.timeout
+	userAgent := s.userAgent
+	includeSubdomains := false
+	useRobots := true
+	useSitemap := true
+	parseScripts := true
+	var exclude []*regexp.Regexp
+
+	// Override with provided parameters if available
+	if val, ok := params["max_depth"].(float64); ok && val >= 0 {
+		maxDepth = int(val)
+	}
+	if val, ok := params["max_pages"].(float64); ok && val > 0 {
+		maxPages = int(val)
+	}
+	if val, ok := params["rate_limit"].(float64); ok && val > 0 {
+		rateLimit = val
+	}
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["user_agent"].(string); ok && val != "" {
+		userAgent = val
+	}
+	if val, ok := params["include_subdomains"].(bool); ok {
+		includeSubdomains = val
+	}
+	if val, ok := params["robots"].(bool); ok {
+		useRobots = val
+	}
+	if val, ok := params["sitemap"].(bool); ok {
+		useSitemap = val
+	}
+	if val, ok := params["parse_scripts"].(bool); ok {
+		parseScripts = val
+	}
+	if val, ok := params["exclude"].([]interface{}); ok {
+		for _, pattern := range val {
+			patternStr, ok := pattern.(string)
+			if !ok || patternStr == "" {
+				continue
+			}
+			re, err := regexp.Compile(patternStr)
+			if err != nil {
+				return nil, fmt.Errorf("invalid exclude pattern %q: %w", patternStr, err)
+			}
+			exclude = append(exclude, re)
+		}
+	}
+
+	state := &crawlState{
+		includeSubs: includeSubdomains,
+		exclude:     exclude,
+		queued:      make(map[string]bool),
+		endpoints:   make(map[string]*crawlEndpoint),
+		forms:       make(map[string]crawlForm),
+		scripts:     make(map[string]bool),
+	}
+
+	httpClient := &http.Client{
+		Timeout: time.Duration(timeout) * time.Second,
+		Transport: &http.Transport{
+			// Certificates are inspected by the TLS scanner, the crawler only maps content
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 || !state.inScope(req.URL) {
+				return http.ErrUseLastResponse
+			}
+			return nil
+		},
+	}
+
+	interval := time.Duration(float64(time.Second) / rateLimit)
+	limiter := time.NewTicker(interval)
+	defer limiter.Stop()
+
+	// Find the first base URL that answers
+	var startPage *http.Response
+	var startBody []byte
+	for _, candidate := range crawlTarget.BaseURLs {
+		baseURL, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+
+		// Pin the scope to the candidate so redirects off the target aren't followed from the start page
+		state.base = baseURL
+		resp, body, err := s.fetch(ctx, httpClient, baseURL, userAgent)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		if location, err := resp.Location(); err == nil && !state.inScope(location) {
+			continue // Only redirects to another site, there's nothing to crawl here
+		}
+		state.base = resp.Request.URL
+		startPage, startBody = resp, body
+		break
+	}
+	if startPage == nil {
+		return nil, fmt.Errorf("no web server reachable on %s", crawlTarget.Host)
+	}
+
+	pages := 1
+	state.handleResponse(crawlItem{url: state.base, kind: "page", source: "start"}, startPage, startBody, maxDepth, parseScripts)
+
+	if useRobots {
+		state.enqueue(state.resolve("/robots.txt"), 0, "robots", "robots")
+	}
+	if useSitemap {
+		state.enqueue(state.resolve("/sitemap.xml"), 0, "sitemap", "sitemap")
+	}
+
+	for len(state.queue) > 0 && pages < maxPages {
+		item := state.queue[0]
+		state.queue = state.queue[1:]
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-limiter.C:
+		}
+
+		resp, body, err := s.fetch(ctx, httpClient, item.url, userAgent)
+		pages++
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+
+		state.handleResponse(item, resp, body, maxDepth, parseScripts)
+	}
+
+	s.createResults(scanResults, state, crawlTarget, pages)
+	return scanResults, nil
+}
+
+// fetch requests a URL and returns the response with its, size limited, body
+func (s *CrawlerScanner) fetch(ctx context.Context, client *http.Client, target *url.URL, userAgent string) (*http.Response, []byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target.String(), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCrawlBodySize))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return resp, body, nil
+}
+
+// createResults stores the crawl on an application and summarizes it in a finding
+func (s *CrawlerScanner) createResults(scanResults *models.ScanResults, state *crawlState, target CrawlTarget, pages int) {
+	endpoints := make([]crawlEndpoint, 0, len(state.order))
+	for _, key := range state.order {
+		endpoints = append(endpoints, *state.endpoints[key])
+	}
+
+	formKeys := make([]string, 0, len(state.forms))
+	for key := range state.forms {
+		formKeys = append(formKeys, key)
+	}
+	sort.Strings(formKeys)
+	forms := make([]crawlForm, 0, len(formKeys))
+	for _, key := range formKeys {
+		forms = append(forms, state.forms[key])
+	}
+
+	scripts := make([]string, 0, len(state.scripts))
+	for script := range state.scripts {
+		scripts = append(scripts, script)
+	}
+	sort.Strings(scripts)
+
+	var parameters []string
+	seenParams := make(map[string]bool)
+	for _, endpoint := range endpoints {
+		for _, name := range endpoint.Parameters {
+			if !seenParams[name] {
+				seenParams[name] = true
+				parameters = append(parameters, name)
+			}
+		}
+	}
+	sort.Strings(parameters)
+
+	baseURL := state.base.Scheme + "://" + state.base.Host + "/"
+	name := state.title
+	if name == "" {
+		name = state.base.Host
+	}
+	crawledAt := time.Now().Format(time.RFC3339)
+
+	application := models.Application{
+		ID:          uuid.New(),
+		ProjectID:   uuid.Nil, // Will be set by worker
+		Name:        truncateString(name, 255),
+		Type:        "web",
+		URL:         baseURL,
+		Description: fmt.Sprintf("Web application at %s with %d endpoints and %d forms discovered by crawling %d pages", baseURL, len(endpoints), len(forms), pages),
+		Metadata: models.JSONB{
+			"source":            "crawler",
+			"crawled_at":        crawledAt,
+			"pages_crawled":     pages,
+			"endpoints":         endpoints,
+			"forms":             forms,
+			"scripts":           scripts,
+			"parameters":        parameters,
+			"robots_disallowed": state.disallowed,
+		},
+	}
+	scanResults.Applications = append(scanResults.Applications, application)
+
+	finding := models.Finding{
+		Title:       fmt.Sprintf("Crawled %s: %d endpoints, %d forms", baseURL, len(endpoints), len(forms)),
+		Description: s.generateFindingDescription(baseURL, endpoints, forms, parameters, pages),
+		Severity:    models.SeverityInfo,
+		FindingType: "web_crawl",
+		Details: models.JSONB{
+			"url":             baseURL,
+			"host":            target.Host,
+			"pages_crawled":   pages,
+			"endpoint_count":  len(endpoints),
+			"form_count":      len(forms),
+			"script_count":    len(scripts),
+			"parameter_names": parameters,
+			"crawled_at":      crawledAt,
+		},
+	}
+	scanResults.Findings = append(scanResults.Findings, finding)
+}
+
+// generateFindingDescription creates a human-readable summary of a crawl
+func (s *CrawlerScanner) generateFindingDescription(baseURL string, endpoints []crawlEndpoint, forms []crawlForm, parameters []string, pages int) string {
+	desc := fmt.Sprintf("Crawling %s made %d requests and discovered %d endpoints and %d forms.", baseURL, pages, len(endpoints), len(forms))
+
+	if len(parameters) > 0 {
+		desc += fmt.Sprintf("\n\nParameters: %s", strings.Join(parameters, ", "))
+	}
+
+	var formLines []string
+	for _, form := range forms {
+		var inputs []string
+		for _, input := range form.Inputs {
+			inputs = append(inputs, input.Name)
+		}
+		formLines = append(formLines, fmt.Sprintf("- %s %s (%s)", form.Method, form.Action, strings.Join(inputs, ", ")))
+	}
+	if len(formLines) > 0 {
+		desc += "\n\nForms:\n" + strings.Join(formLines, "\n")
+	}
+
+	return desc
+}
+
+// handleResponse records a fetched URL and queues whatever it links to
+func (st *crawlState) handleResponse(item crawlItem, resp *http.Response, body []byte, maxDepth int, parseScripts bool) {
+	finalURL := resp.Request.URL
+	contentType := resp.Header.Get("Content-Type")
+	if contentType == "" && len(body) > 0 {
+		contentType = http.DetectContentType(body)
+	}
+	mediaType := strings.ToLower(strings.TrimSpace(strings.Split(contentType, ";")[0]))
+
+	if resp.StatusCode == http.StatusNotFound && (item.kind == "robots" || item.kind == "sitemap") {
+		return
+	}
+
+	if endpoint := st.record(item.url, http.MethodGet, item.source, item.depth); endpoint != nil {
+		endpoint.StatusCode = resp.StatusCode
+		endpoint.ContentType = mediaType
+	}
+	if finalURL.String() != item.url.String() && st.inScope(finalURL) {
+		if endpoint := st.record(finalURL, http.MethodGet, "redirect", item.depth); endpoint != nil {
+			endpoint.StatusCode = resp.StatusCode
+			endpoint.ContentType = mediaType
+		}
+	}
+
+	// Out of scope redirect targets are recorded with their status but not followed
+	if resp.StatusCode >= 300 && resp.StatusCode < 400 {
+		return
+	}
+
+	switch item.kind {
+	case "robots":
+		st.parseRobots(body)
+	case "sitemap":
+		st.parseSitemap(body, item.depth)
+	case "script":
+		if parseScripts {
+			st.parseScript(finalURL, body, item.depth, maxDepth)
+		}
+	default:
+		if item.depth < maxDepth && strings.Contains(mediaType, "html") {
+			st.parseHTML(finalURL, body, item.depth, parseScripts)
+		} else if parseScripts && strings.Contains(mediaType, "javascript") {
+			st.parseScript(finalURL, body, item.depth, maxDepth)
+		}
+	}
+}
+
+// parseHTML queues links, scripts and frames and records the forms of a page
+func (st *crawlState) parseHTML(pageURL *url.URL, body []byte, depth int, parseScripts bool) {
+	doc, err := html.Parse(bytes.NewReader(body))
+	if err != nil {
+		return
+	}
+
+	base := pageURL
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "base":
+				if ref, err := url.Parse(htmlAttr(n, "href")); err == nil && htmlAttr(n, "href") != "" {
+					base = pageURL.ResolveReference(ref)
+				}
+			case "title":
+				if st.title == "" && depth == 0 && n.FirstChild != nil {
+					st.title = strings.TrimSpace(n.FirstChild.Data)
+				}
+			case "a", "area", "link":
+				if n.Data == "link" && !strings.Contains(strings.ToLower(htmlAttr(n, "rel")), "alternate") {
+					break
+				}
+				st.enqueue(resolveCrawlURL(base, htmlAttr(n, "href")), depth+1, "page", "link")
+			case "iframe", "frame":
+				st.enqueue(resolveCrawlURL(base, htmlAttr(n, "src")), depth+1, "page", "frame")
+			case "script":
+				if src := resolveCrawlURL(base, htmlAttr(n, "src")); src != nil {
+					if st.inScope(src) {
+						st.scripts[stripCrawlQuery(src)] = true
+					}
+					if parseScripts {
+						st.enqueue(src, depth+1, "script", "script")
+					}
+				} else if parseScripts && n.FirstChild != nil {
+					st.parseScript(base, []byte(n.FirstChild.Data), depth, depth+1)
+				}
+			case "form":
+				st.recordForm(pageURL, base, n)
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+}
+
+// recordForm records a form and its target endpoint with the names of its fields
+func (st *crawlState) recordForm(pageURL *url.URL, base *url.URL, n *html.Node) {
+	action := resolveCrawlURL(base, htmlAttr(n, "action"))
+	if htmlAttr(n, "action") == "" {
+		action = pageURL
+	}
+	if action == nil {
+		return
+	}
+
+	method := strings.ToUpper(htmlAttr(n, "method"))
+	if method != http.MethodPost {
+		method = http.MethodGet
+	}
+
+	var inputs []crawlFormInput
+	var collect func(*html.Node)
+	collect = func(c *html.Node) {
+		if c.Type == html.ElementNode {
+			switch c.Data {
+			case "input", "select", "textarea", "button":
+				if name := htmlAttr(c, "name"); name != "" {
+					inputType := htmlAttr(c, "type")
+					if inputType == "" {
+						inputType = c.Data
+						if c.Data == "input" {
+							inputType = "text"
+						}
+					}
+					input := crawlFormInput{Name: name, Type: strings.ToLower(inputType)}
+					if input.Type == "hidden" {
+						input.Value = htmlAttr(c, "value")
+					}
+					inputs = append(inputs, input)
+				}
+			}
+		}
+		for child := c.FirstChild; child != nil; child = child.NextSibling {
+			collect(child)
+		}
+	}
+	collect(n)
+
+	names := make([]string, 0, len(inputs))
+	for _, input := range inputs {
+		names = append(names, input.Name)
+	}
+
+	form := crawlForm{
+		Page:   pageURL.String(),
+		Action: action.String(),
+		Method: method,
+		Inputs: inputs,
+	}
+	key := method + " " + stripCrawlQuery(action) + " " + strings.Join(names, ",")
+	if _, exists := st.forms[key]; !exists {
+		st.forms[key] = form
+	}
+
+	if st.inScope(action) {
+		if endpoint := st.record(action, method, "form", 0); endpoint != nil {
+			endpoint.Parameters = mergeCrawlParams(endpoint.Parameters, names)
+		}
+	}
+}
+
+// parseScript queues the absolute paths referenced in JavaScript
+func (st *crawlState) parseScript(scriptURL *url.URL, body []byte, depth int, maxDepth int) {
+	if depth >= maxDepth {
+		return
+	}
+	for _, match := range crawlScriptPath.FindAllSubmatch(body, -1) {
+		candidate := string(match[1])
+		if strings.HasPrefix(candidate, "//") {
+			continue // Protocol relative URLs and comments
+		}
+		st.enqueue(resolveCrawlURL(scriptURL, candidate), depth+1, "page", "script")
+	}
+}
+
+// parseRobots queues the paths and sitemaps listed in robots.txt
+func (st *crawlState) parseRobots(body []byte) {
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	for scanner.Scan() {
+		line := strings.TrimSpace(strings.SplitN(scanner.Text(), "#", 2)[0])
+		field, value, found := strings.Cut(line, ":")
+		if !found {
+			continue
+		}
+		value = strings.TrimSpace(value)
+
+		switch strings.ToLower(strings.TrimSpace(field)) {
+		case "disallow", "allow":
+			// Wildcard rules are cut at the first wildcard
+			if i := strings.IndexAny(value, "*$"); i >= 0 {
+				value = value[:i]
+			}
+			if value == "" || value == "/" {
+				continue
+			}
+			if strings.ToLower(strings.TrimSpace(field)) == "disallow" {
+				st.disallowed = append(st.disallowed, value)
+			}
+			st.enqueue(st.resolve(value), 1, "page", "robots")
+		case "sitemap":
+			st.enqueue(st.resolve(value), 0, "sitemap", "robots")
+		}
+	}
+}
+
+// parseSitemap queues the locations of a sitemap or sitemap index
+func (st *crawlState) parseSitemap(body []byte, depth int) {
+	kind := "page"
+	if bytes.Contains(bytes.ToLower(body), []byte("<sitemapindex")) {
+		kind = "sitemap"
+	}
+	for _, match := range crawlSitemapLoc.FindAllSubmatch(body, -1) {
+		st.enqueue(st.resolve(html.UnescapeString(string(match[1]))), depth+1, kind, "sitemap")
+	}
+}
+
+// enqueue adds an in-scope URL to the crawl queue unless it was queued before
+func (st *crawlState) enqueue(target *url.URL, depth int, kind string, source string) {
+	if target == nil || !st.inScope(target) {
+		return
+	}
+
+	ext := strings.ToLower(path.Ext(target.Path))
+	if crawlSkipExtensions[ext] {
+		return
+	}
+	if crawlNoFetchExtensions[ext] {
+		st.record(target, http.MethodGet, source, depth)
+		return
+	}
+
+	key := kind + " " + crawlKey(target)
+	if st.queued[key] {
+		// Still pick up parameter names of URLs that were already seen
+		st.record(target, http.MethodGet, source, depth)
+		return
+	}
+	st.queued[key] = true
+	st.queue = append(st.queue, crawlItem{url: target, depth: depth, kind: kind, source: source})
+}
+
+// record adds or updates the endpoint for a URL and method
+func (st *crawlState) record(target *url.URL, method string, source string, depth int) *crawlEndpoint {
+	if !st.inScope(target) {
+		return nil
+	}
+
+	key := method + " " + stripCrawlQuery(target)
+	endpoint, exists := st.endpoints[key]
+	if !exists {
+		endpoint = &crawlEndpoint{
+			URL:    stripCrawlQuery(target),
+			Method: method,
+			Source: source,
+			Depth:  depth,
+		}
+		st.endpoints[key] = endpoint
+		st.order = append(st.order, key)
+	}
+
+	var names []string
+	for name := range target.Query() {
+		names = append(names, name)
+	}
+	endpoint.Parameters = mergeCrawlParams(endpoint.Parameters, names)
+
+	return endpoint
+}
+
+// resolve resolves a path or URL against the base URL
+func (st *crawlState) resolve(ref string) *url.URL {
+	return resolveCrawlURL(st.base, ref)
+}
+
+// inScope reports whether a URL belongs to the crawled application
+func (st *crawlState) inScope(target *url.URL) bool {
+	if target == nil || (target.Scheme != "http" && target.Scheme != "https") {
+		return false
+	}
+
+	if st.base != nil {
+		host := strings.ToLower(target.Hostname())
+		baseHost := strings.ToLower(st.base.Hostname())
+		switch {
+		case host == baseHost:
+			if crawlPort(target) != crawlPort(st.base) && !(isDefaultCrawlPort(target) && isDefaultCrawlPort(st.base)) {
+				return false
+			}
+		case st.includeSubs && strings.HasSuffix(host, "."+baseHost):
+		default:
+			return false
+		}
+	}
+
+	for _, re := range st.exclude {
+		if re.MatchString(target.String()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// resolveCrawlURL resolves a link found on a page, dropping fragments and non-HTTP links
+func resolveCrawlURL(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	if ref == "" || strings.HasPrefix(ref, "#") {
+		return nil
+	}
+
+	lower := strings.ToLower(ref)
+	for _, prefix := range []string{"mailto:", "javascript:", "tel:", "data:", "about:"} {
+		if strings.HasPrefix(lower, prefix) {
+			return nil
+		}
+	}
+
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return nil
+	}
+
+	resolved := base.ResolveReference(parsed)
+	resolved.Fragment = ""
+	if resolved.Path == "" {
+		resolved.Path = "/"
+	}
+	return resolved
+}
+
+// crawlKey identifies a URL by its path and parameter names so pagination isn't crawled endlessly
+func crawlKey(target *url.URL) string {
+	var names []string
+	for name := range target.Query() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return stripCrawlQuery(target) + "?" + strings.Join(names, "&")
+}
+
+// stripCrawlQuery returns the URL without its query string and fragment
+func stripCrawlQuery(target *url.URL) string {
+	stripped := *target
+	stripped.RawQuery = ""
+	stripped.Fragment = ""
+	return stripped.String()
+}
+
+// crawlPort returns the explicit or default port of a URL
+func crawlPort(target *url.URL) string {
+	if port := target.Port(); port != "" {
+		return port
+	}
+	if target.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
+
+// isDefaultCrawlPort reports whether a URL uses the default port of HTTP or HTTPS
+func isDefaultCrawlPort(target *url.URL) bool {
+	port := crawlPort(target)
+	return port == "80" || port == "443"
+}
+
+// mergeCrawlParams adds parameter names that are not in the list yet, keeping it sorted
+func mergeCrawlParams(existing []string, names []string) []string {
+	for _, name := range names {
+		if name != "" && !containsString(existing, name) {
+			existing = append(existing, name)
+		}
+	}
+	sort.Strings(existing)
+	return existing
+}
+
+// htmlAttr returns the value of an attribute of an HTML element
+func htmlAttr(n *html.Node, name string) string {
+	for _, attr := range n.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+// Type returns the scanner type identifier
+func (s *CrawlerScanner) Type() string {
+	return "crawler"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *CrawlerScanner) SupportsTargetType(targetType string) bool {
+	switch targetType {
+	case models.TargetTypeDomain, models.TargetTypeIP:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *CrawlerScanner) SupportsServices() bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *CrawlerScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"max_depth":          integerParam("Links followed from the start page, defaults to 3", 0, 0),
+		"max_pages":          integerParam("Maximum requests per application, defaults to 200", 1, 0),
+		"rate_limit":         map[string]interface{}{"type": "number", "description": "Requests per second, defaults to 10", "minimum": 0.1},
+		"timeout":            integerParam("Request timeout in seconds", 1, 0),
+		"user_agent":         stringParam("User agent sent with every request"),
+		"include_subdomains": booleanParam("Follow links to subdomains of the crawled host"),
+		"robots":             booleanParam("Read robots.txt for paths and sitemaps, defaults to true"),
+		"sitemap":            booleanParam("Read sitemap.xml, defaults to true"),
+		"parse_scripts":      booleanParam("Extract paths from JavaScript, defaults to true"),
+		"exclude":            stringListParam("Regular expressions of URLs that are not crawled"),
+	})
+}
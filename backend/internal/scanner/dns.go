@@ -6,16 +6,19 @@ import (
 	"context"
 	"fmt"
 	"net"
-	"os/exec"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/net/dns/dnsmessage"
 )
 
 // DNSScanner implements the Scanner interface for resolving DNS records
 type DNSScanner struct {
 	resolverTimeout int
+	nameservers     []string
+	srvNames        []string
 }
 
 // DNSRecordType defines the types of DNS records to check
@@ -23,34 +26,50 @@ type DNSRecordType string
 
 // DNS record types
 const (
-	RecordTypeA     DNSRecordType = "A"
-	RecordTypeAAAA  DNSRecordType = "AAAA"
-	RecordTypeCNAME DNSRecordType = "CNAME"
-	RecordTypeMX    DNSRecordType = "MX"
-	RecordTypeTXT   DNSRecordType = "TXT"
-	RecordTypeNS    DNSRecordType = "NS"
-	RecordTypeSOA   DNSRecordType = "SOA"
-	RecordTypePTR   DNSRecordType = "PTR"
+	RecordTypeA      DNSRecordType = "A"
+	RecordTypeAAAA   DNSRecordType = "AAAA"
+	RecordTypeCNAME  DNSRecordType = "CNAME"
+	RecordTypeMX     DNSRecordType = "MX"
+	RecordTypeTXT    DNSRecordType = "TXT"
+	RecordTypeNS     DNSRecordType = "NS"
+	RecordTypeSOA    DNSRecordType = "SOA"
+	RecordTypePTR    DNSRecordType = "PTR"
+	RecordTypeSRV    DNSRecordType = "SRV"
+	RecordTypeCAA    DNSRecordType = "CAA"
+	RecordTypeDS     DNSRecordType = "DS"
+	RecordTypeDNSKEY DNSRecordType = "DNSKEY"
 )
 
 // NewDNSScanner creates a new DNS resolver scanner
 func NewDNSScanner() *DNSScanner {
 	return &DNSScanner{
 		resolverTimeout: 5, // Default timeout in seconds
+		nameservers:     systemNameservers(),
+		srvNames: []string{
+			"_sip._tcp",
+			"_sip._udp",
+			"_sips._tcp",
+			"_xmpp-client._tcp",
+			"_xmpp-server._tcp",
+			"_ldap._tcp",
+			"_kerberos._tcp",
+			"_kerberos._udp",
+			"_autodiscover._tcp",
+			"_caldav._tcp",
+			"_carddav._tcp",
+			"_imap._tcp",
+			"_imaps._tcp",
+			"_submission._tcp",
+		},
 	}
 }
 
 // Initialize checks if DNS resolution is available
 func (s *DNSScanner) Initialize(ctx context.Context) error {
-	// Try to resolve a known domain to check if DNS resolution works
-	_, err := net.LookupHost("example.com")
-	if err != nil {
-		// Try an alternative check with the dig command
-		cmd := exec.CommandContext(ctx, "dig", "+short", "example.com")
-		output, err := cmd.CombinedOutput()
-		if err != nil || strings.TrimSpace(string(output)) == "" {
-			return fmt.Errorf("DNS resolution not available: %w", err)
-		}
+	// Query the configured nameservers directly to check if DNS resolution works
+	client := newDNSClient(s.nameservers, time.Duration(s.resolverTimeout)*time.Second)
+	if _, err := client.Query(ctx, "example.com", dnsmessage.TypeA); err != nil {
+		return fmt.Errorf("DNS resolution not available: %w", err)
 	}
 
 	return nil
@@ -96,6 +115,9 @@ func (s *DNSScanner) Scan(ctx context.Context, target interface{}, params models
 		RecordTypeMX,
 		RecordTypeTXT,
 		RecordTypeNS,
+		RecordTypeSOA,
+		RecordTypeCAA,
+		RecordTypeDNSKEY,
 	}
 
 	// Check if this is an IP for reverse lookup
@@ -106,17 +128,82 @@ func (s *DNSScanner) Scan(ctx context.Context, target interface{}, params models
 		recordTypes = []DNSRecordType{}
 		for _, t := range types {
 			if typeStr, ok := t.(string); ok {
-				recordTypes = append(recordTypes, DNSRecordType(typeStr))
+				recordTypes = append(recordTypes, DNSRecordType(strings.ToUpper(typeStr)))
 			}
 		}
 	}
 
+	// Use specific nameservers instead of the system resolvers if provided
+	nameservers := s.nameservers
+	if servers, ok := params["nameservers"].([]interface{}); ok && len(servers) > 0 {
+		nameservers = []string{}
+		for _, server := range servers {
+			if serverStr, ok := server.(string); ok {
+				nameservers = append(nameservers, serverStr)
+			}
+		}
+	}
+
+	timeout := s.resolverTimeout
+	if t, ok := params["timeout"].(float64); ok && t > 0 {
+		timeout = int(t)
+	}
+
+	srvNames := s.srvNames
+	if names, ok := params["srv_names"].([]interface{}); ok && len(names) > 0 {
+		srvNames = []string{}
+		for _, name := range names {
+			if nameStr, ok := name.(string); ok {
+				srvNames = append(srvNames, nameStr)
+			}
+		}
+	}
+
+	// Zone transfers are attempted against every authoritative nameserver by default
+	zoneTransfer := true
+	if zt, ok := params["zone_transfer"].(bool); ok {
+		zoneTransfer = zt
+	}
+
+	// Analyze SPF, DMARC, DKIM, MTA-STS and TLS-RPT for domain targets
+	emailSecurity := false
+	if es, ok := params["email_security"].(bool); ok {
+		emailSecurity = es
+	}
+
+	dkimSelectors := defaultDKIMSelectors
+	if selectors, ok := params["dkim_selectors"].([]interface{}); ok && len(selectors) > 0 {
+		dkimSelectors = []string{}
+		for _, selector := range selectors {
+			if selectorStr, ok := selector.(string); ok {
+				dkimSelectors = append(dkimSelectors, selectorStr)
+			}
+		}
+	}
+
+	client := newDNSClient(nameservers, time.Duration(timeout)*time.Second)
+
 	var dnsRecords []models.DNSRecord
 	var findings []models.Finding
 
 	if isIP {
 		// Perform reverse DNS lookup
-		names, err := net.LookupAddr(targetValue)
+		var names []string
+		response, err := client.Query(ctx, reverseDNSName(net.ParseIP(targetValue)), dnsmessage.TypePTR)
+		if err == nil {
+			for _, answer := range response.Answers {
+				if answer.Header.Type != dnsmessage.TypePTR {
+					continue
+				}
+				name := formatDNSResource(answer)
+				names = append(names, name)
+				dnsRecords = append(dnsRecords, s.createDNSRecord(string(RecordTypePTR), name, answer, response.Server, "query"))
+			}
+			if len(names) == 0 {
+				err = fmt.Errorf("no PTR records returned (%s)", response.RCode)
+			}
+		}
+
 		if err != nil {
 			finding := models.Finding{
 				Title:       fmt.Sprintf("No reverse DNS for %s", targetValue),
@@ -132,34 +219,8 @@ func (s *DNSScanner) Scan(ctx context.Context, target interface{}, params models
 		} else {
 			// Create targets for PTR records
 			for _, name := range names {
-				// Standardize name format (remove trailing dot)
-				name = strings.TrimSuffix(name, ".")
-
-				newTarget := models.Target{
-					ID:         uuid.New(),
-					ProjectID:  uuid.Nil, // Will be set by worker
-					TargetType: models.TargetTypeDomain,
-					Value:      name,
-					Metadata: models.JSONB{
-						"discovered_from": targetValue,
-						"discovery_scan":  "dns_ptr",
-						"discovered_at":   time.Now().Format(time.RFC3339),
-					},
-				}
-				scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
-
-				// Create relation between IP and hostname
-				relation := models.TargetRelation{
-					ID:            uuid.New(),
-					SourceID:      uuid.Nil, // Will be set by worker
-					DestinationID: newTarget.ID,
-					RelationType:  models.RelationResolvesTo,
-					Metadata: models.JSONB{
-						"discovered_at": time.Now().Format(time.RFC3339),
-						"record_type":   "PTR",
-					},
-				}
-				scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+				s.addDiscoveredTarget(scanResults, models.TargetTypeDomain, name, targetValue, "dns_ptr",
+					models.RelationResolvesTo, models.JSONB{"record_type": "PTR"}, nil)
 			}
 
 			finding := models.Finding{
@@ -175,225 +236,25 @@ func (s *DNSScanner) Scan(ctx context.Context, target interface{}, params models
 			findings = append(findings, finding)
 		}
 	} else {
+		var nameserverHosts []string
+		nsQueried := false
+		dnskeyMissing := false
+
 		// Process each requested record type
 		for _, recordType := range recordTypes {
-			var records []string
-			var err error
+			records, typeRecords, err := s.lookupRecords(ctx, client, targetValue, recordType, srvNames, scanResults)
+			dnsRecords = append(dnsRecords, typeRecords...)
 
-			// Lookup appropriate record type
-			switch recordType {
-			case RecordTypeA:
-				ips, err := net.LookupHost(targetValue)
-				if err == nil {
-					records = ips
-
-					// Create targets for A records (IPs)
-					for _, ip := range ips {
-						// Only create targets for IPv4 addresses
-						if net.ParseIP(ip).To4() != nil {
-							newTarget := models.Target{
-								ID:         uuid.New(),
-								ProjectID:  uuid.Nil, // Will be set by worker
-								TargetType: models.TargetTypeIP,
-								Value:      ip,
-								Metadata: models.JSONB{
-									"discovered_from": targetValue,
-									"discovery_scan":  "dns_a",
-									"discovered_at":   time.Now().Format(time.RFC3339),
-								},
-							}
-							scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
-
-							// Create relation between domain and IP
-							relation := models.TargetRelation{
-								ID:            uuid.New(),
-								SourceID:      uuid.Nil, // Will be set by worker
-								DestinationID: newTarget.ID,
-								RelationType:  models.RelationResolvesTo,
-								Metadata: models.JSONB{
-									"discovered_at": time.Now().Format(time.RFC3339),
-									"record_type":   "A",
-								},
-							}
-							scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
-
-							dnsRecord := models.DNSRecord{
-								RecordType:  (string)(recordType),
-								RecordValue: ip,
-							}
-							dnsRecords = append(dnsRecords, dnsRecord)
-						}
-					}
-				}
-			case RecordTypeAAAA:
-				// Filter only IPv6 addresses
-				ips, err := net.LookupIP(targetValue)
-				if err == nil {
-
-					for _, ip := range ips {
-						if ip.To4() == nil { // IPv6 addresses have To4() == nil
-							dnsRecord := models.DNSRecord{
-								RecordType:  (string)(recordType),
-								RecordValue: ip.String(),
-							}
-							dnsRecords = append(dnsRecords, dnsRecord)
-							records = append(records, ip.String())
-						}
-					}
-				}
-			case RecordTypeCNAME:
-				cname, err := net.LookupCNAME(targetValue)
-				if err == nil && cname != "" {
-					cname = strings.TrimSuffix(cname, ".")
-					records = []string{cname}
-
-					// Create target for CNAME
-					newTarget := models.Target{
-						ID:         uuid.New(),
-						ProjectID:  uuid.Nil, // Will be set by worker
-						TargetType: models.TargetTypeDomain,
-						Value:      cname,
-						Metadata: models.JSONB{
-							"discovered_from": targetValue,
-							"discovery_scan":  "dns_cname",
-							"discovered_at":   time.Now().Format(time.RFC3339),
-						},
-					}
-					scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
-
-					// Create relation between domain and CNAME
-					relation := models.TargetRelation{
-						ID:            uuid.New(),
-						SourceID:      uuid.Nil, // Will be set by worker
-						DestinationID: newTarget.ID,
-						RelationType:  models.RelationResolvesTo,
-						Metadata: models.JSONB{
-							"discovered_at": time.Now().Format(time.RFC3339),
-							"record_type":   "CNAME",
-						},
-					}
-					scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
-
-					dnsRecord := models.DNSRecord{
-						RecordType:  (string)(recordType),
-						RecordValue: cname,
-					}
-					dnsRecords = append(dnsRecords, dnsRecord)
-				}
-			case RecordTypeMX:
-				mxs, err := net.LookupMX(targetValue)
-				if err == nil {
-					for _, mx := range mxs {
-						mxHost := strings.TrimSuffix(mx.Host, ".")
-						records = append(records, fmt.Sprintf("%s (priority: %d)", mxHost, mx.Pref))
-
-						// Create target for MX hostname
-						newTarget := models.Target{
-							ID:         uuid.New(),
-							ProjectID:  uuid.Nil, // Will be set by worker
-							TargetType: models.TargetTypeDomain,
-							Value:      mxHost,
-							Metadata: models.JSONB{
-								"discovered_from": targetValue,
-								"discovery_scan":  "dns_mx",
-								"discovered_at":   time.Now().Format(time.RFC3339),
-								"mx_priority":     mx.Pref,
-							},
-						}
-						scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
-
-						// Create relation between domain and MX host
-						relation := models.TargetRelation{
-							ID:            uuid.New(),
-							SourceID:      uuid.Nil, // Will be set by worker
-							DestinationID: newTarget.ID,
-							RelationType:  models.RelationResolvesTo,
-							Metadata: models.JSONB{
-								"discovered_at": time.Now().Format(time.RFC3339),
-								"record_type":   "MX",
-								"priority":      mx.Pref,
-							},
-						}
-						scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
-
-						dnsRecord := models.DNSRecord{
-							RecordType:  (string)(recordType),
-							RecordValue: fmt.Sprintf("%s (priority: %d)", mxHost, mx.Pref),
-						}
-						dnsRecords = append(dnsRecords, dnsRecord)
-					}
-				}
-			case RecordTypeTXT:
-				txts, err := net.LookupTXT(targetValue)
-				if err == nil {
-					records = txts
-					for _, txt := range txts {
-						dnsRecord := models.DNSRecord{
-							RecordType:  (string)(recordType),
-							RecordValue: txt,
-						}
-						dnsRecords = append(dnsRecords, dnsRecord)
-					}
-				}
-			case RecordTypeNS:
-				nss, err := net.LookupNS(targetValue)
-				if err == nil {
-					for _, ns := range nss {
-						nsHost := strings.TrimSuffix(ns.Host, ".")
-						records = append(records, nsHost)
-
-						// Create target for NS hostname
-						newTarget := models.Target{
-							ID:         uuid.New(),
-							ProjectID:  uuid.Nil, // Will be set by worker
-							TargetType: models.TargetTypeDomain,
-							Value:      nsHost,
-							Metadata: models.JSONB{
-								"discovered_from": targetValue,
-								"discovery_scan":  "dns_ns",
-								"discovered_at":   time.Now().Format(time.RFC3339),
-							},
-						}
-						scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
-
-						// Create relation between domain and NS host
-						relation := models.TargetRelation{
-							ID:            uuid.New(),
-							SourceID:      uuid.Nil, // Will be set by worker
-							DestinationID: newTarget.ID,
-							RelationType:  models.RelationResolvesTo,
-							Metadata: models.JSONB{
-								"discovered_at": time.Now().Format(time.RFC3339),
-								"record_type":   "NS",
-							},
-						}
-						scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
-
-						dnsRecord := models.DNSRecord{
-							RecordType:  (string)(recordType),
-							RecordValue: nsHost,
-						}
-						dnsRecords = append(dnsRecords, dnsRecord)
-					}
-				}
-			case RecordTypeSOA:
-				// SOA records need to be queried using a specific DNS tool as Go's net package doesn't support it directly
-				cmd := exec.CommandContext(ctx, "dig", "+short", "SOA", targetValue)
-				output, err := cmd.CombinedOutput()
-				if err == nil {
-					outputStr := strings.TrimSpace(string(output))
-					if outputStr != "" {
-						records = []string{outputStr}
-						dnsRecord := models.DNSRecord{
-							RecordType:  (string)(recordType),
-							RecordValue: outputStr,
-						}
-						dnsRecords = append(dnsRecords, dnsRecord)
-					}
-				}
+			if recordType == RecordTypeNS {
+				nsQueried = true
+				nameserverHosts = records
 			}
 
 			if err != nil || len(records) == 0 {
+				if recordType == RecordTypeDNSKEY && err == nil {
+					dnskeyMissing = true
+				}
+
 				// No records found
 				finding := models.Finding{
 					Title:       fmt.Sprintf("No %s records for %s", recordType, targetValue),
@@ -423,12 +284,366 @@ func (s *DNSScanner) Scan(ctx context.Context, target interface{}, params models
 				findings = append(findings, finding)
 			}
 		}
+
+		// A zone without DNSKEY records at its apex is not signed
+		if dnskeyMissing {
+			if finding := s.dnssecFinding(ctx, client, targetValue); finding != nil {
+				findings = append(findings, *finding)
+			}
+		}
+
+		if zoneTransfer {
+			// Look up the nameservers if they were not part of the requested record types
+			if !nsQueried {
+				response, err := client.Query(ctx, targetValue, dnsmessage.TypeNS)
+				if err == nil {
+					for _, answer := range response.Answers {
+						if answer.Header.Type == dnsmessage.TypeNS {
+							nameserverHosts = append(nameserverHosts, formatDNSResource(answer))
+						}
+					}
+				}
+			}
+
+			if len(nameserverHosts) > 0 {
+				transferFindings, transferRecords := s.attemptZoneTransfers(ctx, client, targetValue, nameserverHosts, scanResults)
+				findings = append(findings, transferFindings...)
+				dnsRecords = append(dnsRecords, transferRecords...)
+			}
+		}
+
+		if emailSecurity {
+			findings = append(findings, s.analyzeEmailSecurity(ctx, client, targetValue, dkimSelectors)...)
+		}
 	}
 	scanResults.Findings = findings
 	scanResults.DNSRecords = dnsRecords
 	return scanResults, nil
 }
 
+// dnssecFinding reports a zone that is not signed with DNSSEC. DNSKEY records only exist at the zone apex,
+// so names inside a zone are left to the finding of their apex. Returns nil when the domain is not a zone
+// apex or its records could not be queried.
+func (s *DNSScanner) dnssecFinding(ctx context.Context, client *dnsClient, domain string) *models.Finding {
+	apex, err := zoneApex(ctx, client, domain)
+	if err != nil || !strings.EqualFold(apex, strings.TrimSuffix(domain, ".")) {
+		return nil
+	}
+
+	// A DS record at the parent means the zone is meant to be signed, even if its DNSKEY query came back empty
+	response, err := client.Query(ctx, domain, dnsTypeDS)
+	if err != nil {
+		return nil
+	}
+	for _, answer := range response.Answers {
+		if answer.Header.Type == dnsTypeDS {
+			return nil
+		}
+	}
+
+	return &models.Finding{
+		Title:       fmt.Sprintf("DNSSEC not enabled for %s", domain),
+		Description: fmt.Sprintf("No DNSKEY or DS records were found for the zone %s. The zone is not signed with DNSSEC, so resolvers cannot verify the authenticity of its DNS responses.", domain),
+		Severity:    models.SeverityLow,
+		FindingType: "dns_dnssec_disabled",
+		Details: models.JSONB{
+			"domain": domain,
+			"zone":   apex,
+		},
+	}
+}
+
+// zoneApex returns the apex of the zone a name belongs to, taken from the SOA record in the answer
+// for the apex itself or in the authority section for names inside the zone
+func zoneApex(ctx context.Context, client *dnsClient, name string) (string, error) {
+	response, err := client.Query(ctx, name, dnsmessage.TypeSOA)
+	if err != nil {
+		return "", err
+	}
+
+	for _, section := range [][]dnsmessage.Resource{response.Answers, response.Authorities} {
+		for _, resource := range section {
+			if resource.Header.Type == dnsmessage.TypeSOA {
+				return trimDNSName(resource.Header.Name), nil
+			}
+		}
+	}
+	return "", fmt.Errorf("no SOA record found for %s", name)
+}
+
+// lookupRecords queries a single record type and creates targets for the hosts it references
+func (s *DNSScanner) lookupRecords(ctx context.Context, client *dnsClient, domain string, recordType DNSRecordType, srvNames []string, scanResults *models.ScanResults) ([]string, []models.DNSRecord, error) {
+	// SRV records live under service specific names, so query the common ones
+	if recordType == RecordTypeSRV {
+		return s.lookupSRVRecords(ctx, client, domain, srvNames, scanResults)
+	}
+
+	qtype, ok := dnsTypeFromString(string(recordType))
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported record type %s", recordType)
+	}
+
+	response, err := client.Query(ctx, domain, qtype)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var records []string
+	var dnsRecords []models.DNSRecord
+
+	for _, answer := range response.Answers {
+		// Skip records from CNAME chains that don't match the requested type
+		if answer.Header.Type != qtype {
+			continue
+		}
+
+		value := formatDNSResource(answer)
+		records = append(records, value)
+		dnsRecords = append(dnsRecords, s.createDNSRecord(string(recordType), value, answer, response.Server, "query"))
+
+		switch body := answer.Body.(type) {
+		case *dnsmessage.AResource:
+			// Create targets for A records (IPs)
+			s.addDiscoveredTarget(scanResults, models.TargetTypeIP, value, domain, "dns_a",
+				models.RelationResolvesTo, models.JSONB{"record_type": "A"}, nil)
+		case *dnsmessage.CNAMEResource:
+			// Create target for CNAME
+			s.addDiscoveredTarget(scanResults, models.TargetTypeDomain, value, domain, "dns_cname",
+				models.RelationResolvesTo, models.JSONB{"record_type": "CNAME"}, nil)
+		case *dnsmessage.MXResource:
+			// Create target for MX hostname
+			s.addDiscoveredTarget(scanResults, models.TargetTypeDomain, trimDNSName(body.MX), domain, "dns_mx",
+				models.RelationResolvesTo, models.JSONB{"record_type": "MX", "priority": body.Pref},
+				models.JSONB{"mx_priority": body.Pref})
+		case *dnsmessage.NSResource:
+			// Create target for NS hostname
+			s.addDiscoveredTarget(scanResults, models.TargetTypeDomain, value, domain, "dns_ns",
+				models.RelationResolvesTo, models.JSONB{"record_type": "NS"}, nil)
+		}
+	}
+
+	return records, dnsRecords, nil
+}
+
+// lookupSRVRecords queries SRV records for a list of common service names under the domain
+func (s *DNSScanner) lookupSRVRecords(ctx context.Context, client *dnsClient, domain string, srvNames []string, scanResults *models.ScanResults) ([]string, []models.DNSRecord, error) {
+	var records []string
+	var dnsRecords []models.DNSRecord
+	var lastErr error
+
+	for _, srvName := range srvNames {
+		if ctx.Err() != nil {
+			return records, dnsRecords, ctx.Err()
+		}
+
+		name := fmt.Sprintf("%s.%s", strings.Trim(srvName, "."), domain)
+		response, err := client.Query(ctx, name, dnsmessage.TypeSRV)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, answer := range response.Answers {
+			srv, ok := answer.Body.(*dnsmessage.SRVResource)
+			if !ok {
+				continue
+			}
+
+			value := fmt.Sprintf("%s %s", name, formatDNSResource(answer))
+			records = append(records, value)
+			dnsRecords = append(dnsRecords, s.createDNSRecord(string(RecordTypeSRV), formatDNSResource(answer), answer, response.Server, "query"))
+
+			// A target of "." means the service is explicitly not available
+			srvTarget := trimDNSName(srv.Target)
+			if srvTarget == "" {
+				continue
+			}
+
+			s.addDiscoveredTarget(scanResults, models.TargetTypeDomain, srvTarget, domain, "dns_srv",
+				models.RelationResolvesTo, models.JSONB{"record_type": "SRV", "service": srvName, "port": srv.Port},
+				models.JSONB{"srv_service": srvName, "srv_port": srv.Port})
+		}
+	}
+
+	if len(records) == 0 && lastErr != nil {
+		return nil, nil, lastErr
+	}
+
+	return records, dnsRecords, nil
+}
+
+// attemptZoneTransfers tries an AXFR of the domain against each of its nameservers
+func (s *DNSScanner) attemptZoneTransfers(ctx context.Context, client *dnsClient, domain string, nameserverHosts []string, scanResults *models.ScanResults) ([]models.Finding, []models.DNSRecord) {
+	var findings []models.Finding
+	var dnsRecords []models.DNSRecord
+
+	var vulnerable []string
+	var refused []string
+	var transferred []dnsmessage.Resource
+	transferServer := ""
+
+	for _, nsHost := range nameserverHosts {
+		if ctx.Err() != nil {
+			break
+		}
+
+		// Resolve the nameserver addresses so each one can be tried directly
+		var addresses []string
+		for _, qtype := range []dnsmessage.Type{dnsmessage.TypeA, dnsmessage.TypeAAAA} {
+			response, err := client.Query(ctx, nsHost, qtype)
+			if err != nil {
+				continue
+			}
+			for _, answer := range response.Answers {
+				if answer.Header.Type == qtype {
+					addresses = append(addresses, formatDNSResource(answer))
+				}
+			}
+		}
+
+		allowed := false
+		for _, address := range addresses {
+			records, err := client.Transfer(ctx, address, domain)
+			if err != nil {
+				continue
+			}
+
+			allowed = true
+			// Keep the records from the first successful transfer
+			if transferred == nil {
+				transferred = records
+				transferServer = fmt.Sprintf("%s (%s)", nsHost, address)
+			}
+			break
+		}
+
+		if allowed {
+			vulnerable = append(vulnerable, nsHost)
+		} else {
+			refused = append(refused, nsHost)
+		}
+	}
+
+	if len(vulnerable) == 0 {
+		if len(refused) > 0 {
+			finding := models.Finding{
+				Title:       fmt.Sprintf("Zone transfer refused for %s", domain),
+				Description: fmt.Sprintf("None of the nameservers for %s allowed a zone transfer (AXFR):\n• %s", domain, strings.Join(refused, "\n• ")),
+				Severity:    models.SeverityInfo,
+				FindingType: "dns_zone_transfer_refused",
+				Details: models.JSONB{
+					"domain":      domain,
+					"nameservers": refused,
+				},
+			}
+			findings = append(findings, finding)
+		}
+		return findings, dnsRecords
+	}
+
+	// Store every transferred record and collect the hostnames in the zone
+	zone := strings.ToLower(strings.TrimSuffix(domain, "."))
+	hostnames := map[string]bool{}
+	for _, record := range transferred {
+		recordType := dnsTypeToString(record.Header.Type)
+		owner := strings.ToLower(trimDNSName(record.Header.Name))
+
+		dnsRecord := s.createDNSRecord(recordType, formatDNSResource(record), record, transferServer, "axfr")
+		dnsRecords = append(dnsRecords, dnsRecord)
+
+		if owner != zone && strings.HasSuffix(owner, "."+zone) && !strings.HasPrefix(owner, "*.") {
+			hostnames[owner] = true
+		}
+	}
+
+	var discovered []string
+	for hostname := range hostnames {
+		discovered = append(discovered, hostname)
+	}
+	sort.Strings(discovered)
+
+	for _, hostname := range discovered {
+		s.addDiscoveredTarget(scanResults, models.TargetTypeDomain, hostname, domain, "dns_axfr",
+			models.RelationParentOf, models.JSONB{"record_type": "AXFR", "nameserver": transferServer}, nil)
+	}
+
+	finding := models.Finding{
+		Title:       fmt.Sprintf("DNS zone transfer allowed for %s", domain),
+		Description: s.generateZoneTransferDescription(domain, vulnerable, len(transferred), discovered),
+		Severity:    models.SeverityHigh,
+		FindingType: "dns_zone_transfer",
+		Details: models.JSONB{
+			"domain":               domain,
+			"nameservers":          vulnerable,
+			"transfer_source":      transferServer,
+			"record_count":         len(transferred),
+			"discovered_hostnames": discovered,
+		},
+	}
+	findings = append(findings, finding)
+
+	return findings, dnsRecords
+}
+
+// createDNSRecord builds a DNSRecord for a resource returned by a nameserver
+func (s *DNSScanner) createDNSRecord(recordType string, value string, resource dnsmessage.Resource, nameserver string, source string) models.DNSRecord {
+	return models.DNSRecord{
+		RecordType:  recordType,
+		RecordValue: value,
+		Details: models.JSONB{
+			"name":       trimDNSName(resource.Header.Name),
+			"ttl":        resource.Header.TTL,
+			"nameserver": nameserver,
+			"source":     source,
+		},
+	}
+}
+
+// addDiscoveredTarget adds a new target found through DNS together with its relation to the scanned target
+func (s *DNSScanner) addDiscoveredTarget(scanResults *models.ScanResults, targetType string, value string, discoveredFrom string, discoveryScan string, relationType string, relationMetadata models.JSONB, extraMetadata models.JSONB) {
+	metadata := models.JSONB{
+		"discovered_from": discoveredFrom,
+		"discovery_scan":  discoveryScan,
+		"discovered_at":   time.Now().Format(time.RFC3339),
+	}
+	for key, value := range extraMetadata {
+		metadata[key] = value
+	}
+
+	newTarget := models.Target{
+		ID:         uuid.New(),
+		ProjectID:  uuid.Nil, // Will be set by worker
+		TargetType: targetType,
+		Value:      value,
+		Metadata:   metadata,
+	}
+	scanResults.NewTargets = append(scanResults.NewTargets, newTarget)
+
+	relationMetadata["discovered_at"] = time.Now().Format(time.RFC3339)
+	relation := models.TargetRelation{
+		ID:            uuid.New(),
+		SourceID:      uuid.Nil, // Will be set by worker
+		DestinationID: newTarget.ID,
+		RelationType:  relationType,
+		Metadata:      relationMetadata,
+	}
+	scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+}
+
+// generateZoneTransferDescription creates a human-readable description of a successful zone transfer
+func (s *DNSScanner) generateZoneTransferDescription(domain string, nameservers []string, recordCount int, hostnames []string) string {
+	desc := fmt.Sprintf("The following nameservers allowed a full zone transfer (AXFR) of %s:", domain)
+	for _, ns := range nameservers {
+		desc += fmt.Sprintf("\n• %s", ns)
+	}
+
+	desc += fmt.Sprintf("\n\nThe transfer returned %d records and %d hostnames. ", recordCount, len(hostnames))
+	desc += "Anyone can retrieve the complete contents of the zone, exposing internal hostnames and infrastructure. "
+	desc += "Restrict zone transfers to trusted secondary nameservers."
+
+	return desc
+}
+
 // generateRecordDescription creates a human-readable description of DNS records
 func (s *DNSScanner) generateRecordDescription(domain string, recordType DNSRecordType, records []string) string {
 	desc := fmt.Sprintf("The following %s records were found for %s:", recordType, domain)
@@ -452,6 +667,16 @@ func (s *DNSScanner) generateRecordDescription(domain string, recordType DNSReco
 		desc += "\n\nThese nameservers are authoritative for this domain."
 	case RecordTypePTR:
 		desc += "\n\nThis IP address has reverse DNS pointing to these hostnames."
+	case RecordTypeSOA:
+		desc += "\n\nThe start of authority record lists the primary nameserver, responsible mailbox, serial and zone timers."
+	case RecordTypeSRV:
+		desc += "\n\nThese service records advertise hosts and ports for specific services (priority weight port target)."
+	case RecordTypeCAA:
+		desc += "\n\nThese certificate authorities are allowed to issue certificates for this domain."
+	case RecordTypeDS:
+		desc += "\n\nThese delegation signer records link the zone to its parent in the DNSSEC chain of trust."
+	case RecordTypeDNSKEY:
+		desc += "\n\nThese public keys are used to sign the zone with DNSSEC."
 	}
 
 	return desc
@@ -530,3 +755,16 @@ func (s *DNSScanner) SupportsTargetType(targetType string) bool {
 func (s *DNSScanner) SupportsServices() bool {
 	return false
 }
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *DNSScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"record_types":   enumListParam("Record types to query", "A", "AAAA", "CNAME", "MX", "TXT", "NS", "SOA", "PTR", "SRV", "CAA", "DS", "DNSKEY"),
+		"nameservers":    stringListParam("Nameservers to query instead of the system resolvers"),
+		"timeout":        integerParam("Query timeout in seconds", 1, 0),
+		"srv_names":      stringListParam("SRV service names to query"),
+		"zone_transfer":  booleanParam("Attempt AXFR against the authoritative nameservers, defaults to true"),
+		"email_security": booleanParam("Analyze SPF, DMARC, DKIM, MTA-STS and TLS-RPT"),
+		"dkim_selectors": stringListParam("DKIM selectors to look up"),
+	})
+}
@@ -0,0 +1,496 @@
+// internal/scanner/dnsclient.go
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// Record types not covered by the dnsmessage package
+const (
+	dnsTypeDS     dnsmessage.Type = 43
+	dnsTypeDNSKEY dnsmessage.Type = 48
+	dnsTypeCAA    dnsmessage.Type = 257
+)
+
+// maxTransferRecords caps the number of records accepted from a single zone transfer
+const maxTransferRecords = 50000
+
+// dnsClient is a minimal wire-level DNS client that sends queries directly to a set of nameservers
+type dnsClient struct {
+	nameservers []string
+	timeout     time.Duration
+}
+
+// dnsResponse contains the parsed answer to a DNS query
+type dnsResponse struct {
+	Server        string
+	RCode         dnsmessage.RCode
+	Authoritative bool
+	Answers       []dnsmessage.Resource
+	Authorities   []dnsmessage.Resource
+}
+
+// newDNSClient creates a DNS client for the given nameservers, adding the default port where missing
+func newDNSClient(nameservers []string, timeout time.Duration) *dnsClient {
+	var servers []string
+	for _, ns := range nameservers {
+		ns = strings.TrimSpace(ns)
+		if ns == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(ns); err != nil {
+			ns = net.JoinHostPort(ns, "53")
+		}
+		servers = append(servers, ns)
+	}
+
+	return &dnsClient{
+		nameservers: servers,
+		timeout:     timeout,
+	}
+}
+
+// systemNameservers returns the nameservers configured in /etc/resolv.conf, falling back to public resolvers
+func systemNameservers() []string {
+	var servers []string
+
+	file, err := os.Open("/etc/resolv.conf")
+	if err == nil {
+		defer file.Close()
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) >= 2 && fields[0] == "nameserver" && net.ParseIP(fields[1]) != nil {
+				servers = append(servers, fields[1])
+			}
+		}
+	}
+
+	if len(servers) == 0 {
+		servers = []string{"1.1.1.1", "8.8.8.8"}
+	}
+
+	return servers
+}
+
+// Query sends a recursive query for name and type to the configured nameservers, returning the first usable answer
+func (c *dnsClient) Query(ctx context.Context, name string, qtype dnsmessage.Type) (*dnsResponse, error) {
+	if len(c.nameservers) == 0 {
+		return nil, errors.New("no nameservers configured")
+	}
+
+	var lastErr error
+	for _, server := range c.nameservers {
+		response, err := c.QueryServer(ctx, server, name, qtype)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		// SERVFAIL and REFUSED are server specific, so try the next nameserver
+		if response.RCode == dnsmessage.RCodeServerFailure || response.RCode == dnsmessage.RCodeRefused {
+			lastErr = fmt.Errorf("nameserver %s returned %s", server, response.RCode)
+			continue
+		}
+
+		return response, nil
+	}
+
+	return nil, lastErr
+}
+
+// QueryServer sends a single query to a specific nameserver, retrying over TCP if the UDP answer is truncated
+func (c *dnsClient) QueryServer(ctx context.Context, server string, name string, qtype dnsmessage.Type) (*dnsResponse, error) {
+	query, id, err := buildDNSQuery(name, qtype)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := c.exchange(ctx, server, query, false)
+	if err != nil {
+		return nil, err
+	}
+
+	var parser dnsmessage.Parser
+	header, err := parser.Start(raw)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNS response: %w", err)
+	}
+
+	if header.Truncated {
+		raw, err = c.exchange(ctx, server, query, true)
+		if err != nil {
+			return nil, err
+		}
+		header, err = parser.Start(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse DNS response: %w", err)
+		}
+	}
+
+	if header.ID != id {
+		return nil, errors.New("DNS response ID does not match query")
+	}
+
+	if err := parser.SkipAllQuestions(); err != nil {
+		return nil, fmt.Errorf("failed to parse DNS questions: %w", err)
+	}
+
+	answers, err := parser.AllAnswers()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNS answers: %w", err)
+	}
+
+	authorities, err := parser.AllAuthorities()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse DNS authorities: %w", err)
+	}
+
+	return &dnsResponse{
+		Server:        server,
+		RCode:         header.RCode,
+		Authoritative: header.Authoritative,
+		Answers:       answers,
+		Authorities:   authorities,
+	}, nil
+}
+
+// Transfer attempts a full zone transfer (AXFR) of zone from the given nameserver
+func (c *dnsClient) Transfer(ctx context.Context, server string, zone string) ([]dnsmessage.Resource, error) {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+
+	query, id, err := buildDNSQuery(zone, dnsmessage.TypeAXFR)
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	// Zone transfers can be large, allow more time than a regular query
+	conn.SetDeadline(time.Now().Add(c.timeout * 10))
+
+	if err := writeTCPMessage(conn, query); err != nil {
+		return nil, err
+	}
+
+	var records []dnsmessage.Resource
+	soaCount := 0
+	for soaCount < 2 {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		raw, err := readTCPMessage(conn)
+		if err != nil {
+			return nil, fmt.Errorf("zone transfer interrupted: %w", err)
+		}
+
+		var parser dnsmessage.Parser
+		header, err := parser.Start(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transfer response: %w", err)
+		}
+		if header.ID != id {
+			return nil, errors.New("transfer response ID does not match query")
+		}
+		if header.RCode != dnsmessage.RCodeSuccess {
+			return nil, fmt.Errorf("zone transfer refused: %s", header.RCode)
+		}
+		if err := parser.SkipAllQuestions(); err != nil {
+			return nil, fmt.Errorf("failed to parse transfer questions: %w", err)
+		}
+
+		answers, err := parser.AllAnswers()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse transfer answers: %w", err)
+		}
+		if len(answers) == 0 {
+			return nil, errors.New("zone transfer returned no records")
+		}
+
+		// A transfer starts and ends with the zone's SOA record
+		for _, answer := range answers {
+			if answer.Header.Type == dnsmessage.TypeSOA {
+				soaCount++
+				if soaCount == 2 {
+					break
+				}
+			}
+			records = append(records, answer)
+		}
+
+		if len(records) == 0 || records[0].Header.Type != dnsmessage.TypeSOA {
+			return nil, errors.New("zone transfer did not start with an SOA record")
+		}
+		if len(records) > maxTransferRecords {
+			return nil, fmt.Errorf("zone transfer exceeded %d records", maxTransferRecords)
+		}
+	}
+
+	return records, nil
+}
+
+// exchange sends a raw DNS message to a server and returns the raw response
+func (c *dnsClient) exchange(ctx context.Context, server string, query []byte, useTCP bool) ([]byte, error) {
+	network := "udp"
+	if useTCP {
+		network = "tcp"
+	}
+
+	dialer := net.Dialer{Timeout: c.timeout}
+	conn, err := dialer.DialContext(ctx, network, server)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", server, err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(c.timeout)
+	if ctxDeadline, ok := ctx.Deadline(); ok && ctxDeadline.Before(deadline) {
+		deadline = ctxDeadline
+	}
+	conn.SetDeadline(deadline)
+
+	if useTCP {
+		if err := writeTCPMessage(conn, query); err != nil {
+			return nil, err
+		}
+		return readTCPMessage(conn)
+	}
+
+	if _, err := conn.Write(query); err != nil {
+		return nil, fmt.Errorf("failed to send query to %s: %w", server, err)
+	}
+
+	buf := make([]byte, 65535)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, fmt.Errorf("no response from %s: %w", server, err)
+	}
+
+	return buf[:n], nil
+}
+
+// writeTCPMessage writes a length prefixed DNS message
+func writeTCPMessage(conn net.Conn, msg []byte) error {
+	framed := make([]byte, 2+len(msg))
+	binary.BigEndian.PutUint16(framed, uint16(len(msg)))
+	copy(framed[2:], msg)
+	if _, err := conn.Write(framed); err != nil {
+		return fmt.Errorf("failed to send query: %w", err)
+	}
+	return nil
+}
+
+// readTCPMessage reads a length prefixed DNS message
+func readTCPMessage(conn net.Conn) ([]byte, error) {
+	var length [2]byte
+	if _, err := io.ReadFull(conn, length[:]); err != nil {
+		return nil, err
+	}
+
+	msg := make([]byte, binary.BigEndian.Uint16(length[:]))
+	if _, err := io.ReadFull(conn, msg); err != nil {
+		return nil, err
+	}
+
+	return msg, nil
+}
+
+// buildDNSQuery creates a DNS query message with a random ID and an EDNS0 record
+func buildDNSQuery(name string, qtype dnsmessage.Type) ([]byte, uint16, error) {
+	var idBytes [2]byte
+	if _, err := rand.Read(idBytes[:]); err != nil {
+		return nil, 0, fmt.Errorf("failed to generate query ID: %w", err)
+	}
+	id := binary.BigEndian.Uint16(idBytes[:])
+
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid DNS name %q: %w", name, err)
+	}
+
+	builder := dnsmessage.NewBuilder(nil, dnsmessage.Header{
+		ID:               id,
+		RecursionDesired: qtype != dnsmessage.TypeAXFR,
+	})
+	builder.EnableCompression()
+
+	if err := builder.StartQuestions(); err != nil {
+		return nil, 0, err
+	}
+	if err := builder.Question(dnsmessage.Question{Name: qname, Type: qtype, Class: dnsmessage.ClassINET}); err != nil {
+		return nil, 0, err
+	}
+
+	if qtype != dnsmessage.TypeAXFR {
+		// Advertise a larger UDP payload size and request DNSSEC records
+		if err := builder.StartAdditionals(); err != nil {
+			return nil, 0, err
+		}
+		var opt dnsmessage.ResourceHeader
+		if err := opt.SetEDNS0(1232, dnsmessage.RCodeSuccess, qtype == dnsTypeDNSKEY || qtype == dnsTypeDS); err != nil {
+			return nil, 0, err
+		}
+		if err := builder.OPTResource(opt, dnsmessage.OPTResource{}); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	msg, err := builder.Finish()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return msg, id, nil
+}
+
+// reverseDNSName returns the in-addr.arpa or ip6.arpa name for an IP address
+func reverseDNSName(ip net.IP) string {
+	if v4 := ip.To4(); v4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d.in-addr.arpa.", v4[3], v4[2], v4[1], v4[0])
+	}
+
+	const hexDigits = "0123456789abcdef"
+	var b strings.Builder
+	for i := len(ip) - 1; i >= 0; i-- {
+		b.WriteByte(hexDigits[ip[i]&0x0f])
+		b.WriteByte('.')
+		b.WriteByte(hexDigits[ip[i]>>4])
+		b.WriteByte('.')
+	}
+	b.WriteString("ip6.arpa.")
+	return b.String()
+}
+
+// dnsTypeFromString maps a record type name to its wire type
+func dnsTypeFromString(recordType string) (dnsmessage.Type, bool) {
+	switch strings.ToUpper(recordType) {
+	case "A":
+		return dnsmessage.TypeA, true
+	case "AAAA":
+		return dnsmessage.TypeAAAA, true
+	case "CNAME":
+		return dnsmessage.TypeCNAME, true
+	case "MX":
+		return dnsmessage.TypeMX, true
+	case "TXT":
+		return dnsmessage.TypeTXT, true
+	case "NS":
+		return dnsmessage.TypeNS, true
+	case "SOA":
+		return dnsmessage.TypeSOA, true
+	case "PTR":
+		return dnsmessage.TypePTR, true
+	case "SRV":
+		return dnsmessage.TypeSRV, true
+	case "CAA":
+		return dnsTypeCAA, true
+	case "DS":
+		return dnsTypeDS, true
+	case "DNSKEY":
+		return dnsTypeDNSKEY, true
+	default:
+		return 0, false
+	}
+}
+
+// dnsTypeToString maps a wire type to its record type name
+func dnsTypeToString(qtype dnsmessage.Type) string {
+	switch qtype {
+	case dnsTypeCAA:
+		return "CAA"
+	case dnsTypeDS:
+		return "DS"
+	case dnsTypeDNSKEY:
+		return "DNSKEY"
+	default:
+		return strings.TrimPrefix(qtype.String(), "Type")
+	}
+}
+
+// formatDNSResource returns the presentation format of a resource record's data
+func formatDNSResource(resource dnsmessage.Resource) string {
+	switch body := resource.Body.(type) {
+	case *dnsmessage.AResource:
+		return net.IP(body.A[:]).String()
+	case *dnsmessage.AAAAResource:
+		return net.IP(body.AAAA[:]).String()
+	case *dnsmessage.CNAMEResource:
+		return trimDNSName(body.CNAME)
+	case *dnsmessage.NSResource:
+		return trimDNSName(body.NS)
+	case *dnsmessage.PTRResource:
+		return trimDNSName(body.PTR)
+	case *dnsmessage.MXResource:
+		return fmt.Sprintf("%s (priority: %d)", trimDNSName(body.MX), body.Pref)
+	case *dnsmessage.TXTResource:
+		return strings.Join(body.TXT, "")
+	case *dnsmessage.SOAResource:
+		return fmt.Sprintf("%s %s %d %d %d %d %d",
+			trimDNSName(body.NS), trimDNSName(body.MBox), body.Serial, body.Refresh, body.Retry, body.Expire, body.MinTTL)
+	case *dnsmessage.SRVResource:
+		return fmt.Sprintf("%d %d %d %s", body.Priority, body.Weight, body.Port, trimDNSName(body.Target))
+	case *dnsmessage.UnknownResource:
+		return formatUnknownDNSResource(body)
+	default:
+		return resource.Body.GoString()
+	}
+}
+
+// formatUnknownDNSResource formats the record types dnsmessage does not parse itself
+func formatUnknownDNSResource(body *dnsmessage.UnknownResource) string {
+	data := body.Data
+	switch body.Type {
+	case dnsTypeCAA:
+		// flags(1) tag-length(1) tag value
+		if len(data) < 2 || len(data) < 2+int(data[1]) {
+			break
+		}
+		tagEnd := 2 + int(data[1])
+		return fmt.Sprintf("%d %s %q", data[0], string(data[2:tagEnd]), string(data[tagEnd:]))
+	case dnsTypeDS:
+		// key-tag(2) algorithm(1) digest-type(1) digest
+		if len(data) < 4 {
+			break
+		}
+		return fmt.Sprintf("%d %d %d %s", binary.BigEndian.Uint16(data[0:2]), data[2], data[3], strings.ToUpper(hex.EncodeToString(data[4:])))
+	case dnsTypeDNSKEY:
+		// flags(2) protocol(1) algorithm(1) public-key
+		if len(data) < 4 {
+			break
+		}
+		return fmt.Sprintf("%d %d %d %s", binary.BigEndian.Uint16(data[0:2]), data[2], data[3], base64.StdEncoding.EncodeToString(data[4:]))
+	}
+
+	return "\\# " + strconv.Itoa(len(data)) + " " + hex.EncodeToString(data)
+}
+
+// trimDNSName converts a DNS name to its string form without the trailing dot
+func trimDNSName(name dnsmessage.Name) string {
+	return strings.TrimSuffix(name.String(), ".")
+}
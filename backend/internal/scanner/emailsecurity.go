@@ -0,0 +1,781 @@
+// internal/scanner/emailsecurity.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+	"golang.org/x/net/publicsuffix"
+)
+
+// spfLookupLimit is the maximum number of DNS querying terms allowed by RFC 7208
+const spfLookupLimit = 10
+
+// defaultDKIMSelectors contains selectors commonly used by mail providers
+var defaultDKIMSelectors = []string{
+	"default",
+	"dkim",
+	"mail",
+	"selector1",
+	"selector2",
+	"google",
+	"k1",
+	"k2",
+	"s1",
+	"s2",
+	"smtp",
+	"mandrill",
+	"mxvault",
+	"zoho",
+	"amazonses",
+	"everlytickey1",
+	"everlytickey2",
+}
+
+// spfResult holds the outcome of evaluating an SPF record and its includes
+type spfResult struct {
+	Record       string
+	AllQualifier string
+	Lookups      int
+	Includes     []string
+	Failed       []string
+	UsesPTR      bool
+	Redirect     string
+}
+
+// analyzeEmailSecurity checks the SPF, DMARC, DKIM, MTA-STS and TLS-RPT configuration of a domain
+func (s *DNSScanner) analyzeEmailSecurity(ctx context.Context, client *dnsClient, domain string, dkimSelectors []string) []models.Finding {
+	var findings []models.Finding
+
+	findings = append(findings, s.analyzeSPF(ctx, client, domain)...)
+	findings = append(findings, s.analyzeDMARC(ctx, client, domain)...)
+	findings = append(findings, s.analyzeDKIM(ctx, client, domain, dkimSelectors)...)
+	findings = append(findings, s.analyzeMTASTS(ctx, client, domain)...)
+	findings = append(findings, s.analyzeTLSRPT(ctx, client, domain)...)
+
+	return findings
+}
+
+// analyzeSPF fetches and evaluates the SPF record of a domain
+func (s *DNSScanner) analyzeSPF(ctx context.Context, client *dnsClient, domain string) []models.Finding {
+	var findings []models.Finding
+
+	records, err := s.lookupTXTWithPrefix(ctx, client, domain, "v=spf1")
+	if err != nil || len(records) == 0 {
+		finding := models.Finding{
+			Title:       fmt.Sprintf("No SPF record for %s", domain),
+			Description: fmt.Sprintf("%s does not publish an SPF record. Anyone can send email claiming to be from this domain without it failing SPF checks.", domain),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_spf_missing",
+			Details: models.JSONB{
+				"domain": domain,
+			},
+		}
+
+		// Names without mail exchangers rarely send mail, so a missing record matters less there
+		if hasMX, err := s.hasMailExchanger(ctx, client, domain); err == nil && !hasMX {
+			finding.Severity = models.SeverityInfo
+			finding.Description += " The name has no MX records, so it likely does not handle mail; publishing \"v=spf1 -all\" still stops it from being spoofed."
+			finding.Details["has_mx"] = false
+		}
+		return append(findings, finding)
+	}
+
+	if len(records) > 1 {
+		finding := models.Finding{
+			Title:       fmt.Sprintf("Multiple SPF records for %s", domain),
+			Description: fmt.Sprintf("%s publishes %d SPF records. Receivers treat this as a permanent error and SPF evaluation fails.", domain, len(records)),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_spf_multiple_records",
+			Details: models.JSONB{
+				"domain":  domain,
+				"records": records,
+			},
+		}
+		findings = append(findings, finding)
+	}
+
+	result := &spfResult{Record: records[0]}
+	s.evaluateSPF(ctx, client, domain, records[0], result, map[string]bool{domain: true}, 0)
+
+	details := models.JSONB{
+		"domain":        domain,
+		"record":        result.Record,
+		"all_qualifier": result.AllQualifier,
+		"lookup_count":  result.Lookups,
+		"includes":      result.Includes,
+	}
+	if result.Redirect != "" {
+		details["redirect"] = result.Redirect
+	}
+
+	finding := models.Finding{
+		Title:       fmt.Sprintf("SPF record for %s", domain),
+		Description: s.generateSPFDescription(domain, result),
+		Severity:    models.SeverityInfo,
+		FindingType: "email_spf_record",
+		Details:     details,
+	}
+	findings = append(findings, finding)
+
+	switch result.AllQualifier {
+	case "+":
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("SPF uses +all for %s", domain),
+			Description: fmt.Sprintf("The SPF record for %s ends with +all, which authorizes every host on the internet to send email for the domain.", domain),
+			Severity:    models.SeverityHigh,
+			FindingType: "email_spf_pass_all",
+			Details:     details,
+		})
+	case "?":
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("SPF uses ?all for %s", domain),
+			Description: fmt.Sprintf("The SPF record for %s ends with ?all (neutral), so mail from unauthorized hosts is not rejected or marked.", domain),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_spf_neutral_all",
+			Details:     details,
+		})
+	case "":
+		if result.Redirect == "" {
+			findings = append(findings, models.Finding{
+				Title:       fmt.Sprintf("SPF record without all mechanism for %s", domain),
+				Description: fmt.Sprintf("The SPF record for %s has no all mechanism or redirect, so mail from unlisted hosts results in a neutral SPF result.", domain),
+				Severity:    models.SeverityMedium,
+				FindingType: "email_spf_no_all",
+				Details:     details,
+			})
+		}
+	}
+
+	if result.Lookups > spfLookupLimit {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("SPF lookup limit exceeded for %s", domain),
+			Description: fmt.Sprintf("Evaluating the SPF record for %s requires %d DNS lookups. RFC 7208 limits this to %d, and receivers will return a permanent error.", domain, result.Lookups, spfLookupLimit),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_spf_too_many_lookups",
+			Details:     details,
+		})
+	}
+
+	if len(result.Failed) > 0 {
+		failedDetails := models.JSONB{
+			"domain":   domain,
+			"record":   result.Record,
+			"failures": result.Failed,
+		}
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Broken SPF include for %s", domain),
+			Description: fmt.Sprintf("The following SPF includes for %s could not be resolved:\n• %s", domain, strings.Join(result.Failed, "\n• ")),
+			Severity:    models.SeverityLow,
+			FindingType: "email_spf_broken_include",
+			Details:     failedDetails,
+		})
+	}
+
+	if result.UsesPTR {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("SPF uses deprecated ptr mechanism for %s", domain),
+			Description: fmt.Sprintf("The SPF record for %s uses the ptr mechanism, which is slow, unreliable and deprecated by RFC 7208.", domain),
+			Severity:    models.SeverityLow,
+			FindingType: "email_spf_ptr",
+			Details:     details,
+		})
+	}
+
+	return findings
+}
+
+// evaluateSPF walks the terms of an SPF record, following includes and redirects while counting DNS lookups
+func (s *DNSScanner) evaluateSPF(ctx context.Context, client *dnsClient, domain string, record string, result *spfResult, visited map[string]bool, depth int) {
+	// Includes are only expanded a few levels deep to avoid runaway chains
+	if depth > 10 || ctx.Err() != nil {
+		return
+	}
+
+	for _, term := range strings.Fields(record)[1:] {
+		term = strings.ToLower(term)
+
+		qualifier := "+"
+		if strings.ContainsAny(term[:1], "+-~?") {
+			qualifier = term[:1]
+			term = term[1:]
+		}
+
+		switch {
+		case term == "all":
+			// Only the all mechanism of the top-level record decides the policy
+			if depth == 0 {
+				result.AllQualifier = qualifier
+			}
+		case strings.HasPrefix(term, "include:"):
+			result.Lookups++
+			target := strings.TrimPrefix(term, "include:")
+			result.Includes = append(result.Includes, target)
+			s.followSPF(ctx, client, target, result, visited, depth)
+		case strings.HasPrefix(term, "redirect="):
+			result.Lookups++
+			target := strings.TrimPrefix(term, "redirect=")
+			if depth == 0 {
+				result.Redirect = target
+			}
+			s.followSPF(ctx, client, target, result, visited, depth)
+		case term == "a" || strings.HasPrefix(term, "a:") || strings.HasPrefix(term, "a/"),
+			term == "mx" || strings.HasPrefix(term, "mx:") || strings.HasPrefix(term, "mx/"),
+			strings.HasPrefix(term, "exists:"):
+			result.Lookups++
+		case term == "ptr" || strings.HasPrefix(term, "ptr:"):
+			result.Lookups++
+			result.UsesPTR = true
+		}
+	}
+}
+
+// followSPF fetches the SPF record of an included or redirected domain and evaluates it
+func (s *DNSScanner) followSPF(ctx context.Context, client *dnsClient, target string, result *spfResult, visited map[string]bool, depth int) {
+	// Macros cannot be expanded without a sender context
+	if strings.Contains(target, "%") {
+		return
+	}
+	if visited[target] {
+		result.Failed = append(result.Failed, fmt.Sprintf("%s (duplicate or looping include)", target))
+		return
+	}
+	visited[target] = true
+
+	records, err := s.lookupTXTWithPrefix(ctx, client, target, "v=spf1")
+	if err != nil || len(records) == 0 {
+		result.Failed = append(result.Failed, target)
+		return
+	}
+
+	s.evaluateSPF(ctx, client, target, records[0], result, visited, depth+1)
+}
+
+// analyzeDMARC fetches and evaluates the DMARC policy of a domain
+func (s *DNSScanner) analyzeDMARC(ctx context.Context, client *dnsClient, domain string) []models.Finding {
+	var findings []models.Finding
+
+	records, err := s.lookupTXTWithPrefix(ctx, client, "_dmarc."+domain, "v=DMARC1")
+	if err != nil || len(records) == 0 {
+		// Receivers fall back to the policy of the organizational domain (RFC 7489 section 6.6.3)
+		orgDomain, orgRecords := s.lookupOrganizationalDMARC(ctx, client, domain)
+		if len(orgRecords) > 0 {
+			return append(findings, s.inheritedDMARCFindings(domain, orgDomain, orgRecords[0])...)
+		}
+
+		details := models.JSONB{
+			"domain": domain,
+		}
+		if orgDomain != "" {
+			details["organizational_domain"] = orgDomain
+		}
+
+		finding := models.Finding{
+			Title:       fmt.Sprintf("No DMARC record for %s", domain),
+			Description: fmt.Sprintf("%s does not publish a DMARC record. Receivers have no policy for handling mail that fails SPF and DKIM, and the domain owner gets no reports about spoofing.", domain),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_dmarc_missing",
+			Details:     details,
+		}
+		return append(findings, finding)
+	}
+
+	tags := parseTagList(records[0])
+	policy := strings.ToLower(tags["p"])
+
+	var rua, ruf []string
+	if tags["rua"] != "" {
+		rua = strings.Split(tags["rua"], ",")
+	}
+	if tags["ruf"] != "" {
+		ruf = strings.Split(tags["ruf"], ",")
+	}
+
+	pct := 100
+	if value, err := strconv.Atoi(tags["pct"]); err == nil {
+		pct = value
+	}
+
+	details := models.JSONB{
+		"domain":           domain,
+		"record":           records[0],
+		"policy":           policy,
+		"subdomain_policy": tags["sp"],
+		"pct":              pct,
+		"rua":              rua,
+		"ruf":              ruf,
+		"adkim":            tags["adkim"],
+		"aspf":             tags["aspf"],
+	}
+
+	findings = append(findings, models.Finding{
+		Title:       fmt.Sprintf("DMARC record for %s", domain),
+		Description: fmt.Sprintf("%s publishes the following DMARC record:\n• %s", domain, records[0]),
+		Severity:    models.SeverityInfo,
+		FindingType: "email_dmarc_record",
+		Details:     details,
+	})
+
+	switch policy {
+	case "none":
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("DMARC p=none for %s", domain),
+			Description: fmt.Sprintf("The DMARC policy for %s is set to none, so receivers deliver mail that fails authentication. This only monitors spoofing without preventing it.", domain),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_dmarc_policy_none",
+			Details:     details,
+		})
+	case "quarantine", "reject":
+		if pct < 100 {
+			findings = append(findings, models.Finding{
+				Title:       fmt.Sprintf("DMARC policy only applies to %d%% of mail for %s", pct, domain),
+				Description: fmt.Sprintf("The DMARC policy for %s uses pct=%d, so the %s policy is only applied to part of the failing mail.", domain, pct, policy),
+				Severity:    models.SeverityLow,
+				FindingType: "email_dmarc_partial_pct",
+				Details:     details,
+			})
+		}
+	default:
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Invalid DMARC policy for %s", domain),
+			Description: fmt.Sprintf("The DMARC record for %s has a missing or invalid p tag (%q) and will be ignored by receivers.", domain, policy),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_dmarc_invalid",
+			Details:     details,
+		})
+	}
+
+	if strings.ToLower(tags["sp"]) == "none" && policy != "none" {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("DMARC sp=none for %s", domain),
+			Description: fmt.Sprintf("The DMARC subdomain policy for %s is set to none, so spoofed mail from subdomains is still delivered.", domain),
+			Severity:    models.SeverityLow,
+			FindingType: "email_dmarc_subdomain_none",
+			Details:     details,
+		})
+	}
+
+	if len(rua) == 0 {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("DMARC without aggregate reporting for %s", domain),
+			Description: fmt.Sprintf("The DMARC record for %s has no rua tag, so no aggregate reports about authentication failures are sent.", domain),
+			Severity:    models.SeverityLow,
+			FindingType: "email_dmarc_no_reporting",
+			Details:     details,
+		})
+	}
+
+	return findings
+}
+
+// lookupOrganizationalDMARC returns the organizational domain of a name and its DMARC records. Returns no
+// records when the name is an organizational domain itself.
+func (s *DNSScanner) lookupOrganizationalDMARC(ctx context.Context, client *dnsClient, domain string) (string, []string) {
+	orgDomain, err := publicsuffix.EffectiveTLDPlusOne(strings.TrimSuffix(domain, "."))
+	if err != nil || strings.EqualFold(orgDomain, strings.TrimSuffix(domain, ".")) {
+		return "", nil
+	}
+
+	records, err := s.lookupTXTWithPrefix(ctx, client, "_dmarc."+orgDomain, "v=DMARC1")
+	if err != nil {
+		return orgDomain, nil
+	}
+	return orgDomain, records
+}
+
+// inheritedDMARCFindings reports the DMARC policy a subdomain inherits from its organizational domain.
+// The record itself is analyzed when the organizational domain is scanned, so only the policy that
+// applies to the subdomain is reported here.
+func (s *DNSScanner) inheritedDMARCFindings(domain string, orgDomain string, record string) []models.Finding {
+	tags := parseTagList(record)
+
+	// The sp tag applies to subdomains, falling back to p
+	policy := strings.ToLower(tags["sp"])
+	if policy == "" {
+		policy = strings.ToLower(tags["p"])
+	}
+
+	details := models.JSONB{
+		"domain":                domain,
+		"organizational_domain": orgDomain,
+		"record":                record,
+		"policy":                policy,
+		"inherited":             true,
+	}
+
+	findings := []models.Finding{{
+		Title:       fmt.Sprintf("DMARC policy for %s inherited from %s", domain, orgDomain),
+		Description: fmt.Sprintf("%s does not publish its own DMARC record, so receivers apply the %s policy of its organizational domain %s:\n• %s", domain, policy, orgDomain, record),
+		Severity:    models.SeverityInfo,
+		FindingType: "email_dmarc_record",
+		Details:     details,
+	}}
+
+	if policy == "none" {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("DMARC p=none for %s", domain),
+			Description: fmt.Sprintf("%s inherits the DMARC policy none from %s, so receivers deliver mail that fails authentication. This only monitors spoofing without preventing it.", domain, orgDomain),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_dmarc_policy_none",
+			Details:     details,
+		})
+	}
+
+	return findings
+}
+
+// hasMailExchanger reports whether a name publishes MX records other than a null MX (RFC 7505)
+func (s *DNSScanner) hasMailExchanger(ctx context.Context, client *dnsClient, domain string) (bool, error) {
+	response, err := client.Query(ctx, domain, dnsmessage.TypeMX)
+	if err != nil {
+		return false, err
+	}
+
+	for _, answer := range response.Answers {
+		if mx, ok := answer.Body.(*dnsmessage.MXResource); ok && trimDNSName(mx.MX) != "" {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// analyzeDKIM looks for DKIM keys published under common selectors
+func (s *DNSScanner) analyzeDKIM(ctx context.Context, client *dnsClient, domain string, selectors []string) []models.Finding {
+	var findings []models.Finding
+	var found []models.JSONB
+
+	for _, selector := range selectors {
+		if ctx.Err() != nil {
+			break
+		}
+
+		name := fmt.Sprintf("%s._domainkey.%s", selector, domain)
+		records, err := s.lookupTXT(ctx, client, name)
+		if err != nil {
+			continue
+		}
+
+		for _, record := range records {
+			tags := parseTagList(record)
+			if _, ok := tags["p"]; !ok {
+				continue
+			}
+
+			keyType := tags["k"]
+			if keyType == "" {
+				keyType = "rsa"
+			}
+
+			key := models.JSONB{
+				"selector": selector,
+				"record":   record,
+				"key_type": keyType,
+			}
+
+			if tags["p"] == "" {
+				key["revoked"] = true
+				found = append(found, key)
+				continue
+			}
+
+			bits := dkimKeySize(tags["p"])
+			key["key_size"] = bits
+			found = append(found, key)
+
+			if keyType == "rsa" && bits > 0 && bits < 2048 {
+				severity := models.SeverityLow
+				if bits < 1024 {
+					severity = models.SeverityHigh
+				}
+				findings = append(findings, models.Finding{
+					Title:       fmt.Sprintf("Weak DKIM key for selector %s on %s", selector, domain),
+					Description: fmt.Sprintf("The DKIM key published under %s uses a %d-bit RSA key. Keys shorter than 2048 bits are considered weak and short keys can be factored to forge signatures.", name, bits),
+					Severity:    severity,
+					FindingType: "email_dkim_weak_key",
+					Details:     key,
+				})
+			}
+		}
+	}
+
+	if len(found) == 0 {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("No DKIM keys found for %s", domain),
+			Description: fmt.Sprintf("No DKIM keys were found for %s under the %d common selectors that were checked. The domain may use a custom selector.", domain, len(selectors)),
+			Severity:    models.SeverityInfo,
+			FindingType: "email_dkim_not_found",
+			Details: models.JSONB{
+				"domain":    domain,
+				"selectors": selectors,
+			},
+		})
+		return findings
+	}
+
+	var names []string
+	for _, key := range found {
+		names = append(names, key["selector"].(string))
+	}
+
+	findings = append(findings, models.Finding{
+		Title:       fmt.Sprintf("DKIM keys for %s", domain),
+		Description: fmt.Sprintf("DKIM keys were found for %s under the following selectors:\n• %s", domain, strings.Join(names, "\n• ")),
+		Severity:    models.SeverityInfo,
+		FindingType: "email_dkim_records",
+		Details: models.JSONB{
+			"domain": domain,
+			"keys":   found,
+		},
+	})
+
+	return findings
+}
+
+// analyzeMTASTS checks the MTA-STS record and fetches the published policy
+func (s *DNSScanner) analyzeMTASTS(ctx context.Context, client *dnsClient, domain string) []models.Finding {
+	var findings []models.Finding
+
+	records, err := s.lookupTXTWithPrefix(ctx, client, "_mta-sts."+domain, "v=STSv1")
+	if err != nil || len(records) == 0 {
+		finding := models.Finding{
+			Title:       fmt.Sprintf("No MTA-STS policy for %s", domain),
+			Description: fmt.Sprintf("%s does not publish an MTA-STS record. Sending servers cannot require TLS when delivering mail to this domain, leaving it open to downgrade attacks.", domain),
+			Severity:    models.SeverityLow,
+			FindingType: "email_mta_sts_missing",
+			Details: models.JSONB{
+				"domain": domain,
+			},
+		}
+		return append(findings, finding)
+	}
+
+	tags := parseTagList(records[0])
+	policyURL := fmt.Sprintf("https://mta-sts.%s/.well-known/mta-sts.txt", domain)
+	details := models.JSONB{
+		"domain":     domain,
+		"record":     records[0],
+		"id":         tags["id"],
+		"policy_url": policyURL,
+	}
+
+	policy, err := fetchMTASTSPolicy(ctx, policyURL, time.Duration(s.resolverTimeout)*time.Second)
+	if err != nil {
+		details["error"] = err.Error()
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("MTA-STS policy unavailable for %s", domain),
+			Description: fmt.Sprintf("%s publishes an MTA-STS record, but the policy could not be fetched from %s. Sending servers will not enforce TLS.", domain, policyURL),
+			Severity:    models.SeverityMedium,
+			FindingType: "email_mta_sts_policy_unavailable",
+			Details:     details,
+		})
+		return findings
+	}
+
+	mode, _ := policy["mode"].(string)
+	details["mode"] = mode
+	details["mx"] = policy["mx"]
+	details["max_age"] = policy["max_age"]
+
+	findings = append(findings, models.Finding{
+		Title:       fmt.Sprintf("MTA-STS policy for %s", domain),
+		Description: fmt.Sprintf("%s publishes an MTA-STS policy in %q mode.", domain, mode),
+		Severity:    models.SeverityInfo,
+		FindingType: "email_mta_sts_policy",
+		Details:     details,
+	})
+
+	if mode != "enforce" {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("MTA-STS not enforced for %s", domain),
+			Description: fmt.Sprintf("The MTA-STS policy for %s is in %q mode, so sending servers still deliver mail when TLS cannot be established.", domain, mode),
+			Severity:    models.SeverityLow,
+			FindingType: "email_mta_sts_not_enforced",
+			Details:     details,
+		})
+	}
+
+	return findings
+}
+
+// analyzeTLSRPT checks whether the domain publishes a TLS reporting address
+func (s *DNSScanner) analyzeTLSRPT(ctx context.Context, client *dnsClient, domain string) []models.Finding {
+	records, err := s.lookupTXTWithPrefix(ctx, client, "_smtp._tls."+domain, "v=TLSRPTv1")
+	if err != nil || len(records) == 0 {
+		return []models.Finding{{
+			Title:       fmt.Sprintf("No TLS-RPT record for %s", domain),
+			Description: fmt.Sprintf("%s does not publish a TLS-RPT record, so it receives no reports about failed TLS connections from sending servers.", domain),
+			Severity:    models.SeverityInfo,
+			FindingType: "email_tls_rpt_missing",
+			Details: models.JSONB{
+				"domain": domain,
+			},
+		}}
+	}
+
+	tags := parseTagList(records[0])
+	return []models.Finding{{
+		Title:       fmt.Sprintf("TLS-RPT record for %s", domain),
+		Description: fmt.Sprintf("%s sends TLS reports to %s", domain, tags["rua"]),
+		Severity:    models.SeverityInfo,
+		FindingType: "email_tls_rpt_record",
+		Details: models.JSONB{
+			"domain": domain,
+			"record": records[0],
+			"rua":    strings.Split(tags["rua"], ","),
+		},
+	}}
+}
+
+// lookupTXT returns all TXT records for a name
+func (s *DNSScanner) lookupTXT(ctx context.Context, client *dnsClient, name string) ([]string, error) {
+	response, err := client.Query(ctx, name, dnsmessage.TypeTXT)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []string
+	for _, answer := range response.Answers {
+		if answer.Header.Type == dnsmessage.TypeTXT {
+			records = append(records, formatDNSResource(answer))
+		}
+	}
+
+	return records, nil
+}
+
+// lookupTXTWithPrefix returns the TXT records for a name that start with the given version tag
+func (s *DNSScanner) lookupTXTWithPrefix(ctx context.Context, client *dnsClient, name string, prefix string) ([]string, error) {
+	records, err := s.lookupTXT(ctx, client, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var matching []string
+	for _, record := range records {
+		trimmed := strings.TrimSpace(record)
+		if strings.EqualFold(trimmed, prefix) || strings.HasPrefix(strings.ToLower(trimmed), strings.ToLower(prefix)+" ") ||
+			strings.HasPrefix(strings.ToLower(trimmed), strings.ToLower(prefix)+";") {
+			matching = append(matching, trimmed)
+		}
+	}
+
+	return matching, nil
+}
+
+// generateSPFDescription creates a human-readable description of an SPF evaluation
+func (s *DNSScanner) generateSPFDescription(domain string, result *spfResult) string {
+	desc := fmt.Sprintf("%s publishes the following SPF record:\n• %s", domain, result.Record)
+
+	if len(result.Includes) > 0 {
+		desc += "\n\nIncluded domains:"
+		for _, include := range result.Includes {
+			desc += fmt.Sprintf("\n• %s", include)
+		}
+	}
+
+	desc += fmt.Sprintf("\n\nEvaluating the record requires %d of the %d allowed DNS lookups.", result.Lookups, spfLookupLimit)
+
+	return desc
+}
+
+// parseTagList parses a semicolon separated tag=value list as used by DMARC, DKIM and MTA-STS
+func parseTagList(record string) map[string]string {
+	tags := map[string]string{}
+	for _, part := range strings.Split(record, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+		// DKIM keys may be split with whitespace across TXT strings
+		key = strings.ToLower(strings.TrimSpace(key))
+		if key == "p" {
+			value = strings.Join(strings.Fields(value), "")
+		}
+		tags[key] = strings.TrimSpace(value)
+	}
+	return tags
+}
+
+// dkimKeySize returns the RSA key size of a base64 encoded DKIM public key, or 0 if it cannot be determined
+func dkimKeySize(encoded string) int {
+	der, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return 0
+	}
+
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		// Some keys are published as a bare PKCS#1 RSA key
+		rsaKey, err := x509.ParsePKCS1PublicKey(der)
+		if err != nil {
+			return 0
+		}
+		return rsaKey.N.BitLen()
+	}
+
+	if rsaKey, ok := key.(*rsa.PublicKey); ok {
+		return rsaKey.N.BitLen()
+	}
+
+	return 0
+}
+
+// fetchMTASTSPolicy downloads and parses an MTA-STS policy file
+func fetchMTASTSPolicy(ctx context.Context, policyURL string, timeout time.Duration) (models.JSONB, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, policyURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	httpClient := &http.Client{
+		Timeout: timeout,
+		// MTA-STS policies must not be served through redirects
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("policy request returned status %d", resp.StatusCode)
+	}
+
+	policy := models.JSONB{}
+	var mx []string
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		key = strings.TrimSpace(key)
+		value = strings.TrimSpace(value)
+		if key == "mx" {
+			mx = append(mx, value)
+			continue
+		}
+		policy[key] = value
+	}
+	policy["mx"] = mx
+
+	if policy["version"] != "STSv1" {
+		return nil, fmt.Errorf("policy file is not a valid STSv1 policy")
+	}
+
+	return policy, nil
+}
@@ -434,6 +434,10 @@ func (s *HTTPXScanner) processHTTPXResult(result HTTPXResult, scanResults *model
 	findings := s.createFindings(result, service.ID)
 	scanResults.Findings = append(scanResults.Findings, findings...)
 
+	// Record detected technologies as applications running on the service
+	applications := s.createApplications(result, hostname, service.ID)
+	scanResults.Applications = append(scanResults.Applications, applications...)
+
 	// Check if we need to create a new target for the hostname
 	if hostname != "" && hostname != result.Input {
 		// Create a new target for the hostname
@@ -629,6 +633,74 @@ func (s *HTTPXScanner) createFindings(result HTTPXResult, serviceID uuid.UUID) [
 	return findings
 }
 
+// createApplications creates an application for every technology detected on a web server
+func (s *HTTPXScanner) createApplications(result HTTPXResult, hostname string, serviceID uuid.UUID) []models.Application {
+	var applications []models.Application
+	seen := make(map[string]bool)
+
+	for _, tech := range result.Technologies {
+		name, version := parseTechnology(tech)
+		appType := technologyType(name)
+		if appType == "" || seen[appType] {
+			continue
+		}
+		seen[appType] = true
+
+		id := serviceID
+		description := fmt.Sprintf("%s detected on %s", name, result.URL)
+		if version != "" {
+			description = fmt.Sprintf("%s %s detected on %s", name, version, result.URL)
+		}
+
+		applications = append(applications, models.Application{
+			ID:          uuid.New(),
+			ProjectID:   uuid.Nil, // Will be set by worker
+			Name:        truncateString(name, 255),
+			Type:        appType,
+			Version:     truncateString(version, 100),
+			Description: description,
+			URL:         result.URL,
+			ServiceID:   &id,
+			Metadata: models.JSONB{
+				"source":        "httpx",
+				"technology":    tech,
+				"host":          hostname,
+				"title":         result.Title,
+				"webserver":     result.WebServer,
+				"discovered_at": time.Now().Format(time.RFC3339),
+				"last_seen":     time.Now().Format(time.RFC3339),
+			},
+		})
+	}
+
+	return applications
+}
+
+// parseTechnology splits a technology reported by httpx, such as "Nginx:1.19.0", into name and version
+func parseTechnology(tech string) (string, string) {
+	tech = strings.TrimSpace(tech)
+	if i := strings.LastIndex(tech, ":"); i > 0 {
+		return strings.TrimSpace(tech[:i]), strings.TrimSpace(tech[i+1:])
+	}
+	return tech, ""
+}
+
+// technologyType derives the application type from a technology name, such as "Microsoft IIS" to "microsoft-iis"
+func technologyType(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '.' || r == '+' {
+			b.WriteRune(r)
+			dash = false
+		} else if !dash && b.Len() > 0 {
+			b.WriteRune('-')
+			dash = true
+		}
+	}
+	return truncateString(strings.TrimSuffix(b.String(), "-"), 100)
+}
+
 // extractHostFromURL extracts the hostname from a URL
 func (s *HTTPXScanner) extractHostFromURL(url string) string {
 	// Strip protocol
@@ -871,3 +943,25 @@ func (s *HTTPXScanner) SupportsTargetType(targetType string) bool {
 func (s *HTTPXScanner) SupportsServices() bool {
 	return true
 }
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *HTTPXScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"timeout":          integerParam("Request timeout in seconds", 1, 0),
+		"threads":          integerParam("Number of threads", 1, 0),
+		"follow_redirects": booleanParam("Follow redirects"),
+		"tech_detect":      booleanParam("Detect technologies"),
+		"status_code":      booleanParam("Record status codes"),
+		"title":            booleanParam("Record page titles"),
+		"web_server":       booleanParam("Record the web server"),
+		"content_type":     booleanParam("Record the content type"),
+		"tls":              booleanParam("Collect TLS information"),
+		"favicon":          booleanParam("Hash the favicon"),
+		"jarm":             booleanParam("Compute JARM fingerprints"),
+		"probe":            booleanParam("Probe for reachability"),
+		"ports":            stringParam("Ports to probe"),
+		"http2":            booleanParam("Probe for HTTP/2 support"),
+		"security_headers": booleanParam("Check security headers"),
+		"extract_cname":    booleanParam("Record CNAMEs"),
+	})
+}
@@ -7,12 +7,14 @@ package scanner
 
 import (
 	"backend/internal/models"
+	"bytes"
 	"context"
 	"encoding/xml"
 	"fmt"
 	"log"
 	"net"
 	"os/exec"
+	"strconv"
 	"strings"
 	"time"
 
@@ -38,6 +40,10 @@ type Host struct {
 	Hostnames struct {
 		Hostnames []Hostname `xml:"hostname"`
 	} `xml:"hostnames"`
+	OS          OS `xml:"os"`
+	HostScripts struct {
+		Scripts []Script `xml:"script"`
+	} `xml:"hostscript"`
 }
 
 // Status represents the status of a host
@@ -59,10 +65,11 @@ type Ports struct {
 
 // Port represents a single port
 type Port struct {
-	Protocol string  `xml:"protocol,attr"`
-	PortID   int     `xml:"portid,attr"`
-	State    State   `xml:"state"`
-	Service  Service `xml:"service"`
+	Protocol string   `xml:"protocol,attr"`
+	PortID   int      `xml:"portid,attr"`
+	State    State    `xml:"state"`
+	Service  Service  `xml:"service"`
+	Scripts  []Script `xml:"script"`
 }
 
 // State represents the state of a port
@@ -73,10 +80,11 @@ type State struct {
 
 // Service represents a service detected on a port
 type Service struct {
-	Name      string `xml:"name,attr"`
-	Product   string `xml:"product,attr"`
-	Version   string `xml:"version,attr"`
-	ExtraInfo string `xml:"extrainfo,attr"`
+	Name      string   `xml:"name,attr"`
+	Product   string   `xml:"product,attr"`
+	Version   string   `xml:"version,attr"`
+	ExtraInfo string   `xml:"extrainfo,attr"`
+	CPEs      []string `xml:"cpe"`
 }
 
 // Hostname represents a hostname
@@ -85,6 +93,56 @@ type Hostname struct {
 	Type string `xml:"type,attr"`
 }
 
+// OS represents the OS detection results for a host
+type OS struct {
+	Matches []OSMatch `xml:"osmatch"`
+}
+
+// OSMatch represents a single OS guess
+type OSMatch struct {
+	Name     string    `xml:"name,attr"`
+	Accuracy int       `xml:"accuracy,attr"`
+	Classes  []OSClass `xml:"osclass"`
+}
+
+// OSClass represents the classification of an OS guess
+type OSClass struct {
+	Type     string   `xml:"type,attr"`
+	Vendor   string   `xml:"vendor,attr"`
+	Family   string   `xml:"osfamily,attr"`
+	Gen      string   `xml:"osgen,attr"`
+	Accuracy int      `xml:"accuracy,attr"`
+	CPEs     []string `xml:"cpe"`
+}
+
+// Script represents the output of an NSE script run against a port or host
+type Script struct {
+	ID     string        `xml:"id,attr"`
+	Output string        `xml:"output,attr"`
+	Elems  []ScriptElem  `xml:"elem"`
+	Tables []ScriptTable `xml:"table"`
+}
+
+// ScriptElem represents a single key/value element in structured NSE output
+type ScriptElem struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// ScriptTable represents a nested table in structured NSE output
+type ScriptTable struct {
+	Key    string        `xml:"key,attr"`
+	Elems  []ScriptElem  `xml:"elem"`
+	Tables []ScriptTable `xml:"table"`
+}
+
+// NmapServiceTarget identifies a single known service to re-probe with nmap
+type NmapServiceTarget struct {
+	Host     string
+	Port     int
+	Protocol string
+}
+
 // NewNmapScanner creates a new Nmap scanner
 func NewNmapScanner() *NmapScanner {
 	return &NmapScanner{
@@ -116,7 +174,21 @@ func (s *NmapScanner) ConvertTarget(target models.Target) interface{} {
 // ConvertService converts a Service to a format suitable for nmap
 func (s *NmapScanner) ConvertService(service models.Service) interface{} {
 	// For a service, we need the target value and the port
-	return nil // Not directly supported
+	targetValue, ok := service.RawInfo["target_value"].(string)
+	if !ok || targetValue == "" {
+		return nil
+	}
+
+	protocol := strings.ToLower(service.Protocol)
+	if protocol != "tcp" && protocol != "udp" {
+		return nil
+	}
+
+	return NmapServiceTarget{
+		Host:     targetValue,
+		Port:     service.Port,
+		Protocol: protocol,
+	}
 }
 
 // isCIDR checks if a target is a CIDR range
@@ -127,7 +199,18 @@ func (s *NmapScanner) isCIDR(targetValue string) bool {
 
 // Scan performs an nmap scan against the target
 func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
-	targetValue := target.(string)
+	switch t := target.(type) {
+	case NmapServiceTarget:
+		return s.scanService(ctx, t, params)
+	case string:
+		return s.scanHost(ctx, t, params)
+	default:
+		return nil, fmt.Errorf("invalid target format for nmap scanner")
+	}
+}
+
+// scanHost performs an nmap scan against a host or CIDR range
+func (s *NmapScanner) scanHost(ctx context.Context, targetValue string, params models.JSONB) (*models.ScanResults, error) {
 	scanResults := &models.ScanResults{
 		Findings:        []models.Finding{},
 		NewTargets:      []models.Target{},
@@ -156,6 +239,25 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 		timing = val
 	}
 
+	var scripts []string
+	if val, ok := params["scripts"].([]interface{}); ok {
+		for _, script := range val {
+			if scriptStr, ok := script.(string); ok && scriptStr != "" {
+				scripts = append(scripts, scriptStr)
+			}
+		}
+	}
+
+	scriptArgs := ""
+	if val, ok := params["script_args"].(string); ok {
+		scriptArgs = val
+	}
+
+	osDetection := scanType == "comprehensive"
+	if val, ok := params["os_detection"].(bool); ok {
+		osDetection = val
+	}
+
 	// Build nmap command based on scan type
 	args := []string{"-oX", "-"} // Output XML to stdout
 
@@ -169,7 +271,6 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 	case "comprehensive":
 		args = append(args, "--top-ports", "2000")
 		args = append(args, "-sV") // Service version detection
-		args = append(args, "-O")  // OS detection
 	case "service":
 		args = append(args, "-sV") // Service version detection
 		args = append(args, "-p", portRange)
@@ -179,6 +280,19 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 		args = append(args, "-p", portRange)
 	}
 
+	// Add OS detection
+	if osDetection {
+		args = append(args, "-O")
+	}
+
+	// Add NSE scripts
+	if len(scripts) > 0 {
+		args = append(args, "--script", strings.Join(scripts, ","))
+		if scriptArgs != "" {
+			args = append(args, "--script-args", scriptArgs)
+		}
+	}
+
 	// Add target
 	args = append(args, targetValue)
 
@@ -277,6 +391,46 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 			}
 		}
 
+		// Store OS detection results on the IP target
+		if osMetadata := s.generateOSMetadata(host.OS); osMetadata != nil {
+			if isCIDR {
+				// The metadata map is shared with the target already added to the results
+				for k, v := range osMetadata {
+					ipTarget.Metadata[k] = v
+				}
+			} else {
+				// Emit the scanned IP as a target so the worker merges the OS metadata into it
+				osTarget := models.Target{
+					ID:         uuid.New(),
+					TargetType: models.TargetTypeIP,
+					Value:      ipAddress,
+					Metadata: models.JSONB{
+						"discovered_from": targetValue,
+						"discovery_scan":  "nmap",
+						"discovered_at":   time.Now().Format(time.RFC3339),
+					},
+				}
+				for k, v := range osMetadata {
+					osTarget.Metadata[k] = v
+				}
+				scanResults.NewTargets = append(scanResults.NewTargets, osTarget)
+
+				// If a hostname was scanned, link it to the IP it resolved to
+				if ipAddress != targetValue {
+					relation := models.TargetRelation{
+						ID:            uuid.New(),
+						SourceID:      uuid.Nil, // Will be set by worker to the original target ID
+						DestinationID: osTarget.ID,
+						RelationType:  models.RelationResolvesTo,
+						Metadata: models.JSONB{
+							"discovered_at": time.Now().Format(time.RFC3339),
+						},
+					}
+					scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+				}
+			}
+		}
+
 		// Count open ports
 		openPortCount := 0
 		targetForFindings := uuid.Nil // If CIDR, use the new IP target ID, otherwise the original target
@@ -290,28 +444,17 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 				openPortCount++
 
 				// Create a service for each open port
-				service := models.Service{
-					ID:          uuid.New(),
-					TargetID:    targetForFindings, // Will be set by worker
-					Port:        port.PortID,
-					Protocol:    port.Protocol,
-					ServiceName: port.Service.Name,
-					Version:     port.Service.Version,
-					Title:       fmt.Sprintf("%s service on port %d", port.Service.Name, port.PortID),
-					Description: s.generateServiceDescription(port),
-					Banner:      port.Service.ExtraInfo,
-					RawInfo: models.JSONB{
-						"product":       port.Service.Product,
-						"version":       port.Service.Version,
-						"extra_info":    port.Service.ExtraInfo,
-						"state":         port.State.State,
-						"reason":        port.State.Reason,
-						"target_value":  ipAddress,
-						"discovered_at": time.Now().Format(time.RFC3339),
-					},
-				}
+				service := s.createService(port, targetForFindings, ipAddress) // Target ID will be set by worker
 				scanResults.Services = append(scanResults.Services, service)
 
+				// Create findings from NSE script output on this port
+				for _, script := range port.Scripts {
+					serviceID := service.ID
+					finding := s.createScriptFinding(script, ipAddress, &port, &serviceID)
+					finding.TargetID = targetForFindings
+					scanResults.Findings = append(scanResults.Findings, finding)
+				}
+
 				// Create a finding for each service
 				// TODO: Change this to actually be a finding.
 				// finding := models.Finding{
@@ -337,6 +480,13 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 			}
 		}
 
+		// Create findings from host level NSE script output
+		for _, script := range host.HostScripts.Scripts {
+			finding := s.createScriptFinding(script, ipAddress, nil, nil)
+			finding.TargetID = targetForFindings
+			scanResults.Findings = append(scanResults.Findings, finding)
+		}
+
 		// Create summary finding for this host
 		if openPortCount > 0 {
 			finding := models.Finding{
@@ -389,6 +539,174 @@ func (s *NmapScanner) Scan(ctx context.Context, target interface{}, params model
 	return scanResults, nil
 }
 
+// scanService re-probes a single known service for version information and script output
+func (s *NmapScanner) scanService(ctx context.Context, target NmapServiceTarget, params models.JSONB) (*models.ScanResults, error) {
+	scanResults := &models.ScanResults{
+		Findings: []models.Finding{},
+		Services: []models.Service{},
+	}
+
+	// Default scan options
+	timing := "4"
+	versionIntensity := ""
+
+	// Override with provided parameters if available
+	if val, ok := params["timing"].(string); ok {
+		timing = val
+	}
+
+	if val, ok := params["version_intensity"].(string); ok {
+		versionIntensity = val
+	}
+
+	var scripts []string
+	if val, ok := params["scripts"].([]interface{}); ok {
+		for _, script := range val {
+			if scriptStr, ok := script.(string); ok && scriptStr != "" {
+				scripts = append(scripts, scriptStr)
+			}
+		}
+	}
+
+	scriptArgs := ""
+	if val, ok := params["script_args"].(string); ok {
+		scriptArgs = val
+	}
+
+	// The host is already known to be up, so skip host discovery and only probe the one port
+	args := []string{"-oX", "-", "-T" + timing, "-Pn", "-sV"}
+
+	if versionIntensity != "" {
+		args = append(args, "--version-intensity", versionIntensity)
+	}
+
+	if target.Protocol == "udp" {
+		args = append(args, "-sU", "-p", fmt.Sprintf("U:%d", target.Port))
+	} else {
+		args = append(args, "-p", fmt.Sprintf("T:%d", target.Port))
+	}
+
+	if len(scripts) > 0 {
+		args = append(args, "--script", strings.Join(scripts, ","))
+		if scriptArgs != "" {
+			args = append(args, "--script-args", scriptArgs)
+		}
+	}
+
+	args = append(args, target.Host)
+
+	// Execute nmap command with timeout
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.binPath, args...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("nmap service scan failed: %w (%s)", err, lastLines(stderr.String(), 5))
+	}
+	output := stdout.Bytes()
+	if !bytes.Contains(output, []byte("<nmaprun")) {
+		return nil, fmt.Errorf("nmap service scan returned no XML output: %s", lastLines(stderr.String(), 5))
+	}
+
+	// Parse XML output
+	var result NmapXML
+	if err := xml.Unmarshal(output, &result); err != nil {
+		return nil, fmt.Errorf("failed to parse nmap output: %w", err)
+	}
+
+	// Find the probed port in the output
+	var probed *Port
+	ipAddress := target.Host
+	for _, host := range result.Hosts {
+		for i := range host.Ports.Ports {
+			port := host.Ports.Ports[i]
+			if port.PortID == target.Port && port.Protocol == target.Protocol {
+				probed = &port
+				ipAddress = host.Address.Addr
+				break
+			}
+		}
+	}
+
+	if probed == nil || !strings.HasPrefix(probed.State.State, "open") {
+		state := "unknown"
+		reason := ""
+		if probed != nil {
+			state = probed.State.State
+			reason = probed.State.Reason
+		}
+
+		finding := models.Finding{
+			Title:       fmt.Sprintf("Service %s:%d/%s is no longer open", target.Host, target.Port, target.Protocol),
+			Description: fmt.Sprintf("Nmap reported port %d/%s on host %s as %s while re-probing a previously discovered service.", target.Port, target.Protocol, target.Host, state),
+			Severity:    models.SeverityInfo,
+			FindingType: "service_not_open",
+			Details: models.JSONB{
+				"target":   target.Host,
+				"port":     target.Port,
+				"protocol": target.Protocol,
+				"state":    state,
+				"reason":   reason,
+			},
+		}
+		scanResults.Findings = append(scanResults.Findings, finding)
+		return scanResults, nil
+	}
+
+	// The worker matches this on target, port and protocol and upserts the existing service
+	service := s.createService(*probed, uuid.Nil, target.Host)
+	service.RawInfo["ip_address"] = ipAddress
+	service.RawInfo["last_probed_at"] = time.Now().Format(time.RFC3339)
+	scanResults.Services = append(scanResults.Services, service)
+
+	for _, script := range probed.Scripts {
+		serviceID := service.ID
+		finding := s.createScriptFinding(script, ipAddress, probed, &serviceID)
+		scanResults.Findings = append(scanResults.Findings, finding)
+	}
+
+	return scanResults, nil
+}
+
+// createService converts an open nmap port into a service
+func (s *NmapScanner) createService(port Port, targetID uuid.UUID, targetValue string) models.Service {
+	service := models.Service{
+		ID:          uuid.New(),
+		TargetID:    targetID,
+		Port:        port.PortID,
+		Protocol:    port.Protocol,
+		ServiceName: port.Service.Name,
+		Version:     port.Service.Version,
+		Title:       fmt.Sprintf("%s service on port %d", port.Service.Name, port.PortID),
+		Description: s.generateServiceDescription(port),
+		Banner:      port.Service.ExtraInfo,
+		RawInfo: models.JSONB{
+			"product":       port.Service.Product,
+			"version":       port.Service.Version,
+			"extra_info":    port.Service.ExtraInfo,
+			"state":         port.State.State,
+			"reason":        port.State.Reason,
+			"target_value":  targetValue,
+			"discovered_at": time.Now().Format(time.RFC3339),
+		},
+	}
+
+	if len(port.Service.CPEs) > 0 {
+		service.RawInfo["cpe"] = port.Service.CPEs
+	}
+
+	if len(port.Scripts) > 0 {
+		scriptOutput := models.JSONB{}
+		for _, script := range port.Scripts {
+			scriptOutput[script.ID] = script.Output
+		}
+		service.RawInfo["scripts"] = scriptOutput
+	}
+
+	return service
+}
+
 // Type returns the scanner type identifier
 func (s *NmapScanner) Type() string {
 	return "nmap"
@@ -406,7 +724,212 @@ func (s *NmapScanner) SupportsTargetType(targetType string) bool {
 
 // SupportsServices indicates whether this scanner can scan services
 func (s *NmapScanner) SupportsServices() bool {
-	return false // Nmap scans hosts, not individual services
+	return true // Known services can be re-probed for version information
+}
+
+// ProbesServices reports whether nmap identifies services with version detection rather than
+// naming them after its port table
+func (s *NmapScanner) ProbesServices(target interface{}, params models.JSONB) bool {
+	if _, ok := target.(NmapServiceTarget); ok {
+		return true
+	}
+	scanType, _ := params["scan_type"].(string)
+	return scanType == "service" || scanType == "comprehensive"
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *NmapScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"scan_type":         enumParam("Scan profile, defaults to basic", "quick", "comprehensive", "service", "all_ports", "basic"),
+		"port_range":        stringParam("Ports passed to nmap -p, defaults to 1-1000"),
+		"timing":            enumParam("Nmap timing template, defaults to 4", "0", "1", "2", "3", "4", "5"),
+		"scripts":           stringListParam("NSE scripts to run"),
+		"script_args":       stringParam("Arguments passed to the NSE scripts"),
+		"os_detection":      booleanParam("Enable OS detection"),
+		"version_intensity": enumParam("Version detection intensity", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9"),
+		"skip_cdn":          booleanParam("Skip targets attributed to a CDN edge network"),
+		"skip_dead":         booleanParam("Skip targets the ping scanner found down"),
+	})
+}
+
+// generateOSMetadata converts nmap OS detection results into target metadata
+func (s *NmapScanner) generateOSMetadata(osResult OS) models.JSONB {
+	if len(osResult.Matches) == 0 {
+		return nil
+	}
+
+	matches := []map[string]interface{}{}
+	for _, match := range osResult.Matches {
+		classes := []map[string]interface{}{}
+		for _, class := range match.Classes {
+			classes = append(classes, map[string]interface{}{
+				"type":     class.Type,
+				"vendor":   class.Vendor,
+				"family":   class.Family,
+				"gen":      class.Gen,
+				"accuracy": class.Accuracy,
+				"cpe":      class.CPEs,
+			})
+		}
+		matches = append(matches, map[string]interface{}{
+			"name":     match.Name,
+			"accuracy": match.Accuracy,
+			"classes":  classes,
+		})
+	}
+
+	// nmap orders matches by accuracy, so the first one is the best guess
+	best := osResult.Matches[0]
+	metadata := models.JSONB{
+		"os_name":     best.Name,
+		"os_accuracy": best.Accuracy,
+		"os_matches":  matches,
+	}
+	if len(best.Classes) > 0 {
+		metadata["os_family"] = best.Classes[0].Family
+		metadata["os_vendor"] = best.Classes[0].Vendor
+	}
+
+	return metadata
+}
+
+// createScriptFinding converts the output of an NSE script into a finding
+func (s *NmapScanner) createScriptFinding(script Script, ipAddress string, port *Port, serviceID *uuid.UUID) models.Finding {
+	title := fmt.Sprintf("Nmap script %s on %s", script.ID, ipAddress)
+	description := fmt.Sprintf("The nmap script %s produced the following output for host %s:", script.ID, ipAddress)
+	details := models.JSONB{
+		"script_id":  script.ID,
+		"output":     script.Output,
+		"ip_address": ipAddress,
+	}
+	// Output of the same script on other ports or hosts is a separate finding
+	fingerprint := script.ID + "@" + ipAddress
+
+	if port != nil {
+		fingerprint = fmt.Sprintf("%s@%s/%s", script.ID, net.JoinHostPort(ipAddress, strconv.Itoa(port.PortID)), port.Protocol)
+		title = fmt.Sprintf("Nmap script %s on %s:%d/%s", script.ID, ipAddress, port.PortID, port.Protocol)
+		description = fmt.Sprintf("The nmap script %s produced the following output for port %d/%s on host %s:",
+			script.ID, port.PortID, port.Protocol, ipAddress)
+		details["port"] = port.PortID
+		details["protocol"] = port.Protocol
+		details["service"] = port.Service.Name
+	}
+
+	description += "\n\n" + strings.TrimSpace(script.Output)
+
+	if structured := s.flattenScriptOutput(script.Elems, script.Tables); structured != nil {
+		details["structured_output"] = structured
+	}
+
+	return models.Finding{
+		ServiceID:   serviceID,
+		Title:       title,
+		Description: description,
+		Severity:    s.determineSeverityForScript(script),
+		FindingType: "nse_" + strings.ReplaceAll(script.ID, "-", "_"),
+		Fingerprint: fingerprint,
+		Details:     details,
+		CVEIDs:      extractCVEIDs(script.Output), // Scripts such as vulners and ssl-heartbleed name the CVEs they check
+	}
+}
+
+// flattenScriptOutput converts structured NSE output into maps and lists.
+// Tables without keys are treated as lists, which is how nmap encodes arrays.
+func (s *NmapScanner) flattenScriptOutput(elems []ScriptElem, tables []ScriptTable) interface{} {
+	if len(elems) == 0 && len(tables) == 0 {
+		return nil
+	}
+
+	isList := true
+	for _, elem := range elems {
+		if elem.Key != "" {
+			isList = false
+		}
+	}
+	for _, table := range tables {
+		if table.Key != "" {
+			isList = false
+		}
+	}
+
+	if isList {
+		list := []interface{}{}
+		for _, elem := range elems {
+			list = append(list, elem.Value)
+		}
+		for _, table := range tables {
+			list = append(list, s.flattenScriptOutput(table.Elems, table.Tables))
+		}
+		return list
+	}
+
+	result := map[string]interface{}{}
+	for _, elem := range elems {
+		result[elem.Key] = elem.Value
+	}
+	for _, table := range tables {
+		result[table.Key] = s.flattenScriptOutput(table.Elems, table.Tables)
+	}
+	return result
+}
+
+// findScriptElem searches structured NSE output for the first element with the given key
+func (s *NmapScanner) findScriptElem(elems []ScriptElem, tables []ScriptTable, key string) string {
+	for _, elem := range elems {
+		if elem.Key == key {
+			return elem.Value
+		}
+	}
+	for _, table := range tables {
+		if value := s.findScriptElem(table.Elems, table.Tables, key); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// determineSeverityForScript sets severity based on the script and its output
+func (s *NmapScanner) determineSeverityForScript(script Script) string {
+	output := strings.ToLower(script.Output)
+
+	// Scripts from the vuln category report their state in the output
+	if strings.Contains(output, "state: vulnerable") {
+		return models.SeverityHigh
+	}
+	if strings.Contains(output, "state: likely vulnerable") {
+		return models.SeverityMedium
+	}
+
+	switch script.ID {
+	case "ssl-enum-ciphers":
+		// The weakest cipher grade offered by the server
+		switch s.findScriptElem(script.Elems, script.Tables, "least strength") {
+		case "A":
+			return models.SeverityInfo
+		case "B":
+			return models.SeverityLow
+		case "C":
+			return models.SeverityMedium
+		case "D", "E", "F":
+			return models.SeverityHigh
+		}
+	case "smb-security-mode":
+		if strings.EqualFold(s.findScriptElem(script.Elems, script.Tables, "account_used"), "guest") {
+			return models.SeverityMedium
+		}
+		switch strings.ToLower(s.findScriptElem(script.Elems, script.Tables, "message_signing")) {
+		case "disabled":
+			return models.SeverityMedium
+		case "supported":
+			return models.SeverityLow
+		}
+	case "smb2-security-mode":
+		if strings.Contains(output, "not required") {
+			return models.SeverityLow
+		}
+	}
+
+	return models.SeverityInfo
 }
 
 // generatePortDescription creates a human-readable description of a port
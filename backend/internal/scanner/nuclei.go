@@ -9,6 +9,7 @@ package scanner
 import (
 	"backend/internal/models"
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -16,10 +17,21 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
+// nucleiStatsInterval is how often nuclei reports its progress, in seconds
+const nucleiStatsInterval = 15
+
+// maxNucleiResultSize caps the length of a result line, which includes the request and response
+const maxNucleiResultSize = 16 << 20
+
+// nucleiStats holds a statistics line of nuclei, such as requests sent and templates matched
+type nucleiStats map[string]interface{}
+
 // NucleiScanner implements the Scanner interface for performing Nuclei scans
 type NucleiScanner struct {
 	binPath      string
@@ -59,10 +71,13 @@ type NucleiResultInfo struct {
 
 // NucleiClassification contains additional classification information
 type NucleiClassification struct {
-	CVEIDs     []string `json:"cve-id,omitempty"`
-	CVSSScore  string   `json:"cvss-score,omitempty"`
-	CVSSVector string   `json:"cvss-metrics,omitempty"`
-	CWEIDs     []string `json:"cwe-id,omitempty"`
+	CVEIDs         []string    `json:"cve-id,omitempty"`
+	CVSSScore      json.Number `json:"cvss-score,omitempty"` // A number, or a string in older templates
+	CVSSVector     string      `json:"cvss-metrics,omitempty"`
+	CWEIDs         []string    `json:"cwe-id,omitempty"`
+	EPSSScore      float64     `json:"epss-score,omitempty"`
+	EPSSPercentile float64     `json:"epss-percentile,omitempty"`
+	CPE            string      `json:"cpe,omitempty"`
 }
 
 // NewNucleiScanner creates a new Nuclei scanner
@@ -221,6 +236,19 @@ func (s *NucleiScanner) Scan(ctx context.Context, target interface{}, params mod
 				severity = append(severity, sevStr)
 			}
 		}
+	} else if val, ok := params["severity"].(string); ok && val != "" {
+		severity = []string{val}
+	} else if _, ok := params["custom_template_paths"]; ok {
+		severity = nil // Custom templates run regardless of their severity unless it is set
+	}
+
+	// Custom templates written by the worker run next to the template paths, instead of the tags
+	if val, ok := params["custom_template_paths"].([]interface{}); ok {
+		for _, path := range val {
+			if pathStr, ok := path.(string); ok {
+				templatePaths = append(templatePaths, pathStr)
+			}
+		}
 	}
 
 	if val, ok := params["timeout"].(float64); ok {
@@ -247,24 +275,30 @@ func (s *NucleiScanner) Scan(ctx context.Context, target interface{}, params mod
 		includeAll = val
 	}
 
-	// Create a temporary file for output
-	outputFile, err := os.CreateTemp("", "nuclei-output-*.json")
-	if err != nil {
-		return nil, fmt.Errorf("failed to create temporary output file: %w", err)
-	}
-	defer os.Remove(outputFile.Name())
-	defer outputFile.Close()
-
-	// Construct the nuclei command
+	// Construct the nuclei command, results are written to stdout as JSON lines and
+	// statistics to stderr as they happen
 	args := []string{
 		"-target", targetValue,
-		"-j",                    // Output in JSON format
-		"-o", outputFile.Name(), // Output file
+		"-jsonl", // Output in JSON lines format
+		"-stats",
+		"-stats-json",
+		"-stats-interval", fmt.Sprintf("%d", nucleiStatsInterval),
+		"-no-color",
 		"-timeout", fmt.Sprintf("%d", timeout),
 		"-rate-limit", fmt.Sprintf("%d", rateLimit),
 		"-bulk-size", fmt.Sprintf("%d", bulkSize),
 	}
 
+	// Scan the crawled endpoints next to the target itself, nuclei reads them from a list file
+	if val, ok := params["crawled_endpoints"].([]interface{}); ok && len(val) > 0 {
+		listFile, err := writeNucleiTargetList(targetValue, val)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(listFile)
+		args[0], args[1] = "-list", listFile
+	}
+
 	// Add template paths if provided
 	for _, path := range templatePaths {
 		args = append(args, "-t", path)
@@ -300,84 +334,164 @@ func (s *NucleiScanner) Scan(ctx context.Context, target interface{}, params mod
 		args = append(args, "-include-all")
 	}
 
-	// Run nuclei with a timeout
+	// Run nuclei, it is killed when the scan is cancelled or times out
 	cmd := exec.CommandContext(ctx, s.binPath, args...)
-	cmd.Stderr = os.Stderr
 
-	// Create a pipe for real-time processing
 	stdoutPipe, err := cmd.StdoutPipe()
 	if err != nil {
 		return nil, fmt.Errorf("failed to create stdout pipe: %w", err)
 	}
+	stderrPipe, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create stderr pipe: %w", err)
+	}
 
 	// Start the command
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("failed to start nuclei scan: %w", err)
 	}
 
-	// Start a goroutine to log progress
+	// Child processes such as headless browsers may keep the pipes open after nuclei is killed,
+	// so they are closed once buffered output had a moment to be read
+	readDone := make(chan struct{})
+	go func() {
+		select {
+		case <-readDone:
+		case <-ctx.Done():
+			select {
+			case <-readDone:
+			case <-time.After(2 * time.Second):
+				stdoutPipe.Close()
+				stderrPipe.Close()
+			}
+		}
+	}()
+
+	reporter := reporterFrom(ctx)
+
+	// Report statistics while the scan runs, other output is only logged
+	var stderrDone sync.WaitGroup
+	stderrDone.Add(1)
 	go func() {
-		scanner := bufio.NewScanner(stdoutPipe)
+		defer stderrDone.Done()
+		scanner := bufio.NewScanner(stderrPipe)
 		for scanner.Scan() {
-			log.Printf("Nuclei progress: %s", scanner.Text())
+			line := scanner.Text()
+			stats, ok := parseNucleiStats(line)
+			if !ok {
+				log.Printf("Nuclei: %s", line)
+				continue
+			}
+			if reporter != nil {
+				reporter.ReportStatus(stats.message(targetValue))
+			}
 		}
 	}()
 
+	// Turn every result into a finding as soon as nuclei writes it
+	reported := 0
+	scanner := bufio.NewScanner(stdoutPipe)
+	scanner.Buffer(make([]byte, 64*1024), maxNucleiResultSize)
+	for scanner.Scan() {
+		finding, ok := s.parseNucleiResult(scanner.Bytes())
+		if !ok {
+			continue
+		}
+
+		if reporter != nil {
+			if err := reporter.ReportFinding(finding); err == nil {
+				reported++
+				continue
+			}
+			log.Printf("Failed to report nuclei finding, returning it with the results: %v", err)
+		}
+		scanResults.Findings = append(scanResults.Findings, finding)
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		log.Printf("Error reading nuclei output: %v", err)
+	}
+	stderrDone.Wait()
+	close(readDone)
+
 	// Wait for the command to complete
 	err = cmd.Wait()
-	// We don't treat this as an error since nuclei will exit with status 1 if vulnerabilities are found
-	if err != nil {
-		log.Printf("Nuclei scan completed with status: %v", err)
+	total := reported + len(scanResults.Findings)
+
+	// What was found before a cancellation or timeout is kept
+	if ctx.Err() != nil {
+		log.Printf("Nuclei scan of %s stopped early (%v), keeping %d findings", targetValue, ctx.Err(), total)
+		if reporter != nil {
+			reporter.ReportStatus(fmt.Sprintf("Nuclei scan of %s stopped early, kept %d findings", targetValue, total))
+		}
+		return scanResults, nil
 	}
 
-	// Parse the results
-	findings, err := s.parseNucleiOutput(outputFile.Name())
+	// We don't treat this as an error since nuclei will exit with status 1 if vulnerabilities are found
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse nuclei output: %w", err)
+		log.Printf("Nuclei scan completed with status: %v", err)
 	}
-
-	// Process findings
-	scanResults.Findings = findings
+	log.Printf("Nuclei scan of %s completed with %d findings", targetValue, total)
 
 	// Return the scan results
 	return scanResults, nil
 }
 
-// parseNucleiOutput reads the Nuclei JSON output and converts it to findings
-func (s *NucleiScanner) parseNucleiOutput(outputFile string) ([]models.Finding, error) {
-	var findings []models.Finding
+// parseNucleiResult converts a line of nuclei JSON output to a finding
+func (s *NucleiScanner) parseNucleiResult(line []byte) (models.Finding, bool) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 || line[0] != '{' {
+		return models.Finding{}, false
+	}
 
-	// Open the output file
-	file, err := os.Open(outputFile)
-	if err != nil {
-		return nil, fmt.Errorf("failed to open nuclei output file: %w", err)
+	var result NucleiResult
+	if err := json.Unmarshal(line, &result); err != nil {
+		log.Printf("Failed to parse nuclei result: %v", err)
+		return models.Finding{}, false
+	}
+	if result.TemplateID == "" {
+		return models.Finding{}, false // Not a result
 	}
-	defer file.Close()
 
-	// Create a scanner to read line by line (Nuclei outputs each result as a separate JSON object)
-	scanner := bufio.NewScanner(file)
-	for scanner.Scan() {
-		line := scanner.Text()
-		if line == "" {
-			continue
-		}
+	return s.createFindingFromNucleiResult(result), true
+}
 
-		var result NucleiResult
-		if err := json.Unmarshal([]byte(line), &result); err != nil {
-			log.Printf("Failed to parse nuclei result: %v", err)
-			continue
-		}
+// parseNucleiStats reads a statistics line written by nuclei with -stats-json
+func parseNucleiStats(line string) (nucleiStats, bool) {
+	line = strings.TrimSpace(line)
+	if !strings.HasPrefix(line, "{") {
+		return nil, false
+	}
 
-		// Create a finding from the result
-		finding := s.createFindingFromNucleiResult(result)
-		findings = append(findings, finding)
+	var stats nucleiStats
+	if err := json.Unmarshal([]byte(line), &stats); err != nil {
+		return nil, false
+	}
+	if _, ok := stats["requests"]; !ok {
+		return nil, false
 	}
+	return stats, true
+}
 
-	if err := scanner.Err(); err != nil {
-		return findings, fmt.Errorf("error reading nuclei output file: %w", err)
+// value returns a statistic as text, nuclei writes them as strings or numbers depending on the version
+func (s nucleiStats) value(key string) string {
+	switch value := s[key].(type) {
+	case string:
+		return value
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return "0"
 	}
+}
 
-	return findings, nil
+// message describes the statistics for the scan status
+func (s nucleiStats) message(target string) string {
+	message := fmt.Sprintf("Nuclei on %s: %s%% done, %s/%s requests, %s matched",
+		target, s.value("percent"), s.value("requests"), s.value("total"), s.value("matched"))
+	if count := s.value("errors"); count != "0" {
+		message += fmt.Sprintf(", %s errors", count)
+	}
+	return message
 }
 
 // createFindingFromNucleiResult converts a Nuclei result to a finding
@@ -403,22 +517,6 @@ func (s *NucleiScanner) createFindingFromNucleiResult(result NucleiResult) model
 		description = fmt.Sprintf("Nuclei found a %s issue using template %s", result.Info.Severity, result.Template)
 	}
 
-	// Add references if available
-	if len(result.Info.Reference) > 0 {
-		description += "\n\nReferences:\n"
-		for _, ref := range result.Info.Reference {
-			description += fmt.Sprintf("- %s\n", ref)
-		}
-	}
-
-	// Add CVSS information if available
-	if result.Info.Classification.CVSSScore != "" {
-		description += fmt.Sprintf("\nCVSS Score: %s", result.Info.Classification.CVSSScore)
-		if result.Info.Classification.CVSSVector != "" {
-			description += fmt.Sprintf("\nCVSS Vector: %s", result.Info.Classification.CVSSVector)
-		}
-	}
-
 	// Add matched data if available
 	if result.MatchedAt != "" {
 		description += fmt.Sprintf("\n\nMatched at: %s", result.MatchedAt)
@@ -442,22 +540,9 @@ func (s *NucleiScanner) createFindingFromNucleiResult(result NucleiResult) model
 		"type":          result.Type,
 	}
 
-	// Add CVE IDs if available
-	if len(result.Info.Classification.CVEIDs) > 0 {
-		details["cves"] = result.Info.Classification.CVEIDs
-	}
-
-	// Add CWE IDs if available
-	if len(result.Info.Classification.CWEIDs) > 0 {
-		details["cwes"] = result.Info.Classification.CWEIDs
-	}
-
-	// Add CVSS information if available
-	if result.Info.Classification.CVSSScore != "" {
-		details["cvss_score"] = result.Info.Classification.CVSSScore
-	}
-	if result.Info.Classification.CVSSVector != "" {
-		details["cvss_vector"] = result.Info.Classification.CVSSVector
+	// Add the CPE of the affected product if available
+	if result.Info.Classification.CPE != "" {
+		details["cpe"] = result.Info.Classification.CPE
 	}
 
 	// Add tags if available
@@ -484,16 +569,65 @@ func (s *NucleiScanner) createFindingFromNucleiResult(result NucleiResult) model
 		Description:  description,
 		Severity:     severity,
 		FindingType:  s.determineFindingType(result),
+		Fingerprint:  nucleiFingerprint(result),
 		Details:      details,
 		DiscoveredAt: time.Now(),
 		Verified:     false, // Requires manual verification
 		Fixed:        false,
 		Manual:       false,
+		CVEIDs:       normalizeCVEIDs(result.Info.Classification.CVEIDs),
+		CWEIDs:       normalizeCWEIDs(result.Info.Classification.CWEIDs),
+		References:   result.Info.Reference,
+	}
+	setCVSS(&finding, result.Info.Classification.CVSSScore.String(), result.Info.Classification.CVSSVector)
+
+	// Templates generated from the CVE feeds carry the EPSS score at the time they were written,
+	// scores from the local exploit data replace it when the finding is stored
+	if result.Info.Classification.EPSSScore > 0 {
+		epssScore := result.Info.Classification.EPSSScore
+		epssPercentile := result.Info.Classification.EPSSPercentile
+		finding.EPSSScore = &epssScore
+		finding.EPSSPercentile = &epssPercentile
 	}
 
 	return finding
 }
 
+// writeNucleiTargetList writes the target and the endpoint URLs to a temporary file, one per line
+func writeNucleiTargetList(targetValue string, endpoints []interface{}) (string, error) {
+	file, err := os.CreateTemp("", "nuclei-targets-*.txt")
+	if err != nil {
+		return "", fmt.Errorf("failed to create nuclei target list: %w", err)
+	}
+	defer file.Close()
+
+	lines := []string{targetValue}
+	for _, endpoint := range endpoints {
+		if url, ok := endpoint.(string); ok && url != "" && url != targetValue {
+			lines = append(lines, url)
+		}
+	}
+	if _, err := file.WriteString(strings.Join(lines, "\n") + "\n"); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write nuclei target list: %w", err)
+	}
+	return file.Name(), nil
+}
+
+// nucleiFingerprint tells apart results of different templates and matchers, and of the same template
+// matching at different locations, since many templates share a finding type
+func nucleiFingerprint(result NucleiResult) string {
+	fingerprint := result.TemplateID
+	if result.Matcher != "" {
+		fingerprint += ":" + result.Matcher
+	}
+	location := result.MatchedAt
+	if location == "" {
+		location = result.Host
+	}
+	return fingerprint + "@" + location
+}
+
 // determineFindingType categorizes the finding based on the template tags and info
 func (s *NucleiScanner) determineFindingType(result NucleiResult) string {
 	// Check if it's a CVE
@@ -540,6 +674,17 @@ func (s *NucleiScanner) determineFindingType(result NucleiResult) string {
 	}
 }
 
+// UsesCrawledEndpoints makes the worker pass the crawled endpoints of the target when use_crawled_endpoints is set
+func (s *NucleiScanner) UsesCrawledEndpoints(params models.JSONB) bool {
+	enabled, _ := params["use_crawled_endpoints"].(bool)
+	return enabled
+}
+
+// UsesCustomTemplates makes the worker write the custom templates selected in the parameters to disk
+func (s *NucleiScanner) UsesCustomTemplates() bool {
+	return true
+}
+
 // Type returns the scanner type identifier
 func (s *NucleiScanner) Type() string {
 	return "nuclei"
@@ -562,3 +707,28 @@ func (s *NucleiScanner) SupportsTargetType(targetType string) bool {
 func (s *NucleiScanner) SupportsServices() bool {
 	return true
 }
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *NucleiScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"template_tags":    stringListParam("Template tags to run, defaults to cve"),
+		"template_paths":   stringListParam("Template paths to run instead of tags"),
+		"template_exclude": stringListParam("Template tags to exclude, defaults to dos"),
+		"severity": map[string]interface{}{
+			"type":        []string{"array", "string"},
+			"description": "Severities to run, as a list or comma separated string",
+			"items":       map[string]interface{}{"type": "string"},
+		},
+		"timeout":               integerParam("Timeout of each request in seconds", 1, 0),
+		"rate_limit":            integerParam("Maximum requests per second", 1, 0),
+		"bulk_size":             integerParam("Number of hosts scanned in parallel per template", 1, 0),
+		"templates_dir":         stringParam("Directory containing the nuclei templates"),
+		"headless":              booleanParam("Run headless browser templates"),
+		"include_all":           booleanParam("Run all templates regardless of tags and severity"),
+		"skip_cdn":              booleanParam("Skip targets attributed to a CDN edge network"),
+		"skip_dead":             booleanParam("Skip targets the ping scanner found down"),
+		"use_crawled_endpoints": booleanParam("Also scan the endpoints the crawler found on the target instead of only its root URL"),
+		"custom_templates":      stringListParam("Custom templates uploaded through the API to run, by template id"),
+		"custom_template_tags":  stringListParam("Tags selecting custom templates uploaded through the API to run"),
+	})
+}
@@ -4,128 +4,499 @@ package scanner
 import (
 	"backend/internal/models"
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"net"
-	"os/exec"
-	"strconv"
+	"os"
 	"strings"
+	"sync"
+	"syscall"
+	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/net/icmp"
+	"golang.org/x/net/ipv4"
 )
 
-// PingScanner implements the Scanner interface for pinging targets
+// PingScanner implements the Scanner interface for checking whether hosts are alive
 type PingScanner struct {
-	count   int
-	timeout int
-	binPath string
+	count       int
+	timeout     int
+	concurrency int
+	tcpPorts    string
+}
+
+// pingResult holds the outcome of a liveness check against a single host
+type pingResult struct {
+	ip      string
+	alive   bool
+	method  string
+	port    int
+	sent    int
+	rtts    []time.Duration
+	icmpErr error
 }
 
 // NewPingScanner creates a new ping scanner
 func NewPingScanner() *PingScanner {
 	return &PingScanner{
-		count:   3,      // Default to 3 pings
-		timeout: 5,      // Default to 5 second timeout
-		binPath: "ping", // Default ping command
+		count:       3,                              // Default to 3 pings
+		timeout:     2,                              // Default to 2 second timeout per probe
+		concurrency: 64,                             // Hosts probed in parallel during sweeps
+		tcpPorts:    "80,443,22,445,3389,8080,8443", // Ports used for TCP ping
 	}
 }
 
-// Initialize checks if ping is available
+// Initialize has nothing to set up since ICMP and TCP probes are sent natively
 func (s *PingScanner) Initialize(ctx context.Context) error {
-	// Simple check to see if ping is available
-	cmd := exec.CommandContext(ctx, s.binPath, "-c", "1", "-W", "1", "127.0.0.1")
-	_, err := cmd.CombinedOutput()
-	if err != nil {
-		return fmt.Errorf("ping command not available: %w", err)
-	}
-
 	return nil
 }
 
 // ConvertTarget converts a Target to a format suitable for ping
 func (s *PingScanner) ConvertTarget(target models.Target) interface{} {
-	// For ping scanner, we need to ensure we have a hostname or IP
-	// Domain type is preferable, but IP works too
-
-	// If target is a CIDR, we'll just use the network address
-	if target.TargetType == models.TargetTypeCIDR {
-		// Extract the network part before the /
-		parts := strings.Split(target.Value, "/")
-		if len(parts) > 0 {
-			return parts[0]
-		}
+	switch target.TargetType {
+	case models.TargetTypeIP, models.TargetTypeDomain, models.TargetTypeCIDR:
+		return target.Value
+	default:
+		return nil
 	}
+}
 
-	// Otherwise just use the value
-	return target.Value
+// ConvertService returns nil since the ping scanner doesn't scan services
+func (s *PingScanner) ConvertService(service models.Service) interface{} {
+	return nil
 }
 
-// Scan performs a ping scan against the target
-func (s *PingScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) ([]models.Finding, error) {
+// Scan checks which hosts of the target are alive
+func (s *PingScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	if target == nil {
+		return nil, fmt.Errorf("invalid target for ping scanner")
+	}
+
 	targetValue := target.(string)
+	scanResults := &models.ScanResults{
+		Findings:        []models.Finding{},
+		NewTargets:      []models.Target{},
+		TargetRelations: []models.TargetRelation{},
+		Services:        []models.Service{},
+	}
 
 	// Configure ping parameters
 	count := s.count
 	timeout := s.timeout
+	concurrency := s.concurrency
+	tcpPortList := s.tcpPorts
+	method := "auto"
 
 	// Override with provided parameters if available
-	if val, ok := params["count"].(float64); ok {
+	if val, ok := params["count"].(float64); ok && val > 0 {
 		count = int(val)
 	}
-
-	if val, ok := params["timeout"].(float64); ok {
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
 		timeout = int(val)
 	}
+	if val, ok := params["concurrency"].(float64); ok && val > 0 {
+		concurrency = int(val)
+	}
+	if val, ok := params["tcp_ports"].(string); ok && val != "" {
+		tcpPortList = val
+	}
+	if val, ok := params["method"].(string); ok && val != "" {
+		method = val
+	}
+
+	switch method {
+	case "auto", "icmp", "tcp":
+	default:
+		return nil, fmt.Errorf("invalid ping method %q, expected auto, icmp or tcp", method)
+	}
+
+	tcpPorts, err := parsePortRange(tcpPortList)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tcp_ports: %w", err)
+	}
 
-	// Build ping command
-	// Different systems use different ping arguments
-	args := []string{
-		"-c", strconv.Itoa(count), // Count of pings
-		"-W", strconv.Itoa(timeout), // Timeout in seconds
+	// Collect the addresses to probe
+	isCIDR := isCIDRValue(targetValue)
+	var hosts []string
+	if isCIDR {
+		hosts, err = expandCIDR(targetValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand CIDR %s: %w", targetValue, err)
+		}
+	} else if ip := net.ParseIP(targetValue); ip != nil {
+		hosts = []string{ip.String()}
+	} else {
+		ips, err := net.DefaultResolver.LookupIP(ctx, "ip4", targetValue)
+		if err != nil || len(ips) == 0 {
+			finding := models.Finding{
+				Title:       fmt.Sprintf("Could not resolve %s", targetValue),
+				Description: fmt.Sprintf("The host %s could not be resolved to an IPv4 address, so its liveness could not be checked.", targetValue),
+				Severity:    models.SeverityInfo,
+				FindingType: "ping_unresolved",
+				Details: models.JSONB{
+					"target": targetValue,
+				},
+			}
+			scanResults.Findings = append(scanResults.Findings, finding)
+			return scanResults, nil
+		}
+		hosts = []string{ips[0].String()}
 	}
 
-	// Add target
-	args = append(args, targetValue)
+	results := s.probeHosts(ctx, hosts, method, count, time.Duration(timeout)*time.Second, tcpPorts, concurrency)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
 
-	// Execute ping command
-	cmd := exec.CommandContext(ctx, s.binPath, args...)
-	output, err := cmd.CombinedOutput()
+	var alive []*pingResult
+	for _, result := range results {
+		if result.alive {
+			alive = append(alive, result)
+		}
+	}
 
-	// Get output regardless of error (ping returns non-zero if host is unreachable)
-	outputStr := string(output)
+	if isCIDR {
+		// Record every live host in the range as a target
+		for _, result := range alive {
+			ipTarget := models.Target{
+				ID:         uuid.New(),
+				ProjectID:  uuid.Nil, // Will be set by worker
+				TargetType: models.TargetTypeIP,
+				Value:      result.ip,
+				Metadata:   s.generateLivenessMetadata(result, targetValue),
+			}
+			scanResults.NewTargets = append(scanResults.NewTargets, ipTarget)
+
+			relation := models.TargetRelation{
+				ID:            uuid.New(),
+				SourceID:      uuid.Nil, // Will be set by worker
+				DestinationID: ipTarget.ID,
+				RelationType:  "contains",
+				Metadata: models.JSONB{
+					"discovered_at": time.Now().Format(time.RFC3339),
+					"discovery":     "ping",
+				},
+			}
+			scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+		}
+
+		var aliveIPs []string
+		for _, result := range alive {
+			aliveIPs = append(aliveIPs, result.ip)
+		}
+
+		finding := models.Finding{
+			Title:       fmt.Sprintf("Ping sweep of %s: %d of %d hosts alive", targetValue, len(alive), len(hosts)),
+			Description: s.generateSweepDescription(targetValue, aliveIPs, len(hosts)),
+			Severity:    models.SeverityInfo,
+			FindingType: "ping_sweep",
+			Details: models.JSONB{
+				"cidr":        targetValue,
+				"hosts_total": len(hosts),
+				"hosts_alive": len(alive),
+				"alive_ips":   aliveIPs,
+			},
+		}
+		scanResults.Findings = append(scanResults.Findings, finding)
+
+		return scanResults, nil
+	}
+
+	result := results[0]
+	details := models.JSONB{
+		"target":     targetValue,
+		"ip":         result.ip,
+		"reachable":  result.alive,
+		"method":     result.method,
+		"ping_count": count,
+		"timeout":    timeout,
+	}
+	for k, v := range s.latencyStats(result) {
+		details[k] = v
+	}
+	if result.icmpErr != nil {
+		details["icmp_error"] = result.icmpErr.Error()
+	}
+
+	if result.alive {
+		// Update the latency stats on the IP target, linking it to the scanned hostname if needed
+		ipTarget := models.Target{
+			ID:         uuid.New(),
+			ProjectID:  uuid.Nil, // Will be set by worker
+			TargetType: models.TargetTypeIP,
+			Value:      result.ip,
+			Metadata:   s.generateLivenessMetadata(result, targetValue),
+		}
+		scanResults.NewTargets = append(scanResults.NewTargets, ipTarget)
+
+		if result.ip != targetValue {
+			relation := models.TargetRelation{
+				ID:            uuid.New(),
+				SourceID:      uuid.Nil, // Will be set by worker
+				DestinationID: ipTarget.ID,
+				RelationType:  models.RelationResolvesTo,
+				Metadata: models.JSONB{
+					"discovered_at": time.Now().Format(time.RFC3339),
+					"discovery":     "ping",
+				},
+			}
+			scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+		}
+	} else if result.ip == targetValue {
+		// Mark the scanned IP as down so later scans can skip it
+		ipTarget := models.Target{
+			ID:         uuid.New(),
+			ProjectID:  uuid.Nil, // Will be set by worker
+			TargetType: models.TargetTypeIP,
+			Value:      result.ip,
+			Metadata: models.JSONB{
+				"discovered_from": targetValue,
+				"discovery_scan":  "ping",
+				"discovered_at":   time.Now().Format(time.RFC3339),
+				"alive":           false,
+			},
+		}
+		scanResults.NewTargets = append(scanResults.NewTargets, ipTarget)
+	}
 
-	// Create finding
 	finding := models.Finding{
-		ScanID:      nil,      // Will be set by worker
-		TargetID:    uuid.Nil, // Will be set by worker
 		Title:       fmt.Sprintf("Ping result for %s", targetValue),
-		Description: s.generateDescription(targetValue, outputStr, err != nil),
-		Severity:    s.determineSeverity(err != nil),
+		Description: s.generateDescription(targetValue, result),
+		Severity:    models.SeverityInfo,
 		FindingType: "ping",
-		Details: models.JSONB{
-			"target":     targetValue,
-			"reachable":  err == nil,
-			"output":     outputStr,
-			"ping_count": count,
-			"timeout":    timeout,
-		},
+		Details:     details,
+	}
+	scanResults.Findings = append(scanResults.Findings, finding)
+
+	return scanResults, nil
+}
+
+// probeHosts checks the liveness of each host using a pool of workers
+func (s *PingScanner) probeHosts(ctx context.Context, hosts []string, method string, count int, timeout time.Duration, tcpPorts []int, concurrency int) []*pingResult {
+	results := make([]*pingResult, len(hosts))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(hosts); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = s.probeHost(ctx, hosts[i], method, count, timeout, tcpPorts)
+			}
+		}()
+	}
+
+	for i := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	// Hosts skipped because of cancellation are reported as not alive
+	for i, result := range results {
+		if result == nil {
+			results[i] = &pingResult{ip: hosts[i]}
+		}
+	}
+
+	return results
+}
+
+// probeHost sends ICMP echo requests where allowed and falls back to TCP connects on common ports
+func (s *PingScanner) probeHost(ctx context.Context, ip string, method string, count int, timeout time.Duration, tcpPorts []int) *pingResult {
+	result := &pingResult{ip: ip}
+
+	if method != "tcp" {
+		rtts, sent, err := s.probeICMP(ctx, ip, count, timeout)
+		result.sent = sent
+		result.icmpErr = err
+		if len(rtts) > 0 {
+			result.alive = true
+			result.method = "icmp"
+			result.rtts = rtts
+			return result
+		}
+		if method == "icmp" {
+			return result
+		}
+	}
+
+	rtt, port, ok := s.probeTCP(ctx, ip, tcpPorts, timeout)
+	if ok {
+		result.alive = true
+		result.method = "tcp"
+		result.port = port
+		result.sent = 1
+		result.rtts = []time.Duration{rtt}
+	}
+
+	return result
+}
+
+// probeICMP sends ICMP echo requests, using an unprivileged socket when the system allows it
+func (s *PingScanner) probeICMP(ctx context.Context, ip string, count int, timeout time.Duration) ([]time.Duration, int, error) {
+	dst := net.ParseIP(ip).To4()
+	if dst == nil {
+		return nil, 0, errors.New("ICMP ping is only supported for IPv4")
+	}
+
+	// Unprivileged ICMP sockets need net.ipv4.ping_group_range, raw sockets need root or CAP_NET_RAW
+	privileged := false
+	conn, err := icmp.ListenPacket("udp4", "0.0.0.0")
+	if err != nil {
+		conn, err = icmp.ListenPacket("ip4:icmp", "0.0.0.0")
+		if err != nil {
+			return nil, 0, fmt.Errorf("ICMP not permitted: %w", err)
+		}
+		privileged = true
+	}
+	defer conn.Close()
+
+	var addr net.Addr = &net.UDPAddr{IP: dst}
+	if privileged {
+		addr = &net.IPAddr{IP: dst}
 	}
 
-	// Attempt to resolve hostname
-	if err == nil {
-		// Try to get IP if target was a hostname
-		ips, err := net.LookupHost(targetValue)
-		if err == nil && len(ips) > 0 {
-			finding.Details["resolved_ips"] = ips
+	id := os.Getpid() & 0xffff
+	var rtts []time.Duration
+	sent := 0
+	buf := make([]byte, 1500)
+
+	for seq := 1; seq <= count; seq++ {
+		if ctx.Err() != nil {
+			break
+		}
+
+		msg := icmp.Message{
+			Type: ipv4.ICMPTypeEcho,
+			Code: 0,
+			Body: &icmp.Echo{ID: id, Seq: seq, Data: []byte("zecas-ping")},
+		}
+		packet, err := msg.Marshal(nil)
+		if err != nil {
+			return rtts, sent, err
+		}
+
+		start := time.Now()
+		if _, err := conn.WriteTo(packet, addr); err != nil {
+			return rtts, sent, err
+		}
+		sent++
+
+		deadline := start.Add(timeout)
+		conn.SetReadDeadline(deadline)
+		for {
+			n, peer, err := conn.ReadFrom(buf)
+			if err != nil {
+				break
+			}
+
+			// Raw sockets receive all ICMP traffic, so match the reply to this probe
+			if !peerIP(peer).Equal(dst) {
+				continue
+			}
+			reply, err := icmp.ParseMessage(1, buf[:n])
+			if err != nil || reply.Type != ipv4.ICMPTypeEchoReply {
+				continue
+			}
+			echo, ok := reply.Body.(*icmp.Echo)
+			if !ok || echo.Seq != seq || (privileged && echo.ID != id) {
+				continue
+			}
+
+			rtts = append(rtts, time.Since(start))
+			break
+		}
+	}
+
+	return rtts, sent, nil
+}
+
+// probeTCP connects to the given ports and treats an accepted or refused connection as a live host
+func (s *PingScanner) probeTCP(ctx context.Context, ip string, ports []int, timeout time.Duration) (time.Duration, int, bool) {
+	dialer := net.Dialer{Timeout: timeout}
+
+	for _, port := range ports {
+		if ctx.Err() != nil {
+			return 0, 0, false
+		}
+
+		start := time.Now()
+		conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(ip, fmt.Sprint(port)))
+		rtt := time.Since(start)
+		if err == nil {
+			conn.Close()
+			return rtt, port, true
 		}
 
-		// Parse stats if possible
-		stats := s.parsePingStats(outputStr)
-		for k, v := range stats {
-			finding.Details[k] = v
+		// A reset means the host is up even though the port is closed
+		if errors.Is(err, syscall.ECONNREFUSED) {
+			return rtt, port, true
 		}
 	}
 
-	return []models.Finding{finding}, nil
+	return 0, 0, false
+}
+
+// latencyStats calculates latency and packet loss statistics for a result
+func (s *PingScanner) latencyStats(result *pingResult) map[string]float64 {
+	stats := make(map[string]float64)
+	if len(result.rtts) == 0 {
+		if result.sent > 0 {
+			stats["packet_loss"] = 100
+		}
+		return stats
+	}
+
+	minRTT, maxRTT, total := math.MaxFloat64, 0.0, 0.0
+	for _, rtt := range result.rtts {
+		ms := float64(rtt.Microseconds()) / 1000
+		minRTT = math.Min(minRTT, ms)
+		maxRTT = math.Max(maxRTT, ms)
+		total += ms
+	}
+
+	stats["min_rtt"] = minRTT
+	stats["avg_rtt"] = total / float64(len(result.rtts))
+	stats["max_rtt"] = maxRTT
+	if result.sent > 0 {
+		stats["packet_loss"] = 100 * float64(result.sent-len(result.rtts)) / float64(result.sent)
+	}
+
+	return stats
+}
+
+// generateLivenessMetadata creates the target metadata for a live host
+func (s *PingScanner) generateLivenessMetadata(result *pingResult, discoveredFrom string) models.JSONB {
+	metadata := models.JSONB{
+		"discovered_from": discoveredFrom,
+		"discovery_scan":  "ping",
+		"discovered_at":   time.Now().Format(time.RFC3339),
+		"alive":           true,
+		"last_seen":       time.Now().Format(time.RFC3339),
+		"liveness_method": result.method,
+	}
+
+	stats := s.latencyStats(result)
+	if val, ok := stats["min_rtt"]; ok {
+		metadata["latency_min_ms"] = val
+		metadata["latency_avg_ms"] = stats["avg_rtt"]
+		metadata["latency_max_ms"] = stats["max_rtt"]
+	}
+	if val, ok := stats["packet_loss"]; ok {
+		metadata["packet_loss"] = val
+	}
+	if result.method == "tcp" {
+		metadata["liveness_port"] = result.port
+	}
+
+	return metadata
 }
 
 // Type returns the scanner type identifier
@@ -133,17 +504,44 @@ func (s *PingScanner) Type() string {
 	return "ping"
 }
 
-// generateDescription creates a human-readable description of ping results
-func (s *PingScanner) generateDescription(target string, output string, failed bool) string {
-	if failed {
-		return fmt.Sprintf("Host %s is unreachable via ICMP ping. This could indicate that the host is down, blocks ICMP packets, or network connectivity issues.", target)
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *PingScanner) SupportsTargetType(targetType string) bool {
+	switch targetType {
+	case models.TargetTypeIP, models.TargetTypeDomain, models.TargetTypeCIDR:
+		return true
+	default:
+		return false
 	}
+}
 
-	// Extract ping statistics if available
-	stats := s.parsePingStats(output)
+// SupportsServices indicates whether this scanner can scan services
+func (s *PingScanner) SupportsServices() bool {
+	return false
+}
 
-	description := fmt.Sprintf("Host %s is reachable via ICMP ping.", target)
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *PingScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"count":       integerParam("Echo requests sent per host", 1, 100),
+		"timeout":     integerParam("Probe timeout in seconds", 1, 0),
+		"concurrency": integerParam("Hosts probed in parallel", 1, 0),
+		"tcp_ports":   stringParam("Ports used for TCP ping"),
+		"method":      enumParam("Liveness method, defaults to auto", "auto", "icmp", "tcp"),
+	})
+}
 
+// generateDescription creates a human-readable description of ping results
+func (s *PingScanner) generateDescription(target string, result *pingResult) string {
+	if !result.alive {
+		return fmt.Sprintf("Host %s did not respond to ICMP echo requests or TCP connections. This could indicate that the host is down, filtered by a firewall, or that there are network connectivity issues.", target)
+	}
+
+	description := fmt.Sprintf("Host %s (%s) is reachable via %s.", target, result.ip, strings.ToUpper(result.method))
+	if result.method == "tcp" {
+		description = fmt.Sprintf("Host %s (%s) is reachable via TCP on port %d.", target, result.ip, result.port)
+	}
+
+	stats := s.latencyStats(result)
 	if val, ok := stats["min_rtt"]; ok {
 		description += fmt.Sprintf("\nPing statistics: min/avg/max = %.2f/%.2f/%.2f ms",
 			val, stats["avg_rtt"], stats["max_rtt"])
@@ -156,62 +554,28 @@ func (s *PingScanner) generateDescription(target string, output string, failed b
 	return description
 }
 
-// determineSeverity sets severity based on ping results
-func (s *PingScanner) determineSeverity(failed bool) string {
-	if failed {
-		return models.SeverityHigh
+// generateSweepDescription creates a human-readable description of a CIDR sweep
+func (s *PingScanner) generateSweepDescription(cidr string, aliveIPs []string, total int) string {
+	if len(aliveIPs) == 0 {
+		return fmt.Sprintf("None of the %d hosts in %s responded to ICMP echo requests or TCP connections.", total, cidr)
 	}
-	return models.SeverityInfo
-}
-
-// parsePingStats extracts metrics from ping output
-func (s *PingScanner) parsePingStats(output string) map[string]float64 {
-	stats := make(map[string]float64)
 
-	// Find the statistics line that typically looks like:
-	// rtt min/avg/max/mdev = 0.083/0.145/0.214/0.055 ms
-	lines := strings.Split(output, "\n")
-	for _, line := range lines {
-		// Look for packet loss info
-		if strings.Contains(line, "packet loss") {
-			parts := strings.Split(line, ",")
-			for _, part := range parts {
-				if strings.Contains(part, "packet loss") {
-					lossStr := strings.TrimSpace(strings.Split(part, "packet loss")[0])
-					lossStr = strings.TrimSuffix(lossStr, "%")
-					if loss, err := strconv.ParseFloat(lossStr, 64); err == nil {
-						stats["packet_loss"] = loss
-					}
-				}
-			}
-		}
-
-		// Look for RTT stats
-		if strings.Contains(line, "min/avg/max") {
-			parts := strings.Split(line, "=")
-			if len(parts) < 2 {
-				continue
-			}
-
-			rtts := strings.Split(strings.TrimSpace(parts[1]), "/")
-			if len(rtts) < 3 {
-				continue
-			}
-
-			// Parse min/avg/max RTTs
-			if min, err := strconv.ParseFloat(rtts[0], 64); err == nil {
-				stats["min_rtt"] = min
-			}
+	description := fmt.Sprintf("%d of %d hosts in %s are alive:", len(aliveIPs), total, cidr)
+	for _, ip := range aliveIPs {
+		description += fmt.Sprintf("\n• %s", ip)
+	}
 
-			if avg, err := strconv.ParseFloat(rtts[1], 64); err == nil {
-				stats["avg_rtt"] = avg
-			}
+	return description
+}
 
-			if max, err := strconv.ParseFloat(rtts[2], 64); err == nil {
-				stats["max_rtt"] = max
-			}
-		}
+// peerIP extracts the IP address from an ICMP peer address
+func peerIP(addr net.Addr) net.IP {
+	switch a := addr.(type) {
+	case *net.UDPAddr:
+		return a.IP
+	case *net.IPAddr:
+		return a.IP
+	default:
+		return nil
 	}
-
-	return stats
 }
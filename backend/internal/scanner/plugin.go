@@ -0,0 +1,401 @@
+// internal/scanner/plugin.go
+package scanner
+
+// Scanner plugins are executables that speak a small JSON protocol over stdin and stdout.
+// For every call the worker starts the plugin, writes a single request and reads a single
+// response:
+//
+//	request:  {"protocol_version": 1, "method": "<method>", "params": {...}}
+//	response: {"protocol_version": 1, "result": {...}, "error": "<message if failed>"}
+//
+// Supported methods:
+//
+//	describe    no params, result is a PluginDescription, optionally with a JSON Schema
+//	            of the parameters the plugin accepts
+//	initialize  no params, result is ignored; an error marks the plugin unavailable
+//	scan        params hold the target or service and the scan parameters,
+//	            result is a models.ScanResults object
+//
+// ConvertTarget and SupportsTargetType are answered from the describe result, so a plugin
+// receives the target or service model as stored in the database. Anything a plugin writes
+// to stderr is passed on to the worker log.
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PluginProtocolVersion is the version of the plugin protocol spoken by this worker
+const PluginProtocolVersion = 1
+
+// PluginTypePrefix is prepended to plugin names to form their scanner type
+const PluginTypePrefix = "plugin:"
+
+// maxPluginResponseSize caps how much output is read from a plugin
+const maxPluginResponseSize = 64 * 1024 * 1024
+
+// pluginNamePattern restricts plugin names to values usable as scanner types
+var pluginNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,40}$`)
+
+// PluginScanner implements the Scanner interface by delegating to an external executable
+type PluginScanner struct {
+	path        string
+	description PluginDescription
+}
+
+// PluginDescription is the result of a plugin's describe method
+type PluginDescription struct {
+	Name             string       `json:"name"`
+	Version          string       `json:"version"`
+	Description      string       `json:"description"`
+	TargetTypes      []string     `json:"target_types"`
+	SupportsServices bool         `json:"supports_services"`
+	ParametersSchema models.JSONB `json:"parameters_schema,omitempty"`
+}
+
+// PluginTarget is the target or service a plugin scan is run against
+type PluginTarget struct {
+	Target  *models.Target
+	Service *models.Service
+}
+
+type pluginRequest struct {
+	ProtocolVersion int         `json:"protocol_version"`
+	Method          string      `json:"method"`
+	Params          interface{} `json:"params,omitempty"`
+}
+
+type pluginResponse struct {
+	ProtocolVersion int             `json:"protocol_version"`
+	Result          json.RawMessage `json:"result,omitempty"`
+	Error           string          `json:"error,omitempty"`
+}
+
+type pluginScanParams struct {
+	Target     *models.Target  `json:"target,omitempty"`
+	Service    *models.Service `json:"service,omitempty"`
+	Parameters models.JSONB    `json:"parameters"`
+}
+
+// PluginDir returns the directory plugins are discovered in, set through SCANNER_PLUGIN_DIR
+func PluginDir() string {
+	if dir := os.Getenv("SCANNER_PLUGIN_DIR"); dir != "" {
+		return dir
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "/opt/zecas/plugins" // Fallback
+	}
+	return filepath.Join(homeDir, ".zecas", "plugins")
+}
+
+// DiscoverPlugins describes every executable in dir and returns a scanner for each valid plugin
+func DiscoverPlugins(ctx context.Context, dir string) []*PluginScanner {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Printf("Failed to read plugin directory %s: %v", dir, err)
+		}
+		return nil
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	var plugins []*PluginScanner
+	seen := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		info, err := os.Stat(path)
+		if err != nil || info.Mode()&0111 == 0 {
+			continue // Not executable
+		}
+
+		plugin, err := NewPluginScanner(ctx, path)
+		if err != nil {
+			log.Printf("Skipping plugin %s: %v", path, err)
+			continue
+		}
+
+		if other, exists := seen[plugin.description.Name]; exists {
+			log.Printf("Skipping plugin %s: name %s is already used by %s", path, plugin.description.Name, other)
+			continue
+		}
+		seen[plugin.description.Name] = path
+
+		log.Printf("Discovered scanner plugin %s %s (%s)", plugin.Type(), plugin.description.Version, path)
+		plugins = append(plugins, plugin)
+	}
+
+	return plugins
+}
+
+// NewPluginScanner creates a scanner for the plugin executable at path by asking it to describe itself
+func NewPluginScanner(ctx context.Context, path string) (*PluginScanner, error) {
+	plugin := &PluginScanner{path: path}
+
+	describeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	var description PluginDescription
+	if err := plugin.call(describeCtx, "describe", nil, &description); err != nil {
+		return nil, err
+	}
+
+	description.Name = strings.ToLower(strings.TrimSpace(description.Name))
+	if !pluginNamePattern.MatchString(description.Name) {
+		return nil, fmt.Errorf("invalid plugin name %q", description.Name)
+	}
+	if len(description.TargetTypes) == 0 && !description.SupportsServices {
+		return nil, fmt.Errorf("plugin %s supports neither target types nor services", description.Name)
+	}
+
+	plugin.description = description
+	return plugin, nil
+}
+
+// Description returns what the plugin reported about itself
+func (s *PluginScanner) Description() PluginDescription {
+	return s.description
+}
+
+// Initialize asks the plugin to verify its own dependencies
+func (s *PluginScanner) Initialize(ctx context.Context) error {
+	initCtx, cancel := context.WithTimeout(ctx, 2*time.Minute)
+	defer cancel()
+
+	if err := s.call(initCtx, "initialize", nil, nil); err != nil {
+		return fmt.Errorf("plugin %s not available: %w", s.description.Name, err)
+	}
+	return nil
+}
+
+// ConvertTarget passes the target on unchanged if the plugin supports its type
+func (s *PluginScanner) ConvertTarget(target models.Target) interface{} {
+	if !s.SupportsTargetType(target.TargetType) {
+		return nil
+	}
+	return PluginTarget{Target: &target}
+}
+
+// ConvertService passes the service on unchanged if the plugin supports services
+func (s *PluginScanner) ConvertService(service models.Service) interface{} {
+	if !s.description.SupportsServices {
+		return nil
+	}
+	return PluginTarget{Service: &service}
+}
+
+// Scan runs the plugin against the target and returns the results it reports
+func (s *PluginScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	pluginTarget, ok := target.(PluginTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for plugin %s", s.description.Name)
+	}
+
+	if params == nil {
+		params = models.JSONB{}
+	}
+
+	scanParams := pluginScanParams{
+		Target:     pluginTarget.Target,
+		Service:    pluginTarget.Service,
+		Parameters: params,
+	}
+
+	var scanResults models.ScanResults
+	if err := s.call(ctx, "scan", scanParams, &scanResults); err != nil {
+		return nil, fmt.Errorf("plugin %s scan failed: %w", s.description.Name, err)
+	}
+
+	s.normalizeResults(&scanResults)
+	return &scanResults, nil
+}
+
+// normalizeResults fills in what the worker relies on but plugins may leave out
+func (s *PluginScanner) normalizeResults(scanResults *models.ScanResults) {
+	for i := range scanResults.NewTargets {
+		if scanResults.NewTargets[i].ID == uuid.Nil {
+			scanResults.NewTargets[i].ID = uuid.New()
+		}
+		if scanResults.NewTargets[i].Metadata == nil {
+			scanResults.NewTargets[i].Metadata = models.JSONB{}
+		}
+		if _, ok := scanResults.NewTargets[i].Metadata["discovery_scan"]; !ok {
+			scanResults.NewTargets[i].Metadata["discovery_scan"] = s.Type()
+			scanResults.NewTargets[i].Metadata["discovered_at"] = time.Now().Format(time.RFC3339)
+		}
+	}
+
+	for i := range scanResults.TargetRelations {
+		if scanResults.TargetRelations[i].ID == uuid.Nil {
+			scanResults.TargetRelations[i].ID = uuid.New()
+		}
+	}
+
+	for i := range scanResults.Services {
+		if scanResults.Services[i].ID == uuid.Nil {
+			scanResults.Services[i].ID = uuid.New()
+		}
+		if scanResults.Services[i].Protocol == "" {
+			scanResults.Services[i].Protocol = "tcp"
+		}
+	}
+
+	for i := range scanResults.Findings {
+		scanResults.Findings[i].Severity = normalizeSeverity(scanResults.Findings[i].Severity)
+		if scanResults.Findings[i].FindingType == "" {
+			scanResults.Findings[i].FindingType = "plugin_" + s.description.Name
+		}
+		scanResults.Findings[i].FindingType = sanitizeFindingType(scanResults.Findings[i].FindingType)
+		if scanResults.Findings[i].Details == nil {
+			scanResults.Findings[i].Details = models.JSONB{}
+		}
+		scanResults.Findings[i].Details["plugin"] = s.description.Name
+	}
+}
+
+// call runs the plugin with a single request and decodes the result of its response into result
+func (s *PluginScanner) call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	request, err := json.Marshal(pluginRequest{
+		ProtocolVersion: PluginProtocolVersion,
+		Method:          method,
+		Params:          params,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %w", method, err)
+	}
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, s.path)
+	cmd.Stdin = bytes.NewReader(append(request, '\n'))
+	cmd.Stdout = &limitedWriter{w: &stdout, remaining: maxPluginResponseSize}
+	cmd.Env = append(os.Environ(), fmt.Sprintf("ZECAS_PLUGIN_PROTOCOL=%d", PluginProtocolVersion))
+
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start plugin: %w", err)
+	}
+
+	name := s.description.Name
+	if name == "" {
+		name = filepath.Base(s.path)
+	}
+	logDone := make(chan struct{})
+	go func() {
+		defer close(logDone)
+		lines := bufio.NewScanner(stderr)
+		for lines.Scan() {
+			log.Printf("[plugin %s] %s", name, lines.Text())
+		}
+	}()
+
+	<-logDone
+	runErr := cmd.Wait()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%s call interrupted: %w", method, ctx.Err())
+	}
+
+	var response pluginResponse
+	if err := json.Unmarshal(bytes.TrimSpace(stdout.Bytes()), &response); err != nil {
+		if runErr != nil {
+			return fmt.Errorf("plugin exited with %v", runErr)
+		}
+		return fmt.Errorf("invalid %s response: %w", method, err)
+	}
+
+	if response.ProtocolVersion != PluginProtocolVersion {
+		return fmt.Errorf("unsupported protocol version %d, expected %d", response.ProtocolVersion, PluginProtocolVersion)
+	}
+	if response.Error != "" {
+		return fmt.Errorf("%s", response.Error)
+	}
+	if runErr != nil {
+		return fmt.Errorf("plugin exited with %v", runErr)
+	}
+
+	if result != nil && len(response.Result) > 0 {
+		if err := json.Unmarshal(response.Result, result); err != nil {
+			return fmt.Errorf("invalid %s result: %w", method, err)
+		}
+	}
+
+	return nil
+}
+
+// Type returns the scanner type identifier
+func (s *PluginScanner) Type() string {
+	return PluginTypePrefix + s.description.Name
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *PluginScanner) SupportsTargetType(targetType string) bool {
+	for _, supported := range s.description.TargetTypes {
+		if supported == targetType {
+			return true
+		}
+	}
+	return false
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *PluginScanner) SupportsServices() bool {
+	return s.description.SupportsServices
+}
+
+// ParametersSchema returns the JSON Schema the plugin reported for its parameters,
+// accepting any parameters if it didn't report one
+func (s *PluginScanner) ParametersSchema() models.JSONB {
+	if s.description.ParametersSchema != nil {
+		return s.description.ParametersSchema
+	}
+	return models.JSONB{
+		"$schema": jsonSchemaDialect,
+		"type":    "object",
+	}
+}
+
+// limitedWriter discards everything written past its limit
+type limitedWriter struct {
+	w         io.Writer
+	remaining int
+}
+
+func (l *limitedWriter) Write(p []byte) (int, error) {
+	n := len(p)
+	if l.remaining <= 0 {
+		return n, nil
+	}
+	if len(p) > l.remaining {
+		p = p[:l.remaining]
+	}
+	l.remaining -= len(p)
+	if _, err := l.w.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
@@ -0,0 +1,644 @@
+// internal/scanner/portscan.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PortScanner implements the Scanner interface for performing native TCP/UDP port scans
+// without depending on any external binaries.
+type PortScanner struct {
+	concurrency int
+	rate        int
+	timeout     int
+}
+
+// maxProbeRate is the highest number of probes per second a scan may send
+const maxProbeRate = 100000
+
+// portScanJob is a single host/port/protocol combination to probe
+type portScanJob struct {
+	host     string
+	port     int
+	protocol string
+}
+
+// portScanResult is the outcome of a single probe that found an open port
+type portScanResult struct {
+	host     string
+	port     int
+	protocol string
+	banner   string
+	latency  time.Duration
+}
+
+// udpProbes contains protocol specific payloads used to solicit a response from common UDP services.
+// Ports not listed here are probed with an empty datagram.
+var udpProbes = map[int][]byte{
+	// DNS: standard query for the root NS records
+	53: {0x13, 0x37, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0x00, 0x01},
+	// NTP: version 3 client request
+	123: append([]byte{0x1b}, make([]byte, 47)...),
+	// SNMP: v1 get-request for sysDescr with community "public"
+	161: {
+		0x30, 0x26, 0x02, 0x01, 0x00, 0x04, 0x06, 0x70, 0x75, 0x62, 0x6c, 0x69, 0x63, 0xa0, 0x19, 0x02,
+		0x01, 0x01, 0x02, 0x01, 0x00, 0x02, 0x01, 0x00, 0x30, 0x0e, 0x30, 0x0c, 0x06, 0x08, 0x2b, 0x06,
+		0x01, 0x02, 0x01, 0x01, 0x01, 0x00, 0x05, 0x00,
+	},
+	// NetBIOS name service: node status request for "*"
+	137: {
+		0x13, 0x37, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x20, 0x43, 0x4b, 0x41,
+		0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41,
+		0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x41, 0x00, 0x00, 0x21,
+		0x00, 0x01,
+	},
+	// SSDP: M-SEARCH discovery request
+	1900: []byte("M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: ssdp:all\r\n\r\n"),
+}
+
+// wellKnownPorts maps common ports to the service name nmap would report for them
+var wellKnownPorts = map[int]string{
+	21:    "ftp",
+	22:    "ssh",
+	23:    "telnet",
+	25:    "smtp",
+	53:    "domain",
+	80:    "http",
+	110:   "pop3",
+	111:   "rpcbind",
+	123:   "ntp",
+	135:   "msrpc",
+	137:   "netbios-ns",
+	139:   "netbios-ssn",
+	143:   "imap",
+	161:   "snmp",
+	389:   "ldap",
+	443:   "https",
+	445:   "microsoft-ds",
+	465:   "smtps",
+	587:   "submission",
+	636:   "ldapssl",
+	993:   "imaps",
+	995:   "pop3s",
+	1433:  "ms-sql-s",
+	1521:  "oracle",
+	1900:  "upnp",
+	2049:  "nfs",
+	3306:  "mysql",
+	3389:  "ms-wbt-server",
+	5432:  "postgresql",
+	5900:  "vnc",
+	6379:  "redis",
+	8080:  "http-proxy",
+	8443:  "https-alt",
+	9200:  "wap-wsp",
+	11211: "memcache",
+	27017: "mongod",
+}
+
+// NewPortScanner creates a new native port scanner
+func NewPortScanner() *PortScanner {
+	return &PortScanner{
+		concurrency: 100,  // Default number of concurrent probes
+		rate:        500,  // Default probes per second
+		timeout:     1500, // Default probe timeout in milliseconds
+	}
+}
+
+// Initialize is a no-op since the port scanner has no external dependencies
+func (s *PortScanner) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for the port scanner
+func (s *PortScanner) ConvertTarget(target models.Target) interface{} {
+	return target.Value
+}
+
+// ConvertService returns nil since the port scanner scans hosts, not services
+func (s *PortScanner) ConvertService(service models.Service) interface{} {
+	return nil
+}
+
+// Scan performs a TCP connect and UDP probe scan against the target
+func (s *PortScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	targetValue, ok := target.(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid target format for port scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings:        []models.Finding{},
+		NewTargets:      []models.Target{},
+		TargetRelations: []models.TargetRelation{},
+		Services:        []models.Service{},
+	}
+
+	// Default scan options
+	portRange := "1-1000"
+	udpPortRange := ""
+	concurrency := s.concurrency
+	rate := s.rate
+	timeout := s.timeout
+	grabBanner := true
+
+	// Override with provided parameters if available
+	if val, ok := params["port_range"].(string); ok && val != "" {
+		portRange = val
+	}
+	if val, ok := params["udp_port_range"].(string); ok {
+		udpPortRange = val
+	}
+	if val, ok := params["concurrency"].(float64); ok && val > 0 {
+		concurrency = int(val)
+	}
+	if val, ok := params["rate"].(float64); ok && val > 0 {
+		rate = int(val)
+	}
+	if rate > maxProbeRate {
+		rate = maxProbeRate
+	}
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["grab_banner"].(bool); ok {
+		grabBanner = val
+	}
+
+	tcpPorts, err := parsePortRange(portRange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid port_range: %w", err)
+	}
+
+	udpPorts, err := parsePortRange(udpPortRange)
+	if err != nil {
+		return nil, fmt.Errorf("invalid udp_port_range: %w", err)
+	}
+
+	// Resolve the list of hosts to scan
+	isCIDR := isCIDRValue(targetValue)
+	var hosts []string
+	if isCIDR {
+		hosts, err = expandCIDR(targetValue)
+		if err != nil {
+			return nil, fmt.Errorf("failed to expand CIDR %s: %w", targetValue, err)
+		}
+	} else {
+		hosts = []string{targetValue}
+	}
+
+	// Build the job list
+	jobs := make(chan portScanJob)
+	go func() {
+		defer close(jobs)
+		for _, host := range hosts {
+			for _, port := range tcpPorts {
+				select {
+				case jobs <- portScanJob{host: host, port: port, protocol: "tcp"}:
+				case <-ctx.Done():
+					return
+				}
+			}
+			for _, port := range udpPorts {
+				select {
+				case jobs <- portScanJob{host: host, port: port, protocol: "udp"}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	// Rate limit probes across all workers
+	ticker := time.NewTicker(time.Second / time.Duration(rate))
+	defer ticker.Stop()
+
+	probeTimeout := time.Duration(timeout) * time.Millisecond
+	results := make(chan portScanResult)
+	var wg sync.WaitGroup
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range jobs {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+
+				var result *portScanResult
+				if job.protocol == "udp" {
+					result = s.probeUDP(ctx, job, probeTimeout)
+				} else {
+					result = s.probeTCP(ctx, job, probeTimeout, grabBanner)
+				}
+
+				if result != nil {
+					select {
+					case results <- *result:
+					case <-ctx.Done():
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// Collect open ports per host
+	openPorts := make(map[string][]portScanResult)
+	for result := range results {
+		openPorts[result.host] = append(openPorts[result.host], result)
+	}
+
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("port scan interrupted: %w", ctx.Err())
+	}
+
+	// Process results for each host in a stable order
+	liveHosts := make([]string, 0, len(openPorts))
+	for host := range openPorts {
+		liveHosts = append(liveHosts, host)
+	}
+	sort.Strings(liveHosts)
+
+	for _, host := range liveHosts {
+		ports := openPorts[host]
+		sort.Slice(ports, func(i, j int) bool {
+			if ports[i].protocol != ports[j].protocol {
+				return ports[i].protocol < ports[j].protocol
+			}
+			return ports[i].port < ports[j].port
+		})
+
+		targetForFindings := uuid.Nil // If CIDR, use the new IP target ID, otherwise the original target
+
+		// Create a new target for this IP if we're scanning a CIDR range
+		if isCIDR {
+			ipTarget := models.Target{
+				ID:         uuid.New(),
+				TargetType: models.TargetTypeIP,
+				Value:      host,
+				Metadata: models.JSONB{
+					"discovered_from": targetValue,
+					"discovery_scan":  "portscan",
+					"discovered_at":   time.Now().Format(time.RFC3339),
+				},
+			}
+			scanResults.NewTargets = append(scanResults.NewTargets, ipTarget)
+			targetForFindings = ipTarget.ID
+
+			// Create a relationship between the CIDR and this IP
+			relation := models.TargetRelation{
+				ID:            uuid.New(),
+				SourceID:      uuid.Nil, // Will be set by worker to the original CIDR target ID
+				DestinationID: ipTarget.ID,
+				RelationType:  "contains",
+				Metadata: models.JSONB{
+					"discovered_at": time.Now().Format(time.RFC3339),
+				},
+			}
+			scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+		}
+
+		for _, port := range ports {
+			serviceName, known := wellKnownPorts[port.port]
+			if !known {
+				serviceName = "unknown"
+			}
+
+			service := models.Service{
+				ID:          uuid.New(),
+				TargetID:    targetForFindings, // Will be set by worker
+				Port:        port.port,
+				Protocol:    port.protocol,
+				ServiceName: serviceName,
+				Title:       fmt.Sprintf("%s service on port %d", serviceName, port.port),
+				Description: s.generateServiceDescription(port, serviceName),
+				Banner:      port.banner,
+				RawInfo: models.JSONB{
+					"state":         "open",
+					"reason":        s.determineReason(port.protocol),
+					"latency_ms":    port.latency.Milliseconds(),
+					"target_value":  host,
+					"discovered_at": time.Now().Format(time.RFC3339),
+				},
+			}
+			scanResults.Services = append(scanResults.Services, service)
+		}
+
+		// Create summary finding for this host
+		finding := models.Finding{
+			Title:       fmt.Sprintf("Host %s has %d open port(s)", host, len(ports)),
+			Description: fmt.Sprintf("The port scanner discovered %d open port(s) on host %s.", len(ports), host),
+			TargetID:    targetForFindings,
+			Severity:    models.SeverityInfo,
+			FindingType: "port_summary",
+			Details: models.JSONB{
+				"target":          host,
+				"open_port_count": len(ports),
+				"scan_type":       "portscan",
+				"ip_address":      host,
+			},
+		}
+		scanResults.Findings = append(scanResults.Findings, finding)
+	}
+
+	if len(liveHosts) == 0 {
+		if isCIDR {
+			finding := models.Finding{
+				Title:       fmt.Sprintf("No live hosts found in CIDR range %s", targetValue),
+				Description: fmt.Sprintf("The port scanner did not discover any hosts with open ports in the CIDR range %s with the current scan parameters.", targetValue),
+				Severity:    models.SeverityLow,
+				FindingType: "no_live_hosts",
+				Details: models.JSONB{
+					"target":         targetValue,
+					"scan_type":      "portscan",
+					"port_range":     portRange,
+					"udp_port_range": udpPortRange,
+				},
+			}
+			scanResults.Findings = append(scanResults.Findings, finding)
+		} else {
+			finding := models.Finding{
+				Title:       fmt.Sprintf("No open ports found on %s", targetValue),
+				Description: fmt.Sprintf("The port scanner did not discover any open ports on host %s within the specified parameters.", targetValue),
+				Severity:    models.SeverityLow,
+				FindingType: "no_open_ports",
+				Details: models.JSONB{
+					"target":         targetValue,
+					"scan_type":      "portscan",
+					"port_range":     portRange,
+					"udp_port_range": udpPortRange,
+				},
+			}
+			scanResults.Findings = append(scanResults.Findings, finding)
+		}
+	}
+
+	return scanResults, nil
+}
+
+// probeTCP attempts a full TCP connect to the port and optionally reads a banner
+func (s *PortScanner) probeTCP(ctx context.Context, job portScanJob, timeout time.Duration, grabBanner bool) *portScanResult {
+	dialer := net.Dialer{Timeout: timeout}
+	address := net.JoinHostPort(job.host, strconv.Itoa(job.port))
+
+	start := time.Now()
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	result := &portScanResult{
+		host:     job.host,
+		port:     job.port,
+		protocol: job.protocol,
+		latency:  time.Since(start),
+	}
+
+	// Many services (SSH, FTP, SMTP, ...) greet the client, so read whatever is sent first
+	if grabBanner {
+		conn.SetReadDeadline(time.Now().Add(timeout))
+		buf := make([]byte, 512)
+		n, _ := conn.Read(buf)
+		if n > 0 {
+			result.banner = sanitizeBanner(buf[:n])
+		}
+	}
+
+	return result
+}
+
+// probeUDP sends a protocol specific payload and reports the port as open if anything comes back
+func (s *PortScanner) probeUDP(ctx context.Context, job portScanJob, timeout time.Duration) *portScanResult {
+	dialer := net.Dialer{Timeout: timeout}
+	address := net.JoinHostPort(job.host, strconv.Itoa(job.port))
+
+	conn, err := dialer.DialContext(ctx, "udp", address)
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	payload, ok := udpProbes[job.port]
+	if !ok {
+		payload = []byte{}
+	}
+
+	start := time.Now()
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(payload); err != nil {
+		return nil
+	}
+
+	// An ICMP port unreachable surfaces as a read error, a timeout means open|filtered.
+	// Only ports that answer are reported as open.
+	buf := make([]byte, 512)
+	n, err := conn.Read(buf)
+	if err != nil || n == 0 {
+		return nil
+	}
+
+	return &portScanResult{
+		host:     job.host,
+		port:     job.port,
+		protocol: job.protocol,
+		banner:   sanitizeBanner(buf[:n]),
+		latency:  time.Since(start),
+	}
+}
+
+// determineReason mirrors the reason nmap reports for an open port
+func (s *PortScanner) determineReason(protocol string) string {
+	if protocol == "udp" {
+		return "udp-response"
+	}
+	return "syn-ack"
+}
+
+// generateServiceDescription creates a human-readable description of a service
+func (s *PortScanner) generateServiceDescription(port portScanResult, serviceName string) string {
+	desc := fmt.Sprintf("Service detected on port %d/%s.", port.port, port.protocol)
+
+	if serviceName != "unknown" {
+		desc += fmt.Sprintf("\nService guessed from port number: %s", serviceName)
+	}
+
+	if port.banner != "" {
+		desc += fmt.Sprintf("\nBanner: %s", port.banner)
+	}
+
+	return desc
+}
+
+// Type returns the scanner type identifier
+func (s *PortScanner) Type() string {
+	return "portscan"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *PortScanner) SupportsTargetType(targetType string) bool {
+	switch targetType {
+	case models.TargetTypeIP, models.TargetTypeDomain, models.TargetTypeCIDR:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *PortScanner) SupportsServices() bool {
+	return false
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *PortScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"port_range":     stringParam("TCP ports to scan, defaults to 1-1000"),
+		"udp_port_range": stringParam("UDP ports to scan, none by default"),
+		"concurrency":    integerParam("Number of concurrent probes", 1, 0),
+		"rate":           integerParam("Maximum probes per second", 1, maxProbeRate),
+		"timeout":        integerParam("Probe timeout in milliseconds", 1, 0),
+		"grab_banner":    booleanParam("Read banners from open TCP ports, defaults to true"),
+		"skip_cdn":       booleanParam("Skip targets attributed to a CDN edge network"),
+		"skip_dead":      booleanParam("Skip targets the ping scanner found down"),
+	})
+}
+
+// parsePortRange parses an nmap style port specification such as "22,80,8000-8100"
+func parsePortRange(portRange string) ([]int, error) {
+	portRange = strings.TrimSpace(portRange)
+	if portRange == "" {
+		return []int{}, nil
+	}
+	if portRange == "-" {
+		portRange = "1-65535"
+	}
+
+	seen := make(map[int]bool)
+	var ports []int
+	for _, part := range strings.Split(portRange, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		start, end := part, part
+		if strings.Contains(part, "-") {
+			bounds := strings.SplitN(part, "-", 2)
+			start, end = bounds[0], bounds[1]
+			if start == "" {
+				start = "1"
+			}
+			if end == "" {
+				end = "65535"
+			}
+		}
+
+		low, err := strconv.Atoi(start)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", start)
+		}
+		high, err := strconv.Atoi(end)
+		if err != nil {
+			return nil, fmt.Errorf("invalid port %q", end)
+		}
+		if low < 1 || high > 65535 || low > high {
+			return nil, fmt.Errorf("invalid port range %q", part)
+		}
+
+		for port := low; port <= high; port++ {
+			if !seen[port] {
+				seen[port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+
+	return ports, nil
+}
+
+// isCIDRValue checks if a target value is a CIDR range
+func isCIDRValue(targetValue string) bool {
+	_, _, err := net.ParseCIDR(targetValue)
+	return err == nil
+}
+
+// maxCIDRHosts limits the number of addresses expanded from a single CIDR (a /16)
+const maxCIDRHosts = 65536
+
+// expandCIDR returns every usable host address in a CIDR range
+func expandCIDR(cidr string) ([]string, error) {
+	ip, ipNet, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	ones, bits := ipNet.Mask.Size()
+	if bits-ones > 16 {
+		return nil, errors.New("CIDR range is too large, at most a /16 (IPv4) or /112 (IPv6) is supported")
+	}
+
+	var hosts []string
+	for current := ip.Mask(ipNet.Mask); ipNet.Contains(current); current = nextIP(current) {
+		hosts = append(hosts, current.String())
+		if len(hosts) > maxCIDRHosts {
+			break
+		}
+	}
+
+	// Drop the network and broadcast addresses for IPv4 ranges larger than a /31
+	if ip.To4() != nil && len(hosts) > 2 {
+		hosts = hosts[1 : len(hosts)-1]
+	}
+
+	return hosts, nil
+}
+
+// nextIP returns the address following ip
+func nextIP(ip net.IP) net.IP {
+	next := make(net.IP, len(ip))
+	copy(next, ip)
+	for i := len(next) - 1; i >= 0; i-- {
+		next[i]++
+		if next[i] != 0 {
+			break
+		}
+	}
+	return next
+}
+
+// sanitizeBanner strips non-printable characters from a raw banner
+func sanitizeBanner(raw []byte) string {
+	var b strings.Builder
+	for _, c := range raw {
+		switch {
+		case c == '\r':
+			continue
+		case c == '\n' || c == '\t':
+			b.WriteByte(' ')
+		case c >= 0x20 && c < 0x7f:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,31 @@
+// internal/scanner/progress.go
+package scanner
+
+import (
+	"context"
+
+	"backend/internal/models"
+)
+
+// Reporter receives results while a scan is still running, so long scans show progress
+// and don't lose what they found when they are cancelled or time out
+type Reporter interface {
+	// ReportFinding publishes a finding as soon as it is found
+	ReportFinding(finding models.Finding) error
+
+	// ReportStatus describes how far the scan has come
+	ReportStatus(message string)
+}
+
+type reporterKey struct{}
+
+// WithReporter returns a context passing the results of a scan to reporter as they are found
+func WithReporter(ctx context.Context, reporter Reporter) context.Context {
+	return context.WithValue(ctx, reporterKey{}, reporter)
+}
+
+// reporterFrom returns the reporter of a scan, or nil when results are only returned by Scan
+func reporterFrom(ctx context.Context) Reporter {
+	reporter, _ := ctx.Value(reporterKey{}).(Reporter)
+	return reporter
+}
@@ -4,6 +4,9 @@ package scanner
 import (
 	"context"
 	"fmt"
+	"log"
+	"sort"
+	"strings"
 
 	"backend/internal/models"
 
@@ -34,6 +37,51 @@ type Scanner interface {
 
 	// SupportsServices indicates whether this scanner can scan services
 	SupportsServices() bool
+
+	// ParametersSchema returns the JSON Schema of the parameters the scanner accepts
+	ParametersSchema() models.JSONB
+}
+
+// ProjectAwareScanner is implemented by scanners that use the domains already known in the project.
+// The worker passes them to Scan in the project_domains parameter.
+type ProjectAwareScanner interface {
+	UsesProjectDomains() bool
+}
+
+// CustomTemplateScanner is implemented by scanners that run custom templates uploaded through the API.
+// The worker writes the templates selected by the custom_templates and custom_template_tags parameters
+// to disk and passes their paths to Scan in the custom_template_paths parameter.
+type CustomTemplateScanner interface {
+	UsesCustomTemplates() bool
+}
+
+// ServiceProbingScanner is implemented by scanners that identify services by talking to them instead of
+// guessing from the port number. Only their results refresh the name and details of services already known.
+type ServiceProbingScanner interface {
+	ProbesServices(target interface{}, params models.JSONB) bool
+}
+
+// EndpointAwareScanner is implemented by scanners that can run against the paths found by the crawler.
+// When UsesCrawledEndpoints returns true, the worker passes the endpoint URLs stored on the applications
+// of the scanned target or service to Scan in the crawled_endpoints parameter.
+type EndpointAwareScanner interface {
+	UsesCrawledEndpoints(params models.JSONB) bool
+}
+
+// ScannerInfo describes a registered scanner in the scanner catalog
+type ScannerInfo struct {
+	Type             string       `json:"type"`
+	TargetTypes      []string     `json:"target_types"`
+	SupportsServices bool         `json:"supports_services"`
+	Plugin           bool         `json:"plugin"`
+	ParametersSchema models.JSONB `json:"parameters_schema"`
+}
+
+// catalogTargetTypes are the target types reported in the scanner catalog
+var catalogTargetTypes = []string{
+	models.TargetTypeIP,
+	models.TargetTypeCIDR,
+	models.TargetTypeDomain,
 }
 
 // Registry stores and provides access to scanner implementations
@@ -62,6 +110,72 @@ func (r *Registry) Get(name string) (Scanner, error) {
 	return scanner, nil
 }
 
+// RegisterBuiltins adds every scanner compiled into Zecas to the registry
+func (r *Registry) RegisterBuiltins() {
+	r.Register("nmap", NewNmapScanner())
+	r.Register("portscan", NewPortScanner())
+	r.Register("dns", NewDNSScanner())
+	r.Register("subdomain", NewSubdomainScanner())
+	r.Register("nuclei", NewNucleiScanner())
+	r.Register("httpx", NewHTTPXScanner())
+	r.Register("testSSL", NewTestSSLScanner())
+	r.Register("takeover", NewTakeoverScanner())
+	r.Register("tls", NewTLSScanner())
+	r.Register("ping", NewPingScanner())
+	r.Register("command", NewCommandScanner())
+	r.Register("crawler", NewCrawlerScanner())
+	r.Register("contentdiscovery", NewContentDiscoveryScanner())
+	r.Register("banner", NewBannerScanner())
+	r.Register("ssh", NewSSHScanner())
+	r.Register("smtp", NewSMTPScanner())
+	r.Register("vhost", NewVHostScanner())
+}
+
+// RegisterPlugins adds the scanner plugins found in dir, skipping types that are already registered
+func (r *Registry) RegisterPlugins(ctx context.Context, dir string) {
+	for _, plugin := range DiscoverPlugins(ctx, dir) {
+		if _, exists := r.scanners[plugin.Type()]; exists {
+			log.Printf("Skipping plugin %s: scanner type already registered", plugin.Type())
+			continue
+		}
+		r.Register(plugin.Type(), plugin)
+	}
+}
+
+// Catalog describes every registered scanner, sorted by type
+func (r *Registry) Catalog() []ScannerInfo {
+	catalog := make([]ScannerInfo, 0, len(r.scanners))
+	for name, scanner := range r.scanners {
+		info := ScannerInfo{
+			Type:             name,
+			TargetTypes:      []string{},
+			SupportsServices: scanner.SupportsServices(),
+			Plugin:           strings.HasPrefix(name, PluginTypePrefix),
+			ParametersSchema: scanner.ParametersSchema(),
+		}
+		for _, targetType := range catalogTargetTypes {
+			if scanner.SupportsTargetType(targetType) {
+				info.TargetTypes = append(info.TargetTypes, targetType)
+			}
+		}
+		catalog = append(catalog, info)
+	}
+
+	sort.Slice(catalog, func(i, j int) bool {
+		return catalog[i].Type < catalog[j].Type
+	})
+	return catalog
+}
+
+// Validate checks scan parameters against the schema of the named scanner
+func (r *Registry) Validate(name string, params models.JSONB) (map[string]string, error) {
+	scanner, err := r.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	return ValidateParameters(scanner.ParametersSchema(), params), nil
+}
+
 // CreateFinding is a helper function to create a finding
 func CreateFinding(
 	scanID uuid.UUID,
@@ -0,0 +1,281 @@
+// internal/scanner/schema.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// jsonSchemaDialect is the JSON Schema version parameter schemas are written in
+const jsonSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// parametersSchema builds the top level schema of a scanner's parameters.
+// Unknown parameters are rejected so typos don't go unnoticed.
+func parametersSchema(properties map[string]interface{}, required ...string) models.JSONB {
+	schema := models.JSONB{
+		"$schema":              jsonSchemaDialect,
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+// stringParam describes a free-form string parameter
+func stringParam(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": description,
+	}
+}
+
+// enumParam describes a string parameter limited to the given values
+func enumParam(description string, values ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "string",
+		"description": description,
+		"enum":        values,
+	}
+}
+
+// integerParam describes a whole number parameter, a maximum of 0 means unbounded
+func integerParam(description string, minimum int, maximum int) map[string]interface{} {
+	param := map[string]interface{}{
+		"type":        "integer",
+		"description": description,
+		"minimum":     minimum,
+	}
+	if maximum > 0 {
+		param["maximum"] = maximum
+	}
+	return param
+}
+
+// booleanParam describes a true/false parameter
+func booleanParam(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "boolean",
+		"description": description,
+	}
+}
+
+// stringListParam describes a list of strings
+func stringListParam(description string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]interface{}{"type": "string"},
+	}
+}
+
+// enumListParam describes a list of strings limited to the given values
+func enumListParam(description string, values ...string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":        "array",
+		"description": description,
+		"items":       map[string]interface{}{"type": "string", "enum": values},
+	}
+}
+
+// ValidateParameters checks scan parameters against a scanner's JSON Schema and returns
+// an error message for every invalid field, keyed by the path of the field
+func ValidateParameters(schema models.JSONB, params models.JSONB) map[string]string {
+	errs := make(map[string]string)
+	if schema == nil {
+		return errs
+	}
+
+	var value interface{} = map[string]interface{}(params)
+	if params == nil {
+		value = map[string]interface{}{}
+	}
+
+	validateSchemaValue(map[string]interface{}(schema), value, "", errs)
+	return errs
+}
+
+// validateSchemaValue validates a value against the supported subset of JSON Schema:
+// type, enum, properties, required, additionalProperties, items, minItems, minimum,
+// maximum, minLength and pattern
+func validateSchemaValue(schema map[string]interface{}, value interface{}, path string, errs map[string]string) {
+	field := path
+	if field == "" {
+		field = "parameters"
+	}
+
+	if types := schemaStrings(schema["type"]); len(types) > 0 && !matchesSchemaType(value, types) {
+		errs[field] = fmt.Sprintf("must be of type %s", strings.Join(types, " or "))
+		return
+	}
+
+	if enum, ok := schema["enum"]; ok {
+		allowed := schemaStrings(enum)
+		if s, isString := value.(string); isString && !containsString(allowed, s) {
+			errs[field] = fmt.Sprintf("must be one of %s", strings.Join(allowed, ", "))
+			return
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		properties := schemaObject(schema["properties"])
+
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, present := v[name]; !present {
+				errs[joinSchemaPath(path, name)] = "is required"
+			}
+		}
+
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			if propSchema, known := properties[key]; known {
+				if propMap := schemaObject(propSchema); propMap != nil {
+					validateSchemaValue(propMap, v[key], joinSchemaPath(path, key), errs)
+				}
+				continue
+			}
+
+			switch additional := schema["additionalProperties"].(type) {
+			case bool:
+				if !additional {
+					errs[joinSchemaPath(path, key)] = "is not a known parameter"
+				}
+			case map[string]interface{}:
+				validateSchemaValue(additional, v[key], joinSchemaPath(path, key), errs)
+			}
+		}
+
+	case []interface{}:
+		if minItems, ok := schemaNumber(schema["minItems"]); ok && float64(len(v)) < minItems {
+			errs[field] = fmt.Sprintf("must contain at least %v items", minItems)
+		}
+		if items := schemaObject(schema["items"]); items != nil {
+			for i, item := range v {
+				validateSchemaValue(items, item, fmt.Sprintf("%s[%d]", field, i), errs)
+			}
+		}
+
+	case float64:
+		if minimum, ok := schemaNumber(schema["minimum"]); ok && v < minimum {
+			errs[field] = fmt.Sprintf("must be at least %v", minimum)
+		}
+		if maximum, ok := schemaNumber(schema["maximum"]); ok && v > maximum {
+			errs[field] = fmt.Sprintf("must be at most %v", maximum)
+		}
+
+	case string:
+		if minLength, ok := schemaNumber(schema["minLength"]); ok && float64(len(v)) < minLength {
+			errs[field] = fmt.Sprintf("must be at least %v characters long", minLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(v) {
+				errs[field] = fmt.Sprintf("must match %s", pattern)
+			}
+		}
+	}
+}
+
+// matchesSchemaType reports whether a decoded JSON value has one of the given JSON Schema types
+func matchesSchemaType(value interface{}, types []string) bool {
+	for _, t := range types {
+		switch v := value.(type) {
+		case nil:
+			if t == "null" {
+				return true
+			}
+		case string:
+			if t == "string" {
+				return true
+			}
+		case bool:
+			if t == "boolean" {
+				return true
+			}
+		case float64:
+			if t == "number" || (t == "integer" && v == math.Trunc(v)) {
+				return true
+			}
+		case []interface{}:
+			if t == "array" {
+				return true
+			}
+		case map[string]interface{}:
+			if t == "object" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// schemaStrings reads a string or list of strings from a schema keyword
+func schemaStrings(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case []interface{}:
+		var list []string
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				list = append(list, s)
+			}
+		}
+		return list
+	default:
+		return nil
+	}
+}
+
+// schemaObject reads a nested schema, which may be built in Go or decoded from JSON
+func schemaObject(value interface{}) map[string]interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return v
+	case models.JSONB:
+		return v
+	default:
+		return nil
+	}
+}
+
+// schemaNumber reads a numeric schema keyword
+func schemaNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+func joinSchemaPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,901 @@
+// internal/scanner/smtp.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// smtpEnumerationMethods lists the commands tested for user enumeration
+var smtpEnumerationMethods = []string{"VRFY", "EXPN", "RCPT"}
+
+// SMTPScanner implements the Scanner interface for auditing mail servers
+type SMTPScanner struct {
+	timeout           int
+	ports             string
+	heloName          string
+	relayFrom         string
+	relayTo           string
+	users             []string
+	expiryWarningDays int
+}
+
+// SMTPTarget is a mail server to audit
+type SMTPTarget struct {
+	Host    string
+	Domain  string          // Mail domain served by the host, empty when unknown
+	Ports   []int           // Empty when the ports come from the scan parameters
+	Service *models.Service // Set when auditing a known service
+}
+
+// smtpOptions holds the scan parameters shared by every port
+type smtpOptions struct {
+	timeout          time.Duration
+	serverName       string
+	heloName         string
+	domain           string
+	relayFrom        string
+	relayTo          string
+	users            []string
+	checkTLS         bool
+	checkRelay       bool
+	checkEnumeration bool
+}
+
+// smtpConn is an SMTP session, the connection is replaced once STARTTLS succeeds
+type smtpConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	close  func()
+}
+
+// smtpRelay is a relay attempt the server accepted
+type smtpRelay struct {
+	from  string
+	to    string
+	reply string
+}
+
+// smtpEnumeration is the evidence that a command tells existing and unknown users apart
+type smtpEnumeration struct {
+	method       string
+	user         string
+	validReply   string
+	invalidReply string
+}
+
+// smtpResult holds everything learned about a single SMTP endpoint
+type smtpResult struct {
+	port        int
+	implicitTLS bool
+	greeting    string
+	extensions  []string
+	starttls    bool
+	authMethods []string
+	tls         *tlsPortResult
+	tlsError    string
+	relays      []smtpRelay
+	enumeration []smtpEnumeration
+}
+
+// NewSMTPScanner creates a new SMTP scanner
+func NewSMTPScanner() *SMTPScanner {
+	return &SMTPScanner{
+		timeout:           10,                             // Connect and reply timeout in seconds
+		ports:             "25,465,587",                   // Ports checked on IP and domain targets
+		heloName:          "zecas.local",                  // Name sent in EHLO
+		relayFrom:         "zecas-relay-test@example.com", // External sender used for the relay test
+		relayTo:           "zecas-relay-test@example.net", // External recipient used for the relay test
+		users:             []string{"postmaster", "root", "admin"},
+		expiryWarningDays: 30, // Warn about certificates expiring within this many days
+	}
+}
+
+// Initialize has nothing to set up since the scanner speaks SMTP natively
+func (s *SMTPScanner) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for the SMTP audit
+func (s *SMTPScanner) ConvertTarget(target models.Target) interface{} {
+	switch target.TargetType {
+	case models.TargetTypeDomain:
+		// MX hosts found by the DNS scanner remember the domain they receive mail for
+		domain := target.Value
+		if discovery, _ := target.Metadata["discovery_scan"].(string); discovery == "dns_mx" {
+			if from, ok := target.Metadata["discovered_from"].(string); ok && from != "" {
+				domain = from
+			}
+		}
+		return SMTPTarget{Host: target.Value, Domain: domain}
+	case models.TargetTypeIP:
+		return SMTPTarget{Host: target.Value}
+	default:
+		return nil
+	}
+}
+
+// ConvertService converts an SMTP Service to a format suitable for the SMTP audit
+func (s *SMTPScanner) ConvertService(service models.Service) interface{} {
+	if service.Protocol != "" && service.Protocol != "tcp" {
+		return nil
+	}
+
+	// Only audit services known to speak SMTP
+	name := strings.ToLower(service.ServiceName)
+	isSMTP := name == "smtp" || name == "smtps" || name == "submission" ||
+		(name == "" && bannerPorts[service.Port] == "smtp")
+	if !isSMTP {
+		return nil
+	}
+
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return nil
+	}
+
+	domain, _ := service.RawInfo["mail_domain"].(string)
+	if domain == "" && net.ParseIP(host) == nil {
+		domain = host
+	}
+
+	return SMTPTarget{Host: host, Domain: domain, Ports: []int{service.Port}, Service: &service}
+}
+
+// Scan checks STARTTLS and the certificate, open relaying and user enumeration on each port of the mail server
+func (s *SMTPScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	smtpTarget, ok := target.(SMTPTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for smtp scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings:     []models.Finding{},
+		Services:     []models.Service{},
+		Certificates: []models.Certificate{},
+	}
+
+	// Configure scan parameters
+	timeout := s.timeout
+	portList := s.ports
+	expiryWarningDays := s.expiryWarningDays
+	options := smtpOptions{
+		heloName:         s.heloName,
+		domain:           smtpTarget.Domain,
+		relayFrom:        s.relayFrom,
+		relayTo:          s.relayTo,
+		users:            s.users,
+		checkTLS:         true,
+		checkRelay:       true,
+		checkEnumeration: true,
+	}
+
+	// Override with provided parameters if available
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["ports"].(string); ok && val != "" {
+		portList = val
+	}
+	if val, ok := params["helo_name"].(string); ok && val != "" {
+		options.heloName = val
+	}
+	if val, ok := params["domain"].(string); ok && val != "" {
+		options.domain = val
+	}
+	if val, ok := params["relay_from"].(string); ok && val != "" {
+		options.relayFrom = val
+	}
+	if val, ok := params["relay_to"].(string); ok && val != "" {
+		options.relayTo = val
+	}
+	if val, ok := params["users"].([]interface{}); ok && len(val) > 0 {
+		options.users = nil
+		for _, user := range val {
+			if str, ok := user.(string); ok && str != "" {
+				options.users = append(options.users, str)
+			}
+		}
+	}
+	if val, ok := params["check_tls"].(bool); ok {
+		options.checkTLS = val
+	}
+	if val, ok := params["check_relay"].(bool); ok {
+		options.checkRelay = val
+	}
+	if val, ok := params["check_enumeration"].(bool); ok {
+		options.checkEnumeration = val
+	}
+	if val, ok := params["expiry_warning_days"].(float64); ok && val >= 0 {
+		expiryWarningDays = int(val)
+	}
+
+	options.timeout = time.Duration(timeout) * time.Second
+
+	// Only send SNI for hostnames
+	if net.ParseIP(smtpTarget.Host) == nil {
+		options.serverName = smtpTarget.Host
+	}
+
+	ports := smtpTarget.Ports
+	if len(ports) == 0 {
+		parsed, err := parsePortRange(portList)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ports: %w", err)
+		}
+		ports = parsed
+	}
+
+	for _, port := range ports {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		implicitTLS := bannerTLSPorts[port]
+		if smtpTarget.Service != nil {
+			implicitTLS = implicitTLS || bannerTLSNames[strings.ToLower(smtpTarget.Service.ServiceName)]
+		}
+
+		address := net.JoinHostPort(smtpTarget.Host, strconv.Itoa(port))
+		result, err := s.audit(ctx, address, implicitTLS, options)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			// Closed ports are expected when checking the default ports of a target
+			if smtpTarget.Service == nil {
+				continue
+			}
+			return nil, fmt.Errorf("SMTP session with %s failed: %w", address, err)
+		}
+		result.port = port
+		if result.tls != nil {
+			result.tls.port = port
+		}
+
+		service := s.createService(smtpTarget, options.domain, result)
+		scanResults.Services = append(scanResults.Services, service)
+
+		if result.tls != nil {
+			certificate := (&TLSScanner{}).createCertificate(smtpTarget.Host, result.tls)
+			certificate.ServiceID = service.ID
+			scanResults.Certificates = append(scanResults.Certificates, certificate)
+		}
+
+		scanResults.Findings = append(scanResults.Findings, s.createFindings(smtpTarget.Host, options, result, service.ID, expiryWarningDays)...)
+	}
+
+	return scanResults, nil
+}
+
+// audit runs every check against a single SMTP endpoint, each in its own session
+func (s *SMTPScanner) audit(ctx context.Context, address string, implicitTLS bool, options smtpOptions) (*smtpResult, error) {
+	result := &smtpResult{implicitTLS: implicitTLS}
+
+	tlsConfig := &tls.Config{
+		ServerName:         options.serverName,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}
+
+	conn, greeting, err := s.connect(ctx, address, implicitTLS, tlsConfig, options.timeout)
+	result.greeting = greeting
+	if err != nil {
+		return nil, err
+	}
+
+	extensions, err := conn.hello(options.heloName)
+	if err != nil {
+		conn.quit()
+		return nil, err
+	}
+	result.extensions = extensions
+	result.starttls = smtpExtension(extensions, "STARTTLS") != ""
+
+	if implicitTLS {
+		if state, ok := conn.conn.(*tls.Conn); ok {
+			result.tls = s.tlsResult(state.ConnectionState())
+		}
+	} else if result.starttls {
+		state, err := conn.startTLS(tlsConfig)
+		if err != nil {
+			result.tlsError = err.Error()
+		} else {
+			result.tls = s.tlsResult(*state)
+			// Servers commonly only offer authentication once the session is encrypted
+			if tlsExtensions, err := conn.hello(options.heloName); err == nil {
+				extensions = tlsExtensions
+			}
+		}
+	}
+	conn.quit()
+
+	if auth := smtpExtension(extensions, "AUTH"); auth != "" {
+		result.authMethods = strings.Fields(auth)
+	}
+
+	// A certificate without a chain can't be evaluated
+	if result.tls != nil && len(result.tls.chain) == 0 {
+		result.tls = nil
+	}
+
+	if result.tls != nil && options.checkTLS {
+		s.probeVersions(ctx, address, implicitTLS, options, result.tls)
+	}
+	if options.checkRelay {
+		result.relays = s.testRelay(ctx, address, result, options)
+	}
+	if options.checkEnumeration {
+		result.enumeration = s.testEnumeration(ctx, address, result, options)
+	}
+
+	return result, nil
+}
+
+// connect opens an SMTP session and reads the greeting, the greeting is returned even when the server refuses the session
+func (s *SMTPScanner) connect(ctx context.Context, address string, implicitTLS bool, tlsConfig *tls.Config, timeout time.Duration) (*smtpConn, string, error) {
+	dialer := net.Dialer{Timeout: timeout}
+	rawConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, "", err
+	}
+
+	// Abort blocking reads when the scan is cancelled
+	stop := context.AfterFunc(ctx, func() { rawConn.Close() })
+	rawConn.SetDeadline(time.Now().Add(timeout * 3))
+
+	conn := &smtpConn{
+		conn: rawConn,
+		close: func() {
+			stop()
+			rawConn.Close()
+		},
+	}
+
+	if implicitTLS {
+		tlsConn := tls.Client(rawConn, tlsConfig)
+		if err := tlsConn.Handshake(); err != nil {
+			conn.close()
+			return nil, "", fmt.Errorf("TLS handshake failed: %w", err)
+		}
+		conn.conn = tlsConn
+	}
+	conn.reader = bufio.NewReader(conn.conn)
+
+	code, greeting, err := conn.reply()
+	if err != nil {
+		conn.close()
+		return nil, greeting, err
+	}
+	if code != 220 {
+		conn.close()
+		return nil, greeting, fmt.Errorf("server refused the session: %s", truncateString(greeting, 200))
+	}
+
+	return conn, greeting, nil
+}
+
+// session opens a session ready for mail commands, encrypted when the server offers STARTTLS
+func (s *SMTPScanner) session(ctx context.Context, address string, result *smtpResult, options smtpOptions) (*smtpConn, error) {
+	tlsConfig := &tls.Config{
+		ServerName:         options.serverName,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}
+
+	conn, _, err := s.connect(ctx, address, result.implicitTLS, tlsConfig, options.timeout)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.hello(options.heloName); err != nil {
+		conn.quit()
+		return nil, err
+	}
+
+	// Submission ports refuse mail commands before STARTTLS, which would hide a relay
+	if !result.implicitTLS && result.starttls && result.tlsError == "" {
+		if _, err := conn.startTLS(tlsConfig); err != nil {
+			conn.close()
+			return nil, err
+		}
+		if _, err := conn.hello(options.heloName); err != nil {
+			conn.quit()
+			return nil, err
+		}
+	}
+
+	return conn, nil
+}
+
+// probeVersions records the protocol versions the endpoint accepts, with the cipher suite negotiated for each
+func (s *SMTPScanner) probeVersions(ctx context.Context, address string, implicitTLS bool, options smtpOptions, result *tlsPortResult) {
+	insecure := map[uint16]bool{}
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.ID] = true
+	}
+
+	for _, version := range tlsVersions {
+		if ctx.Err() != nil {
+			return
+		}
+
+		tlsConfig := &tls.Config{
+			ServerName:         options.serverName,
+			InsecureSkipVerify: true,
+			MinVersion:         version,
+			MaxVersion:         version,
+		}
+
+		var state tls.ConnectionState
+		conn, _, err := s.connect(ctx, address, implicitTLS, tlsConfig, options.timeout)
+		if err != nil {
+			continue
+		}
+		if implicitTLS {
+			state = conn.conn.(*tls.Conn).ConnectionState()
+		} else {
+			if _, err := conn.hello(options.heloName); err != nil {
+				conn.quit()
+				continue
+			}
+			negotiated, err := conn.startTLS(tlsConfig)
+			if err != nil {
+				conn.close()
+				continue
+			}
+			state = *negotiated
+		}
+		conn.quit()
+
+		versionName := tls.VersionName(version)
+		cipher := tls.CipherSuiteName(state.CipherSuite)
+		result.versions = append(result.versions, versionName)
+		result.ciphers[versionName] = []string{cipher}
+		if insecure[state.CipherSuite] && !containsString(result.weakCipher, cipher) {
+			result.weakCipher = append(result.weakCipher, cipher)
+		}
+	}
+}
+
+// testRelay tries to have mail for an external domain accepted without authenticating, it never sends message data
+func (s *SMTPScanner) testRelay(ctx context.Context, address string, result *smtpResult, options smtpOptions) []smtpRelay {
+	senders := []string{options.relayFrom}
+	if options.domain != "" {
+		// Servers that trust their own domain as sender can be abused with a spoofed local address
+		senders = append(senders, "postmaster@"+options.domain)
+	}
+
+	var relays []smtpRelay
+	for _, from := range senders {
+		if ctx.Err() != nil {
+			break
+		}
+
+		conn, err := s.session(ctx, address, result, options)
+		if err != nil {
+			continue
+		}
+
+		code, _, err := conn.command("MAIL FROM:<" + from + ">")
+		if err == nil && code/100 == 2 {
+			code, reply, err := conn.command("RCPT TO:<" + options.relayTo + ">")
+			if err == nil && code/100 == 2 {
+				relays = append(relays, smtpRelay{from: from, to: options.relayTo, reply: reply})
+			}
+		}
+		conn.command("RSET")
+		conn.quit()
+	}
+	return relays
+}
+
+// testEnumeration compares the replies for existing users with those for a user that can't exist
+func (s *SMTPScanner) testEnumeration(ctx context.Context, address string, result *smtpResult, options smtpOptions) []smtpEnumeration {
+	if len(options.users) == 0 {
+		return nil
+	}
+
+	conn, err := s.session(ctx, address, result, options)
+	if err != nil {
+		return nil
+	}
+	defer conn.quit()
+
+	invalid := "zecas" + randomContentToken()[:10]
+
+	var found []smtpEnumeration
+	for _, method := range smtpEnumerationMethods {
+		if ctx.Err() != nil {
+			break
+		}
+
+		recipient := func(user string) string {
+			if method == "RCPT" {
+				return user + "@" + options.domain
+			}
+			return user
+		}
+
+		if method == "RCPT" {
+			if options.domain == "" {
+				continue
+			}
+			conn.command("RSET")
+			if code, _, err := conn.command("MAIL FROM:<" + options.relayFrom + ">"); err != nil || code/100 != 2 {
+				continue
+			}
+		}
+
+		probe := func(user string) (int, string, error) {
+			if method == "RCPT" {
+				return conn.command("RCPT TO:<" + recipient(user) + ">")
+			}
+			return conn.command(method + " " + recipient(user))
+		}
+
+		// An unknown user has to be rejected outright, 252 means the server won't tell
+		invalidCode, invalidReply, err := probe(invalid)
+		if err != nil {
+			break
+		}
+		if invalidCode/100 != 5 || invalidCode == 500 || invalidCode == 502 || invalidCode == 504 {
+			continue
+		}
+
+		for _, user := range options.users {
+			code, reply, err := probe(user)
+			if err != nil {
+				break
+			}
+			if code == 250 || code == 251 {
+				found = append(found, smtpEnumeration{
+					method:       method,
+					user:         recipient(user),
+					validReply:   reply,
+					invalidReply: invalidReply,
+				})
+				break
+			}
+		}
+	}
+	conn.command("RSET")
+	return found
+}
+
+// tlsResult converts a negotiated TLS session into the result used by the TLS checks
+func (s *SMTPScanner) tlsResult(state tls.ConnectionState) *tlsPortResult {
+	return &tlsPortResult{
+		chain:      state.PeerCertificates,
+		ciphers:    map[string][]string{},
+		negotiated: state,
+	}
+}
+
+// reply reads the next reply and parses its code
+func (c *smtpConn) reply() (int, string, error) {
+	reply, err := readReply(c.reader, "smtp")
+	if err != nil {
+		return 0, "", err
+	}
+	reply = strings.TrimSpace(reply)
+	if len(reply) < 3 || !isDigits(reply[:3]) {
+		return 0, reply, fmt.Errorf("invalid SMTP reply: %q", truncateString(reply, 100))
+	}
+	code, _ := strconv.Atoi(reply[:3])
+	return code, reply, nil
+}
+
+// command sends a command and reads its reply
+func (c *smtpConn) command(line string) (int, string, error) {
+	if _, err := c.conn.Write([]byte(line + "\r\n")); err != nil {
+		return 0, "", err
+	}
+	return c.reply()
+}
+
+// hello greets the server with EHLO and returns the advertised extensions, falling back to HELO for old servers
+func (c *smtpConn) hello(name string) ([]string, error) {
+	code, reply, err := c.command("EHLO " + name)
+	if err != nil {
+		return nil, err
+	}
+	if code == 250 {
+		return replyLines(reply, 1), nil
+	}
+
+	code, reply, err = c.command("HELO " + name)
+	if err != nil {
+		return nil, err
+	}
+	if code != 250 {
+		return nil, fmt.Errorf("server rejected HELO: %s", truncateString(reply, 200))
+	}
+	return []string{}, nil
+}
+
+// startTLS upgrades the session and returns the negotiated connection state
+func (c *smtpConn) startTLS(config *tls.Config) (*tls.ConnectionState, error) {
+	code, reply, err := c.command("STARTTLS")
+	if err != nil {
+		return nil, err
+	}
+	if code != 220 {
+		return nil, fmt.Errorf("server rejected STARTTLS: %s", truncateString(reply, 200))
+	}
+
+	tlsConn := tls.Client(c.conn, config)
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, fmt.Errorf("TLS handshake failed: %w", err)
+	}
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+
+	state := tlsConn.ConnectionState()
+	return &state, nil
+}
+
+// quit ends the session politely and closes the connection
+func (c *smtpConn) quit() {
+	c.command("QUIT")
+	c.close()
+}
+
+// createService creates the SMTP service with the capabilities learned during the audit
+func (s *SMTPScanner) createService(target SMTPTarget, domain string, result *smtpResult) models.Service {
+	product, version := identifyProduct(result.greeting)
+
+	smtpInfo := models.JSONB{
+		"greeting":     result.greeting,
+		"extensions":   result.extensions,
+		"starttls":     result.starttls,
+		"implicit_tls": result.implicitTLS,
+		"auth_methods": result.authMethods,
+		"open_relay":   len(result.relays) > 0,
+		"audited_at":   time.Now().Format(time.RFC3339),
+	}
+	if result.tls != nil {
+		smtpInfo["tls_versions"] = result.tls.versions
+		smtpInfo["tls_ciphers"] = result.tls.ciphers
+	}
+	if result.tlsError != "" {
+		smtpInfo["tls_error"] = result.tlsError
+	}
+
+	var methods []string
+	for _, enumeration := range result.enumeration {
+		methods = append(methods, enumeration.method)
+	}
+	smtpInfo["user_enumeration"] = methods
+
+	rawInfo := models.JSONB{
+		"banner":        result.greeting,
+		"target_value":  target.Host,
+		"smtp":          smtpInfo,
+		"discovered_at": time.Now().Format(time.RFC3339),
+	}
+	if domain != "" {
+		rawInfo["mail_domain"] = domain
+	}
+	if product != "" {
+		rawInfo["product"] = product
+	}
+	if version != "" {
+		rawInfo["version"] = version
+	}
+
+	targetID := uuid.Nil // Will be set by worker
+	serviceName := "smtp"
+	if result.implicitTLS {
+		serviceName = "smtps"
+	} else if result.port == 587 {
+		serviceName = "submission"
+	}
+	if target.Service != nil {
+		targetID = target.Service.TargetID
+		if target.Service.ServiceName != "" {
+			serviceName = target.Service.ServiceName
+		}
+	}
+
+	desc := fmt.Sprintf("SMTP server on port %d greeting with %s", result.port, strings.TrimSpace(result.greeting))
+	switch {
+	case result.implicitTLS:
+		desc += "\nWrapped in TLS"
+	case result.starttls:
+		desc += "\nOffers STARTTLS"
+	default:
+		desc += "\nDoes not offer STARTTLS"
+	}
+	if len(result.authMethods) > 0 {
+		desc += fmt.Sprintf("\nAuthentication methods: %s", strings.Join(result.authMethods, ", "))
+	}
+
+	return models.Service{
+		ID:          uuid.New(),
+		TargetID:    targetID,
+		Port:        result.port,
+		Protocol:    "tcp",
+		ServiceName: serviceName,
+		Version:     version,
+		Title:       fmt.Sprintf("%s service on port %d", serviceName, result.port),
+		Description: desc,
+		Banner:      truncateString(result.greeting, 1024),
+		RawInfo:     rawInfo,
+	}
+}
+
+// createFindings reports missing STARTTLS, certificate problems, open relaying and user enumeration
+func (s *SMTPScanner) createFindings(host string, options smtpOptions, result *smtpResult, serviceID uuid.UUID, expiryWarningDays int) []models.Finding {
+	var findings []models.Finding
+	address := net.JoinHostPort(host, strconv.Itoa(result.port))
+
+	newFinding := func(title, description, severity, findingType string, details models.JSONB) models.Finding {
+		id := serviceID
+		details["host"] = host
+		details["port"] = result.port
+		details["greeting"] = result.greeting
+		details["discovered_at"] = time.Now().Format(time.RFC3339)
+		return models.Finding{
+			ID:          uuid.New(),
+			TargetID:    uuid.Nil, // Will be set by worker
+			ServiceID:   &id,
+			Title:       title,
+			Description: description,
+			Severity:    severity,
+			FindingType: findingType,
+			Fingerprint: address, // Keeps the ports of a host apart
+			Details:     details,
+		}
+	}
+
+	// Transport encryption
+	if !result.implicitTLS && !result.starttls {
+		findings = append(findings, newFinding(
+			fmt.Sprintf("SMTP server on %s does not offer STARTTLS", address),
+			fmt.Sprintf("The mail server on %s does not advertise STARTTLS, so mail and credentials sent to it travel in cleartext.", address),
+			models.SeverityMedium,
+			"smtp_starttls_missing",
+			models.JSONB{"extensions": result.extensions},
+		))
+	}
+	if result.tlsError != "" {
+		findings = append(findings, newFinding(
+			fmt.Sprintf("STARTTLS fails on %s", address),
+			fmt.Sprintf("The mail server on %s advertises STARTTLS but the upgrade could not be completed: %s. Senders fall back to cleartext delivery.", address, result.tlsError),
+			models.SeverityMedium,
+			"smtp_starttls_failed",
+			models.JSONB{"error": result.tlsError},
+		))
+	}
+
+	// Certificate quality, the configuration itself is already recorded on the service
+	if result.tls != nil && options.checkTLS {
+		for _, finding := range (&TLSScanner{}).evaluate(host, options.serverName, result.tls, expiryWarningDays) {
+			if finding.FindingType == "tls_summary" {
+				continue
+			}
+			id := serviceID
+			finding.ID = uuid.New()
+			finding.ServiceID = &id
+			findings = append(findings, finding)
+		}
+	}
+
+	// Open relay
+	if len(result.relays) > 0 {
+		severity := models.SeverityHigh
+		attempts := make([]models.JSONB, 0, len(result.relays))
+		desc := fmt.Sprintf("The mail server on %s accepted mail for an external domain without authentication:", address)
+		for _, relay := range result.relays {
+			if relay.from == options.relayFrom {
+				// Any sender can relay, not only spoofed local addresses
+				severity = models.SeverityCritical
+			}
+			attempts = append(attempts, models.JSONB{
+				"mail_from": relay.from,
+				"rcpt_to":   relay.to,
+				"reply":     relay.reply,
+			})
+			desc += fmt.Sprintf("\n  MAIL FROM:<%s> RCPT TO:<%s> answered %s", relay.from, relay.to, relay.reply)
+		}
+		desc += "\nNo message was sent, the session was reset after the recipient was accepted."
+
+		findings = append(findings, newFinding(
+			fmt.Sprintf("Open mail relay on %s", address),
+			desc,
+			severity,
+			"smtp_open_relay",
+			models.JSONB{"accepted": attempts},
+		))
+	}
+
+	// User enumeration
+	if len(result.enumeration) > 0 {
+		var methods []string
+		evidence := make([]models.JSONB, 0, len(result.enumeration))
+		desc := fmt.Sprintf("The mail server on %s answers differently for existing and unknown users, which allows valid accounts to be enumerated:", address)
+		for _, enumeration := range result.enumeration {
+			methods = append(methods, enumeration.method)
+			evidence = append(evidence, models.JSONB{
+				"method":        enumeration.method,
+				"user":          enumeration.user,
+				"valid_reply":   enumeration.validReply,
+				"invalid_reply": enumeration.invalidReply,
+			})
+			desc += fmt.Sprintf("\n  %s %s answered %s, an unknown user %s", enumeration.method, enumeration.user, enumeration.validReply, enumeration.invalidReply)
+		}
+
+		findings = append(findings, newFinding(
+			fmt.Sprintf("SMTP user enumeration on %s", address),
+			desc,
+			models.SeverityMedium,
+			"smtp_user_enumeration",
+			models.JSONB{
+				"methods":  methods,
+				"evidence": evidence,
+			},
+		))
+	}
+
+	return findings
+}
+
+// smtpExtension returns the parameters of an advertised extension, or an empty string when it isn't offered
+func smtpExtension(extensions []string, name string) string {
+	for _, extension := range extensions {
+		keyword, params, _ := strings.Cut(extension, " ")
+		if strings.EqualFold(keyword, name) {
+			if params == "" {
+				return keyword
+			}
+			return params
+		}
+	}
+	return ""
+}
+
+// Type returns the scanner type identifier
+func (s *SMTPScanner) Type() string {
+	return "smtp"
+}
+
+// SupportsTargetType returns true for domains, such as MX hosts, and IP addresses
+func (s *SMTPScanner) SupportsTargetType(targetType string) bool {
+	return targetType == models.TargetTypeDomain || targetType == models.TargetTypeIP
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *SMTPScanner) SupportsServices() bool {
+	return true
+}
+
+// ProbesServices reports that the scanner identifies the server from its SMTP greeting
+func (s *SMTPScanner) ProbesServices(target interface{}, params models.JSONB) bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *SMTPScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"ports":               stringParam("Ports checked on IP and domain targets, services use their own port"),
+		"timeout":             integerParam("Connect and reply timeout in seconds", 1, 0),
+		"helo_name":           stringParam("Name announced in EHLO"),
+		"domain":              stringParam("Mail domain served by the server, defaults to the domain an MX host was found for"),
+		"relay_from":          stringParam("External sender address used for the relay test"),
+		"relay_to":            stringParam("External recipient address used for the relay test"),
+		"users":               stringListParam("Users expected to exist, compared against an unknown user to detect enumeration"),
+		"check_tls":           booleanParam("Check the protocols and certificate offered through STARTTLS"),
+		"check_relay":         booleanParam("Test whether mail for external domains is accepted without authentication"),
+		"check_enumeration":   booleanParam("Test whether VRFY, EXPN and RCPT TO reveal which users exist"),
+		"expiry_warning_days": integerParam("Warn about certificates expiring within this many days", 0, 0),
+	})
+}
@@ -0,0 +1,286 @@
+// internal/scanner/smtp_test.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+// smtpStandInDomain is the mail domain served by the stand-in
+const smtpStandInDomain = "example.test"
+
+// smtpStandIn is a local SMTP server answering the commands the scanner sends
+type smtpStandIn struct {
+	tlsConfig *tls.Config     // Offers STARTTLS when set
+	relay     bool            // Accepts recipients in other domains
+	users     map[string]bool // Existing local users
+	vrfy      bool            // Answers VRFY for existing and unknown users
+	expn      bool            // Answers EXPN for existing and unknown users
+	rcptCheck bool            // Rejects unknown local recipients at RCPT TO
+}
+
+// start listens on a free local port and serves sessions until the test ends, returning the port
+func (st *smtpStandIn) start(t *testing.T) int {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go st.serve(conn)
+		}
+	}()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+// serve runs a single SMTP session
+func (st *smtpStandIn) serve(conn net.Conn) {
+	defer func() { conn.Close() }()
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	reader := bufio.NewReader(conn)
+	send := func(lines ...string) {
+		conn.Write([]byte(strings.Join(lines, "\r\n") + "\r\n"))
+	}
+
+	send("220 mx." + smtpStandInDomain + " ESMTP stand-in")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(strings.TrimRight(line, "\r\n"), " ")
+
+		switch strings.ToUpper(verb) {
+		case "EHLO":
+			lines := []string{"250-mx." + smtpStandInDomain}
+			if st.tlsConfig != nil {
+				if _, encrypted := conn.(*tls.Conn); !encrypted {
+					lines = append(lines, "250-STARTTLS")
+				}
+			}
+			send(append(lines, "250 AUTH PLAIN LOGIN")...)
+		case "HELO":
+			send("250 mx." + smtpStandInDomain)
+		case "STARTTLS":
+			if st.tlsConfig == nil {
+				send("502 5.5.1 Command not implemented")
+				continue
+			}
+			send("220 2.0.0 Ready to start TLS")
+			tlsConn := tls.Server(conn, st.tlsConfig)
+			if err := tlsConn.Handshake(); err != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(conn)
+		case "MAIL":
+			send("250 2.1.0 Ok")
+		case "RCPT":
+			address := strings.Trim(strings.TrimPrefix(strings.ToUpper(arg), "TO:"), "<> ")
+			user, domain, _ := strings.Cut(strings.ToLower(address), "@")
+			switch {
+			case domain != smtpStandInDomain && !st.relay:
+				send("554 5.7.1 Relay access denied")
+			case domain == smtpStandInDomain && st.rcptCheck && !st.users[user]:
+				send("550 5.1.1 User unknown")
+			default:
+				send("250 2.1.5 Ok")
+			}
+		case "VRFY", "EXPN":
+			enabled := st.vrfy
+			if strings.ToUpper(verb) == "EXPN" {
+				enabled = st.expn
+			}
+			switch {
+			case !enabled:
+				send("502 5.5.1 Command not implemented")
+			case st.users[strings.ToLower(arg)]:
+				send("250 2.1.5 " + arg + "@" + smtpStandInDomain)
+			default:
+				send("550 5.1.1 User unknown")
+			}
+		case "RSET":
+			send("250 2.0.0 Ok")
+		case "QUIT":
+			send("221 2.0.0 Bye")
+			return
+		default:
+			send("500 5.5.2 Command unrecognized")
+		}
+	}
+}
+
+// standInTLSConfig returns a server configuration with a self-signed certificate for the stand-in
+func standInTLSConfig(t *testing.T) *tls.Config {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "mx." + smtpStandInDomain},
+		DNSNames:     []string{"mx." + smtpStandInDomain},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(24 * time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create certificate: %v", err)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}
+}
+
+// scanStandIn audits the stand-in and returns the results with the findings indexed by type
+func scanStandIn(t *testing.T, st *smtpStandIn, params models.JSONB) (*models.ScanResults, map[string]models.Finding) {
+	t.Helper()
+
+	port := st.start(t)
+	if params == nil {
+		params = models.JSONB{}
+	}
+	params["timeout"] = float64(2)
+	params["check_tls"] = false
+
+	target := SMTPTarget{Host: "127.0.0.1", Domain: smtpStandInDomain, Ports: []int{port}}
+	results, err := NewSMTPScanner().Scan(context.Background(), target, params)
+	if err != nil {
+		t.Fatalf("scan failed: %v", err)
+	}
+	if len(results.Services) != 1 {
+		t.Fatalf("expected 1 service, got %d", len(results.Services))
+	}
+
+	findings := make(map[string]models.Finding)
+	for _, finding := range results.Findings {
+		findings[finding.FindingType] = finding
+	}
+	return results, findings
+}
+
+func TestSMTPScanStartTLSNotOffered(t *testing.T) {
+	results, findings := scanStandIn(t, &smtpStandIn{}, nil)
+
+	finding, ok := findings["smtp_starttls_missing"]
+	if !ok {
+		t.Fatalf("expected a smtp_starttls_missing finding, got %v", findingTypes(findings))
+	}
+	if finding.Severity != models.SeverityMedium {
+		t.Errorf("expected medium severity, got %s", finding.Severity)
+	}
+	if starttls, _ := results.Services[0].RawInfo["smtp"].(models.JSONB)["starttls"].(bool); starttls {
+		t.Errorf("expected the service to record that STARTTLS isn't offered")
+	}
+}
+
+func TestSMTPScanStartTLSOffered(t *testing.T) {
+	results, findings := scanStandIn(t, &smtpStandIn{tlsConfig: standInTLSConfig(t)}, nil)
+
+	for _, findingType := range []string{"smtp_starttls_missing", "smtp_starttls_failed"} {
+		if _, ok := findings[findingType]; ok {
+			t.Errorf("unexpected %s finding", findingType)
+		}
+	}
+	if starttls, _ := results.Services[0].RawInfo["smtp"].(models.JSONB)["starttls"].(bool); !starttls {
+		t.Errorf("expected the service to record that STARTTLS is offered")
+	}
+	if len(results.Certificates) != 1 {
+		t.Errorf("expected the certificate presented through STARTTLS, got %d certificates", len(results.Certificates))
+	}
+}
+
+func TestSMTPScanOpenRelay(t *testing.T) {
+	_, findings := scanStandIn(t, &smtpStandIn{relay: true}, models.JSONB{"check_enumeration": false})
+
+	finding, ok := findings["smtp_open_relay"]
+	if !ok {
+		t.Fatalf("expected a smtp_open_relay finding, got %v", findingTypes(findings))
+	}
+	// The external sender was accepted, not only the spoofed local one
+	if finding.Severity != models.SeverityCritical {
+		t.Errorf("expected critical severity, got %s", finding.Severity)
+	}
+	if accepted, _ := finding.Details["accepted"].([]models.JSONB); len(accepted) != 2 {
+		t.Errorf("expected both senders to be accepted, got %v", finding.Details["accepted"])
+	}
+}
+
+func TestSMTPScanRelayRejected(t *testing.T) {
+	_, findings := scanStandIn(t, &smtpStandIn{}, models.JSONB{"check_enumeration": false})
+
+	if _, ok := findings["smtp_open_relay"]; ok {
+		t.Errorf("unexpected smtp_open_relay finding for a server rejecting relay at RCPT TO")
+	}
+}
+
+func TestSMTPScanUserEnumeration(t *testing.T) {
+	st := &smtpStandIn{
+		users:     map[string]bool{"postmaster": true},
+		vrfy:      true,
+		expn:      false,
+		rcptCheck: true,
+	}
+	_, findings := scanStandIn(t, st, models.JSONB{"check_relay": false})
+
+	finding, ok := findings["smtp_user_enumeration"]
+	if !ok {
+		t.Fatalf("expected a smtp_user_enumeration finding, got %v", findingTypes(findings))
+	}
+	methods, _ := finding.Details["methods"].([]string)
+	if strings.Join(methods, ",") != "VRFY,RCPT" {
+		t.Errorf("expected enumeration through VRFY and RCPT, got %v", methods)
+	}
+	evidence, _ := finding.Details["evidence"].([]models.JSONB)
+	for _, entry := range evidence {
+		if !strings.HasPrefix(entry["user"].(string), "postmaster") {
+			t.Errorf("expected postmaster to be reported as existing, got %v", entry["user"])
+		}
+	}
+}
+
+func TestSMTPScanNoUserEnumeration(t *testing.T) {
+	// VRFY and EXPN are disabled and every local recipient is accepted, so users can't be told apart
+	st := &smtpStandIn{users: map[string]bool{"postmaster": true}}
+	_, findings := scanStandIn(t, st, models.JSONB{"check_relay": false})
+
+	if _, ok := findings["smtp_user_enumeration"]; ok {
+		t.Errorf("unexpected smtp_user_enumeration finding")
+	}
+}
+
+// findingTypes lists the finding types found, for failure messages
+func findingTypes(findings map[string]models.Finding) []string {
+	var types []string
+	for findingType := range findings {
+		types = append(types, findingType)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,797 @@
+// internal/scanner/ssh.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"context"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshClientIdent is the identification string sent to servers during the audit
+const sshClientIdent = "SSH-2.0-Zecas_SSH_Audit"
+
+// sshMaxPacketLength bounds the unencrypted packet holding the server's KEXINIT
+const sshMaxPacketLength = 256 * 1024
+
+// errSSHAuditDone aborts a handshake once the audit has learned what it needs from it
+var errSSHAuditDone = errors.New("ssh audit complete")
+
+// sshWeakness describes why an SSH algorithm is weak or deprecated
+type sshWeakness struct {
+	severity string
+	reason   string
+}
+
+// sshWeakAlgorithms lists weak or deprecated algorithms per category, following the OpenSSH deprecations
+var sshWeakAlgorithms = map[string]map[string]sshWeakness{
+	"kex": {
+		"diffie-hellman-group1-sha1":         {models.SeverityMedium, "1024-bit Diffie-Hellman group with SHA-1"},
+		"diffie-hellman-group14-sha1":        {models.SeverityLow, "Uses SHA-1"},
+		"diffie-hellman-group-exchange-sha1": {models.SeverityLow, "Uses SHA-1"},
+		"rsa1024-sha1":                       {models.SeverityMedium, "1024-bit RSA key with SHA-1"},
+	},
+	"host_key": {
+		"ssh-dss":                      {models.SeverityMedium, "DSA keys are limited to 1024 bits and removed from OpenSSH"},
+		"ssh-dss-cert-v01@openssh.com": {models.SeverityMedium, "DSA keys are limited to 1024 bits and removed from OpenSSH"},
+		"ssh-rsa":                      {models.SeverityLow, "Signatures use SHA-1"},
+		"ssh-rsa-cert-v01@openssh.com": {models.SeverityLow, "Signatures use SHA-1"},
+	},
+	"cipher": {
+		"none":                        {models.SeverityHigh, "Traffic is not encrypted"},
+		"des-cbc":                     {models.SeverityHigh, "DES uses a 56-bit key"},
+		"3des-cbc":                    {models.SeverityMedium, "64-bit block size is vulnerable to Sweet32"},
+		"blowfish-cbc":                {models.SeverityMedium, "64-bit block size is vulnerable to Sweet32"},
+		"cast128-cbc":                 {models.SeverityMedium, "64-bit block size is vulnerable to Sweet32"},
+		"idea-cbc":                    {models.SeverityMedium, "64-bit block size is vulnerable to Sweet32"},
+		"arcfour":                     {models.SeverityMedium, "RC4 is broken"},
+		"arcfour128":                  {models.SeverityMedium, "RC4 is broken"},
+		"arcfour256":                  {models.SeverityMedium, "RC4 is broken"},
+		"aes128-cbc":                  {models.SeverityLow, "CBC mode is vulnerable to plaintext recovery"},
+		"aes192-cbc":                  {models.SeverityLow, "CBC mode is vulnerable to plaintext recovery"},
+		"aes256-cbc":                  {models.SeverityLow, "CBC mode is vulnerable to plaintext recovery"},
+		"rijndael-cbc@lysator.liu.se": {models.SeverityLow, "CBC mode is vulnerable to plaintext recovery"},
+	},
+	"mac": {
+		"none":                         {models.SeverityHigh, "Traffic is not integrity protected"},
+		"hmac-md5":                     {models.SeverityMedium, "Uses MD5"},
+		"hmac-md5-96":                  {models.SeverityMedium, "Uses MD5 with a truncated tag"},
+		"hmac-md5-etm@openssh.com":     {models.SeverityMedium, "Uses MD5"},
+		"hmac-md5-96-etm@openssh.com":  {models.SeverityMedium, "Uses MD5 with a truncated tag"},
+		"hmac-sha1":                    {models.SeverityLow, "Uses SHA-1"},
+		"hmac-sha1-etm@openssh.com":    {models.SeverityLow, "Uses SHA-1"},
+		"hmac-sha1-96":                 {models.SeverityLow, "Uses SHA-1 with a truncated tag"},
+		"hmac-sha1-96-etm@openssh.com": {models.SeverityLow, "Uses SHA-1 with a truncated tag"},
+		"hmac-ripemd160":               {models.SeverityLow, "Deprecated and removed from OpenSSH"},
+		"hmac-ripemd160@openssh.com":   {models.SeverityLow, "Deprecated and removed from OpenSSH"},
+		"umac-64@openssh.com":          {models.SeverityLow, "64-bit tag"},
+		"umac-64-etm@openssh.com":      {models.SeverityLow, "64-bit tag"},
+	},
+}
+
+// sshCategories describes the finding reported for weak algorithms of each category
+var sshCategories = []struct {
+	category    string
+	findingType string
+	title       string
+}{
+	{"kex", "ssh_weak_kex_algorithm", "Weak SSH key exchange algorithms"},
+	{"host_key", "ssh_weak_host_key", "Weak SSH host key algorithms"},
+	{"cipher", "ssh_weak_cipher", "Weak SSH ciphers"},
+	{"mac", "ssh_weak_mac", "Weak SSH MAC algorithms"},
+}
+
+// sshHostKeyTypes maps the host key algorithms the audit can negotiate to the type of key they sign with
+var sshHostKeyTypes = map[string]string{
+	ssh.KeyAlgoED25519:   ssh.KeyAlgoED25519,
+	ssh.KeyAlgoECDSA256:  ssh.KeyAlgoECDSA256,
+	ssh.KeyAlgoECDSA384:  ssh.KeyAlgoECDSA384,
+	ssh.KeyAlgoECDSA521:  ssh.KeyAlgoECDSA521,
+	ssh.KeyAlgoRSASHA512: ssh.KeyAlgoRSA,
+	ssh.KeyAlgoRSASHA256: ssh.KeyAlgoRSA,
+	ssh.KeyAlgoRSA:       ssh.KeyAlgoRSA,
+	ssh.KeyAlgoDSA:       ssh.KeyAlgoDSA,
+}
+
+// sshAuditKeyExchanges and sshAuditCiphers include legacy algorithms so host keys can be fetched from old servers
+var sshAuditKeyExchanges = []string{
+	"curve25519-sha256", "curve25519-sha256@libssh.org",
+	"ecdh-sha2-nistp256", "ecdh-sha2-nistp384", "ecdh-sha2-nistp521",
+	"diffie-hellman-group14-sha256", "diffie-hellman-group16-sha512",
+	"diffie-hellman-group-exchange-sha256",
+	"diffie-hellman-group14-sha1", "diffie-hellman-group-exchange-sha1", "diffie-hellman-group1-sha1",
+}
+
+var sshAuditCiphers = []string{
+	"aes128-gcm@openssh.com", "aes256-gcm@openssh.com", "chacha20-poly1305@openssh.com",
+	"aes128-ctr", "aes192-ctr", "aes256-ctr",
+	"aes128-cbc", "3des-cbc", "arcfour256", "arcfour128", "arcfour",
+}
+
+// sshSeverityRank orders severities to pick the most severe weakness of a category
+var sshSeverityRank = map[string]int{
+	models.SeverityLow:    1,
+	models.SeverityMedium: 2,
+	models.SeverityHigh:   3,
+}
+
+// SSHScanner implements the Scanner interface for auditing SSH server configurations
+type SSHScanner struct {
+	timeout  int
+	port     int
+	username string
+}
+
+// SSHTarget is an SSH server to audit
+type SSHTarget struct {
+	Host    string
+	Port    int             // Zero when the port comes from the scan parameters
+	Service *models.Service // Set when auditing a known service
+}
+
+// sshKexInit holds the algorithms offered in the server's SSH_MSG_KEXINIT
+type sshKexInit struct {
+	kex                     []string
+	hostKey                 []string
+	ciphersClientServer     []string
+	ciphersServerClient     []string
+	macsClientServer        []string
+	macsServerClient        []string
+	compressionClientServer []string
+	compressionServerClient []string
+}
+
+// sshHostKey describes a host key presented by the server
+type sshHostKey struct {
+	keyType string
+	bits    int
+	sha256  string
+	md5     string
+}
+
+// sshAudit holds everything learned about an SSH server
+type sshAudit struct {
+	ident           string
+	protocolVersion string
+	software        string
+	ipAddress       string
+	kexInit         *sshKexInit
+	hostKeys        []sshHostKey
+	authMethods     []string
+}
+
+// sshIssue is a weak algorithm or key found during the audit
+type sshIssue struct {
+	algorithm string
+	severity  string
+	reason    string
+}
+
+// NewSSHScanner creates a new SSH configuration audit scanner
+func NewSSHScanner() *SSHScanner {
+	return &SSHScanner{
+		timeout:  10,     // Connect and handshake timeout in seconds
+		port:     22,     // Port audited on IP and domain targets
+		username: "root", // Username used to ask which authentication methods are offered
+	}
+}
+
+// Initialize has nothing to set up since the audit speaks SSH natively
+func (s *SSHScanner) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for the SSH audit
+func (s *SSHScanner) ConvertTarget(target models.Target) interface{} {
+	if target.TargetType != models.TargetTypeIP && target.TargetType != models.TargetTypeDomain {
+		return nil
+	}
+	return SSHTarget{Host: target.Value}
+}
+
+// ConvertService converts an SSH Service to a format suitable for the SSH audit
+func (s *SSHScanner) ConvertService(service models.Service) interface{} {
+	if service.Protocol != "" && service.Protocol != "tcp" {
+		return nil
+	}
+
+	// Only audit services known to speak SSH
+	isSSH := strings.EqualFold(service.ServiceName, "ssh") ||
+		strings.HasPrefix(service.Banner, "SSH-") ||
+		(service.ServiceName == "" && bannerPorts[service.Port] == "ssh")
+	if !isSSH {
+		return nil
+	}
+
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return nil
+	}
+
+	return SSHTarget{Host: host, Port: service.Port, Service: &service}
+}
+
+// Scan negotiates with the SSH server and reports its algorithms, host keys and weaknesses
+func (s *SSHScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	sshTarget, ok := target.(SSHTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for ssh scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings: []models.Finding{},
+		Services: []models.Service{},
+	}
+
+	// Configure scan parameters
+	timeout := s.timeout
+	port := s.port
+	username := s.username
+	checkAuth := true
+
+	// Override with provided parameters if available
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["port"].(float64); ok && val > 0 {
+		port = int(val)
+	}
+	if val, ok := params["username"].(string); ok && val != "" {
+		username = val
+	}
+	if val, ok := params["auth_methods"].(bool); ok {
+		checkAuth = val
+	}
+
+	if sshTarget.Port != 0 {
+		port = sshTarget.Port
+	}
+	address := net.JoinHostPort(sshTarget.Host, strconv.Itoa(port))
+	dialTimeout := time.Duration(timeout) * time.Second
+
+	audit, err := s.negotiate(ctx, address, dialTimeout)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		// Nothing listening is expected when auditing the default port of a target
+		if sshTarget.Service == nil {
+			return scanResults, nil
+		}
+		return nil, fmt.Errorf("SSH negotiation with %s failed: %w", address, err)
+	}
+
+	if audit.kexInit != nil {
+		// One handshake per key type, since a server only presents the key of the negotiated algorithm
+		seen := make(map[string]bool)
+		for _, algorithm := range audit.kexInit.hostKey {
+			keyType, ok := sshHostKeyTypes[algorithm]
+			if !ok || seen[keyType] {
+				continue
+			}
+			seen[keyType] = true
+
+			key, err := s.fetchHostKey(ctx, address, algorithm, dialTimeout)
+			if err != nil {
+				continue
+			}
+			audit.hostKeys = append(audit.hostKeys, sshHostKey{
+				keyType: key.Type(),
+				bits:    sshKeyBits(key),
+				sha256:  ssh.FingerprintSHA256(key),
+				md5:     ssh.FingerprintLegacyMD5(key),
+			})
+		}
+
+		if checkAuth {
+			audit.authMethods = s.passwordAuthMethods(ctx, address, username, dialTimeout)
+		}
+	}
+
+	service := s.createService(sshTarget, port, audit)
+	scanResults.Services = append(scanResults.Services, service)
+	scanResults.Findings = append(scanResults.Findings, s.createFindings(sshTarget.Host, port, audit, service.ID)...)
+
+	return scanResults, nil
+}
+
+// dial opens a TCP connection that is closed when the scan is cancelled
+func (s *SSHScanner) dial(ctx context.Context, address string, timeout time.Duration) (net.Conn, func(), error) {
+	dialer := net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	conn.SetDeadline(time.Now().Add(timeout * 3))
+	return conn, func() {
+		stop()
+		conn.Close()
+	}, nil
+}
+
+// negotiate exchanges identification strings and reads the algorithms offered by the server
+func (s *SSHScanner) negotiate(ctx context.Context, address string, timeout time.Duration) (*sshAudit, error) {
+	conn, closeConn, err := s.dial(ctx, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	audit := &sshAudit{}
+	if addr, ok := conn.RemoteAddr().(*net.TCPAddr); ok {
+		audit.ipAddress = addr.IP.String()
+	}
+
+	// Both sides may send their identification right away
+	if _, err := conn.Write([]byte(sshClientIdent + "\r\n")); err != nil {
+		return nil, err
+	}
+
+	reader := bufio.NewReader(conn)
+	audit.ident, err = readSSHIdent(reader)
+	if err != nil {
+		return nil, err
+	}
+
+	// SSH-protoversion-softwareversion SP comments
+	parts := strings.SplitN(strings.SplitN(audit.ident, " ", 2)[0], "-", 3)
+	if len(parts) == 3 {
+		audit.protocolVersion = parts[1]
+		audit.software = parts[2]
+	}
+
+	// Servers speaking only SSH 1 don't send a KEXINIT
+	if strings.HasPrefix(audit.protocolVersion, "1.") && audit.protocolVersion != "1.99" {
+		return audit, nil
+	}
+
+	payload, err := readSSHPacket(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read KEXINIT: %w", err)
+	}
+	audit.kexInit, err = parseKexInit(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return audit, nil
+}
+
+// fetchHostKey completes a key exchange using a single host key algorithm and returns the key the server signed with
+func (s *SSHScanner) fetchHostKey(ctx context.Context, address, algorithm string, timeout time.Duration) (ssh.PublicKey, error) {
+	conn, closeConn, err := s.dial(ctx, address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer closeConn()
+
+	var hostKey ssh.PublicKey
+	config := &ssh.ClientConfig{
+		User:              "zecas",
+		ClientVersion:     sshClientIdent,
+		HostKeyAlgorithms: []string{algorithm},
+		HostKeyCallback: func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+			hostKey = key
+			return errSSHAuditDone
+		},
+		Timeout: timeout,
+	}
+	config.KeyExchanges = sshAuditKeyExchanges
+	config.Ciphers = sshAuditCiphers
+
+	_, _, _, err = ssh.NewClientConn(conn, address, config)
+	if hostKey != nil {
+		return hostKey, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no host key presented")
+	}
+	return nil, err
+}
+
+// passwordAuthMethods returns the authentication methods that accept a password. Each method is
+// offered to the server on its own connection and abandoned as soon as the server accepts to use it,
+// so no credentials are ever sent.
+func (s *SSHScanner) passwordAuthMethods(ctx context.Context, address, username string, timeout time.Duration) []string {
+	var methods []string
+
+	probes := []struct {
+		method string
+		auth   func(offered *bool) ssh.AuthMethod
+	}{
+		{"password", func(offered *bool) ssh.AuthMethod {
+			return ssh.PasswordCallback(func() (string, error) {
+				*offered = true
+				return "", errSSHAuditDone
+			})
+		}},
+		{"keyboard-interactive", func(offered *bool) ssh.AuthMethod {
+			return ssh.KeyboardInteractive(func(name, instruction string, questions []string, echos []bool) ([]string, error) {
+				*offered = true
+				return nil, errSSHAuditDone
+			})
+		}},
+	}
+
+	for _, probe := range probes {
+		conn, closeConn, err := s.dial(ctx, address, timeout)
+		if err != nil {
+			continue
+		}
+
+		offered := false
+		config := &ssh.ClientConfig{
+			User:            username,
+			ClientVersion:   sshClientIdent,
+			Auth:            []ssh.AuthMethod{probe.auth(&offered)},
+			HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+			Timeout:         timeout,
+		}
+		config.KeyExchanges = sshAuditKeyExchanges
+		config.Ciphers = sshAuditCiphers
+
+		if client, chans, reqs, err := ssh.NewClientConn(conn, address, config); err == nil {
+			// The server let us in without credentials
+			ssh.NewClient(client, chans, reqs).Close()
+			if !containsString(methods, "none") {
+				methods = append(methods, "none")
+			}
+		}
+		closeConn()
+
+		if offered {
+			methods = append(methods, probe.method)
+		}
+	}
+
+	return methods
+}
+
+// createService reports the audited server as a service with its algorithms and host keys
+func (s *SSHScanner) createService(target SSHTarget, port int, audit *sshAudit) models.Service {
+	product, version := identifyProduct(audit.ident)
+
+	sshInfo := models.JSONB{
+		"protocol_version": audit.protocolVersion,
+		"software":         audit.software,
+		"audited_at":       time.Now().Format(time.RFC3339),
+	}
+	if audit.kexInit != nil {
+		sshInfo["kex_algorithms"] = audit.kexInit.kex
+		sshInfo["host_key_algorithms"] = audit.kexInit.hostKey
+		sshInfo["encryption_algorithms_client_to_server"] = audit.kexInit.ciphersClientServer
+		sshInfo["encryption_algorithms_server_to_client"] = audit.kexInit.ciphersServerClient
+		sshInfo["mac_algorithms_client_to_server"] = audit.kexInit.macsClientServer
+		sshInfo["mac_algorithms_server_to_client"] = audit.kexInit.macsServerClient
+		sshInfo["compression_algorithms_client_to_server"] = audit.kexInit.compressionClientServer
+		sshInfo["compression_algorithms_server_to_client"] = audit.kexInit.compressionServerClient
+		sshInfo["password_auth_methods"] = audit.authMethods
+	}
+
+	hostKeys := make([]map[string]interface{}, 0, len(audit.hostKeys))
+	fingerprints := make([]string, 0, len(audit.hostKeys))
+	for _, key := range audit.hostKeys {
+		hostKeys = append(hostKeys, map[string]interface{}{
+			"type":               key.keyType,
+			"bits":               key.bits,
+			"fingerprint_sha256": key.sha256,
+			"fingerprint_md5":    key.md5,
+		})
+		fingerprints = append(fingerprints, key.sha256)
+	}
+	sshInfo["host_keys"] = hostKeys
+
+	rawInfo := models.JSONB{
+		"banner":                audit.ident,
+		"target_value":          target.Host,
+		"ssh":                   sshInfo,
+		"host_key_fingerprints": fingerprints,
+		"discovered_at":         time.Now().Format(time.RFC3339),
+	}
+	if audit.ipAddress != "" {
+		rawInfo["ip_address"] = audit.ipAddress
+	}
+	if product != "" {
+		rawInfo["product"] = product
+	}
+	if version != "" {
+		rawInfo["version"] = version
+	}
+
+	targetID := uuid.Nil // Will be set by worker
+	if target.Service != nil {
+		targetID = target.Service.TargetID
+	}
+
+	desc := fmt.Sprintf("SSH server on port %d identifying as %s", port, audit.ident)
+	if len(audit.hostKeys) > 0 {
+		desc += "\nHost keys:"
+		for _, key := range audit.hostKeys {
+			desc += fmt.Sprintf("\n  %s (%d bits) %s", key.keyType, key.bits, key.sha256)
+		}
+	}
+
+	return models.Service{
+		ID:          uuid.New(),
+		TargetID:    targetID,
+		Port:        port,
+		Protocol:    "tcp",
+		ServiceName: "ssh",
+		Version:     version,
+		Title:       fmt.Sprintf("ssh service on port %d", port),
+		Description: desc,
+		Banner:      truncateString(audit.ident, 1024),
+		RawInfo:     rawInfo,
+	}
+}
+
+// createFindings reports protocol version 1, weak algorithms and password authentication
+func (s *SSHScanner) createFindings(host string, port int, audit *sshAudit, serviceID uuid.UUID) []models.Finding {
+	var findings []models.Finding
+
+	newFinding := func(title, description, severity, findingType string, details models.JSONB) models.Finding {
+		id := serviceID
+		details["host"] = host
+		details["port"] = port
+		details["ident"] = audit.ident
+		details["discovered_at"] = time.Now().Format(time.RFC3339)
+		return models.Finding{
+			ID:          uuid.New(),
+			TargetID:    uuid.Nil, // Will be set by worker
+			ServiceID:   &id,
+			Title:       title,
+			Description: description,
+			Severity:    severity,
+			FindingType: findingType,
+			Fingerprint: net.JoinHostPort(host, strconv.Itoa(port)), // Keeps the ports of a host apart
+			Details:     details,
+		}
+	}
+
+	if strings.HasPrefix(audit.protocolVersion, "1.") {
+		findings = append(findings, newFinding(
+			fmt.Sprintf("SSH protocol version 1 supported on port %d", port),
+			fmt.Sprintf("%s:%d announces protocol version %s. SSH 1 has known design flaws that allow session hijacking and decryption.", host, port, audit.protocolVersion),
+			models.SeverityHigh,
+			"ssh_protocol_v1",
+			models.JSONB{"protocol_version": audit.protocolVersion},
+		))
+	}
+
+	if audit.kexInit == nil {
+		return findings
+	}
+
+	offered := map[string][]string{
+		"kex":      audit.kexInit.kex,
+		"host_key": audit.kexInit.hostKey,
+		"cipher":   mergeUnique(audit.kexInit.ciphersClientServer, audit.kexInit.ciphersServerClient),
+		"mac":      mergeUnique(audit.kexInit.macsClientServer, audit.kexInit.macsServerClient),
+	}
+
+	for _, category := range sshCategories {
+		var issues []sshIssue
+		for _, algorithm := range offered[category.category] {
+			if weakness, ok := sshWeakAlgorithms[category.category][algorithm]; ok {
+				issues = append(issues, sshIssue{algorithm, weakness.severity, weakness.reason})
+			}
+		}
+
+		// Short keys are weak whatever algorithm signs with them
+		if category.category == "host_key" {
+			for _, key := range audit.hostKeys {
+				if (key.keyType == ssh.KeyAlgoRSA || key.keyType == ssh.KeyAlgoDSA) && key.bits > 0 && key.bits < 2048 {
+					issues = append(issues, sshIssue{
+						algorithm: fmt.Sprintf("%s %d-bit key", key.keyType, key.bits),
+						severity:  models.SeverityMedium,
+						reason:    "Keys shorter than 2048 bits can be factored",
+					})
+				}
+			}
+		}
+
+		if len(issues) == 0 {
+			continue
+		}
+
+		severity := models.SeverityLow
+		lines := make([]string, 0, len(issues))
+		algorithms := make([]map[string]interface{}, 0, len(issues))
+		for _, issue := range issues {
+			if sshSeverityRank[issue.severity] > sshSeverityRank[severity] {
+				severity = issue.severity
+			}
+			lines = append(lines, fmt.Sprintf("- %s: %s", issue.algorithm, issue.reason))
+			algorithms = append(algorithms, map[string]interface{}{
+				"algorithm": issue.algorithm,
+				"severity":  issue.severity,
+				"reason":    issue.reason,
+			})
+		}
+
+		findings = append(findings, newFinding(
+			fmt.Sprintf("%s on port %d", category.title, port),
+			fmt.Sprintf("%s:%d offers weak or deprecated algorithms:\n%s", host, port, strings.Join(lines, "\n")),
+			severity,
+			category.findingType,
+			models.JSONB{"category": category.category, "algorithms": algorithms},
+		))
+	}
+
+	if len(audit.authMethods) > 0 {
+		severity := models.SeverityLow
+		description := fmt.Sprintf("%s:%d accepts password based authentication (%s), which exposes accounts to brute force attacks.",
+			host, port, strings.Join(audit.authMethods, ", "))
+		if containsString(audit.authMethods, "none") {
+			severity = models.SeverityCritical
+			description = fmt.Sprintf("%s:%d accepts logins without any credentials.", host, port)
+		}
+		findings = append(findings, newFinding(
+			fmt.Sprintf("SSH password authentication enabled on port %d", port),
+			description,
+			severity,
+			"ssh_password_authentication",
+			models.JSONB{"auth_methods": audit.authMethods},
+		))
+	}
+
+	return findings
+}
+
+// readSSHIdent reads the server's identification string, skipping any lines sent before it
+func readSSHIdent(reader *bufio.Reader) (string, error) {
+	for i := 0; i < 50; i++ {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if strings.HasPrefix(line, "SSH-") {
+			return line, nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no SSH identification string received")
+}
+
+// readSSHPacket reads an unencrypted binary packet and returns its payload
+func readSSHPacket(reader *bufio.Reader) ([]byte, error) {
+	header := make([]byte, 5)
+	if _, err := io.ReadFull(reader, header); err != nil {
+		return nil, err
+	}
+
+	length := binary.BigEndian.Uint32(header[:4])
+	padding := int(header[4])
+	if length < 5 || length > sshMaxPacketLength {
+		return nil, fmt.Errorf("invalid packet length %d", length)
+	}
+
+	body := make([]byte, length-1)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return nil, err
+	}
+
+	payloadLength := int(length) - padding - 1
+	if payloadLength < 1 {
+		return nil, fmt.Errorf("invalid padding length %d", padding)
+	}
+	return body[:payloadLength], nil
+}
+
+// parseKexInit parses the name-lists of an SSH_MSG_KEXINIT payload
+func parseKexInit(payload []byte) (*sshKexInit, error) {
+	const msgKexInit = 20
+	if len(payload) < 17 || payload[0] != msgKexInit {
+		return nil, fmt.Errorf("expected KEXINIT, got message type %d", payload[0])
+	}
+
+	// Skip the message type and the 16 byte cookie
+	offset := 17
+	lists := make([][]string, 10)
+	for i := range lists {
+		if offset+4 > len(payload) {
+			return nil, fmt.Errorf("truncated KEXINIT")
+		}
+		length := int(binary.BigEndian.Uint32(payload[offset : offset+4]))
+		offset += 4
+		if offset+length > len(payload) {
+			return nil, fmt.Errorf("truncated KEXINIT")
+		}
+		if length > 0 {
+			lists[i] = strings.Split(string(payload[offset:offset+length]), ",")
+		} else {
+			lists[i] = []string{}
+		}
+		offset += length
+	}
+
+	return &sshKexInit{
+		kex:                     lists[0],
+		hostKey:                 lists[1],
+		ciphersClientServer:     lists[2],
+		ciphersServerClient:     lists[3],
+		macsClientServer:        lists[4],
+		macsServerClient:        lists[5],
+		compressionClientServer: lists[6],
+		compressionServerClient: lists[7],
+	}, nil
+}
+
+// sshKeyBits returns the size of a public key in bits
+func sshKeyBits(key ssh.PublicKey) int {
+	cryptoKey, ok := key.(ssh.CryptoPublicKey)
+	if !ok {
+		return 0
+	}
+
+	switch k := cryptoKey.CryptoPublicKey().(type) {
+	case *rsa.PublicKey:
+		return k.N.BitLen()
+	case *ecdsa.PublicKey:
+		return k.Curve.Params().BitSize
+	case *dsa.PublicKey:
+		return k.P.BitLen()
+	case ed25519.PublicKey:
+		return 256
+	}
+	return 0
+}
+
+// mergeUnique returns the values of both lists without duplicates, keeping their order
+func mergeUnique(a, b []string) []string {
+	seen := make(map[string]bool)
+	var merged []string
+	for _, value := range append(append([]string{}, a...), b...) {
+		if !seen[value] {
+			seen[value] = true
+			merged = append(merged, value)
+		}
+	}
+	return merged
+}
+
+// Type returns the scanner type identifier
+func (s *SSHScanner) Type() string {
+	return "ssh"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *SSHScanner) SupportsTargetType(targetType string) bool {
+	return targetType == models.TargetTypeIP || targetType == models.TargetTypeDomain
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *SSHScanner) SupportsServices() bool {
+	return true
+}
+
+// ProbesServices reports that the scanner identifies the server from its SSH handshake
+func (s *SSHScanner) ProbesServices(target interface{}, params models.JSONB) bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *SSHScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"timeout":      integerParam("Connect and handshake timeout in seconds", 1, 0),
+		"port":         integerParam("Port audited on IP and domain targets, services use their own port", 1, 65535),
+		"username":     stringParam("Username used to ask the server which authentication methods it offers"),
+		"auth_methods": booleanParam("Check whether password and keyboard-interactive authentication are offered"),
+	})
+}
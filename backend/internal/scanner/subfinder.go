@@ -344,3 +344,13 @@ func (s *SubdomainScanner) SupportsTargetType(targetType string) bool {
 func (s *SubdomainScanner) SupportsServices() bool {
 	return false
 }
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *SubdomainScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"recursive":  booleanParam("Enumerate subdomains recursively"),
+		"resolve_ip": booleanParam("Resolve discovered subdomains, defaults to true"),
+		"wordlist":   stringParam("Wordlist used when subfinder is not available"),
+		"timeout":    integerParam("Resolver timeout in seconds", 1, 0),
+	})
+}
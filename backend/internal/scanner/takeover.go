@@ -0,0 +1,533 @@
+// internal/scanner/takeover.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"context"
+	"crypto/tls"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// defaultTakeoverFingerprints is the fingerprint database shipped with the scanner
+//
+//go:embed fingerprints/takeover.json
+var defaultTakeoverFingerprints []byte
+
+// maxCNAMEChain limits how many CNAMEs are followed when resolving a target
+const maxCNAMEChain = 10
+
+// defaultFingerprintsFile is the name of the fingerprint database used when a scan doesn't pick another one
+const defaultFingerprintsFile = "fingerprints.json"
+
+// fingerprintsMaxAge is how old the default fingerprint database may get before it is downloaded again
+const fingerprintsMaxAge = 24 * time.Hour
+
+// TakeoverScanner implements the Scanner interface for detecting subdomain takeovers through dangling CNAMEs
+type TakeoverScanner struct {
+	fingerprintsDir    string
+	fingerprintsURL    string
+	updateFingerprints bool
+	nameservers        []string
+	timeout            int
+	fingerprints       []TakeoverFingerprint
+}
+
+// TakeoverFingerprint describes how an unclaimed resource looks for a third-party service.
+// The format is compatible with the can-i-take-over-xyz fingerprints.json file.
+type TakeoverFingerprint struct {
+	Service       string   `json:"service"`
+	CNAME         []string `json:"cname"`
+	Fingerprint   string   `json:"fingerprint"`
+	HTTPStatus    *int     `json:"http_status"`
+	NXDomain      bool     `json:"nxdomain"`
+	Status        string   `json:"status"`
+	Vulnerable    bool     `json:"vulnerable"`
+	Documentation string   `json:"documentation"`
+	Discussion    string   `json:"discussion"`
+}
+
+// takeoverHTTPResponse holds the parts of an HTTP response used for fingerprint matching
+type takeoverHTTPResponse struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+// NewTakeoverScanner creates a new subdomain takeover scanner. Where fingerprint databases are kept and
+// whether the default one is kept up to date is worker configuration, scans can only pick a database by name.
+func NewTakeoverScanner() *TakeoverScanner {
+	fingerprintsDir := os.Getenv("TAKEOVER_FINGERPRINTS_DIR")
+	if fingerprintsDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err == nil {
+			fingerprintsDir = filepath.Join(homeDir, ".zecas", "takeover")
+		} else {
+			fingerprintsDir = "/opt/zecas/takeover" // Fallback
+		}
+	}
+
+	fingerprintsURL := os.Getenv("TAKEOVER_FINGERPRINTS_URL")
+	if fingerprintsURL == "" {
+		fingerprintsURL = "https://raw.githubusercontent.com/EdOverflow/can-i-take-over-xyz/master/fingerprints.json"
+	}
+
+	updateFingerprints, _ := strconv.ParseBool(os.Getenv("TAKEOVER_UPDATE_FINGERPRINTS"))
+
+	return &TakeoverScanner{
+		fingerprintsDir:    fingerprintsDir,
+		fingerprintsURL:    fingerprintsURL,
+		updateFingerprints: updateFingerprints,
+		nameservers:        systemNameservers(),
+		timeout:            10, // Default timeout in seconds
+	}
+}
+
+// Initialize loads the default fingerprint database, installing the bundled copy if none exists yet
+// and downloading a fresh one when updates are enabled and the local copy is outdated
+func (s *TakeoverScanner) Initialize(ctx context.Context) error {
+	fingerprintsPath := filepath.Join(s.fingerprintsDir, defaultFingerprintsFile)
+
+	info, err := os.Stat(fingerprintsPath)
+	if os.IsNotExist(err) {
+		log.Printf("Takeover fingerprints not found at %s, installing bundled database", fingerprintsPath)
+		if err := os.MkdirAll(s.fingerprintsDir, 0755); err == nil {
+			if err := os.WriteFile(fingerprintsPath, defaultTakeoverFingerprints, 0644); err != nil {
+				log.Printf("Failed to install takeover fingerprints: %v", err)
+			}
+		}
+	}
+
+	if s.updateFingerprints && (info == nil || time.Since(info.ModTime()) > fingerprintsMaxAge) {
+		if _, err := s.downloadFingerprints(ctx, s.fingerprintsURL, fingerprintsPath, time.Duration(s.timeout)*time.Second); err != nil {
+			log.Printf("Failed to update takeover fingerprints: %v", err)
+		}
+	}
+
+	fingerprints, err := s.loadFingerprints(fingerprintsPath)
+	if err != nil {
+		// Fall back to the bundled database so the scanner stays usable
+		log.Printf("Failed to load takeover fingerprints from %s, using bundled database: %v", fingerprintsPath, err)
+		if err := json.Unmarshal(defaultTakeoverFingerprints, &fingerprints); err != nil {
+			return fmt.Errorf("failed to parse bundled takeover fingerprints: %w", err)
+		}
+	}
+
+	s.fingerprints = fingerprints
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for takeover checks
+func (s *TakeoverScanner) ConvertTarget(target models.Target) interface{} {
+	if target.TargetType == models.TargetTypeDomain {
+		return target.Value
+	}
+	return nil
+}
+
+// ConvertService returns nil since the takeover scanner doesn't scan services
+func (s *TakeoverScanner) ConvertService(service models.Service) interface{} {
+	return nil
+}
+
+// Scan resolves the CNAME chain of the target and checks it against the fingerprint database
+func (s *TakeoverScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	if target == nil {
+		return nil, fmt.Errorf("invalid target for takeover scanner")
+	}
+
+	domain := strings.TrimSuffix(target.(string), ".")
+	scanResults := &models.ScanResults{
+		Findings:        []models.Finding{},
+		NewTargets:      []models.Target{},
+		TargetRelations: []models.TargetRelation{},
+		Services:        []models.Service{},
+	}
+
+	// Override with provided parameters if available
+	timeout := s.timeout
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+
+	checkHTTP := true
+	if val, ok := params["check_http"].(bool); ok {
+		checkHTTP = val
+	}
+
+	nameservers := s.nameservers
+	if servers, ok := params["nameservers"].([]interface{}); ok && len(servers) > 0 {
+		nameservers = []string{}
+		for _, server := range servers {
+			if serverStr, ok := server.(string); ok {
+				nameservers = append(nameservers, serverStr)
+			}
+		}
+	}
+
+	// Use another fingerprint database from the fingerprint directory of the worker
+	fingerprints := s.fingerprints
+	if val, ok := params["fingerprints_file"].(string); ok && val != "" {
+		fingerprintsPath, err := s.fingerprintsFile(val)
+		if err != nil {
+			return nil, err
+		}
+		loaded, err := s.loadFingerprints(fingerprintsPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load takeover fingerprints: %w", err)
+		}
+		fingerprints = loaded
+	}
+
+	if len(fingerprints) == 0 {
+		if err := json.Unmarshal(defaultTakeoverFingerprints, &fingerprints); err != nil {
+			return nil, fmt.Errorf("failed to parse bundled takeover fingerprints: %w", err)
+		}
+	}
+
+	client := newDNSClient(nameservers, time.Duration(timeout)*time.Second)
+
+	chain, finalRCode, err := s.resolveCNAMEChain(ctx, client, domain)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", domain, err)
+	}
+
+	// Without a CNAME there is no third-party resource that could be claimed
+	if len(chain) == 0 {
+		return scanResults, nil
+	}
+
+	dangling := finalRCode == dnsmessage.RCodeNameError
+
+	var responses []takeoverHTTPResponse
+	httpChecked := false
+
+	matched := false
+	for _, fingerprint := range fingerprints {
+		cnameMatch := s.matchCNAME(fingerprint, chain)
+		if cnameMatch == "" {
+			continue
+		}
+
+		details := models.JSONB{
+			"domain":        domain,
+			"cname_chain":   chain,
+			"matched_cname": cnameMatch,
+			"service":       fingerprint.Service,
+			"status":        fingerprint.Status,
+			"nxdomain":      dangling,
+			"documentation": fingerprint.Documentation,
+			"discussion":    fingerprint.Discussion,
+		}
+
+		evidence := ""
+		if fingerprint.NXDomain {
+			if dangling {
+				evidence = "nxdomain"
+			}
+		} else if fingerprint.Fingerprint != "" && checkHTTP {
+			if !httpChecked {
+				responses = s.fetchHTTP(ctx, domain, time.Duration(timeout)*time.Second)
+				httpChecked = true
+			}
+
+			for _, response := range responses {
+				if !strings.Contains(response.Body, fingerprint.Fingerprint) {
+					continue
+				}
+				if fingerprint.HTTPStatus != nil && *fingerprint.HTTPStatus != response.StatusCode {
+					continue
+				}
+
+				evidence = "http_fingerprint"
+				details["fingerprint"] = fingerprint.Fingerprint
+				details["url"] = response.URL
+				details["http_status"] = response.StatusCode
+				details["response_snippet"] = s.extractSnippet(response.Body, fingerprint.Fingerprint)
+				break
+			}
+		}
+
+		if evidence == "" {
+			continue
+		}
+
+		matched = true
+		details["evidence"] = evidence
+
+		finding := models.Finding{
+			Title:       fmt.Sprintf("Possible subdomain takeover of %s (%s)", domain, fingerprint.Service),
+			Description: s.generateTakeoverDescription(domain, chain, fingerprint, evidence),
+			Severity:    s.determineSeverityForFingerprint(fingerprint),
+			FindingType: "subdomain_takeover",
+			Details:     details,
+		}
+		scanResults.Findings = append(scanResults.Findings, finding)
+	}
+
+	// A CNAME pointing at a name that does not exist may be registrable even for unknown services
+	if !matched && dangling {
+		finding := models.Finding{
+			Title:       fmt.Sprintf("Dangling CNAME for %s", domain),
+			Description: fmt.Sprintf("%s is an alias for %s, which does not exist (NXDOMAIN). If the target domain or resource can be registered by someone else, they can serve content for %s.", domain, chain[len(chain)-1], domain),
+			Severity:    models.SeverityHigh,
+			FindingType: "dangling_cname",
+			Details: models.JSONB{
+				"domain":      domain,
+				"cname_chain": chain,
+				"nxdomain":    true,
+				"evidence":    "nxdomain",
+			},
+		}
+		scanResults.Findings = append(scanResults.Findings, finding)
+	}
+
+	return scanResults, nil
+}
+
+// resolveCNAMEChain follows the CNAMEs of a domain and returns the chain together with the final response code
+func (s *TakeoverScanner) resolveCNAMEChain(ctx context.Context, client *dnsClient, domain string) ([]string, dnsmessage.RCode, error) {
+	var chain []string
+	seen := map[string]bool{domain: true}
+	current := domain
+
+	for i := 0; i < maxCNAMEChain; i++ {
+		response, err := client.Query(ctx, current, dnsmessage.TypeCNAME)
+		if err != nil {
+			return chain, 0, err
+		}
+
+		next := ""
+		for _, answer := range response.Answers {
+			if cname, ok := answer.Body.(*dnsmessage.CNAMEResource); ok && strings.EqualFold(trimDNSName(answer.Header.Name), current) {
+				next = strings.ToLower(trimDNSName(cname.CNAME))
+				break
+			}
+		}
+
+		if next == "" || seen[next] {
+			break
+		}
+
+		seen[next] = true
+		chain = append(chain, next)
+		current = next
+	}
+
+	if len(chain) == 0 {
+		return chain, dnsmessage.RCodeSuccess, nil
+	}
+
+	// Check whether the end of the chain actually resolves
+	response, err := client.Query(ctx, current, dnsmessage.TypeA)
+	if err != nil {
+		return chain, 0, err
+	}
+
+	return chain, response.RCode, nil
+}
+
+// matchCNAME returns the first CNAME in the chain that belongs to the fingerprinted service
+func (s *TakeoverScanner) matchCNAME(fingerprint TakeoverFingerprint, chain []string) string {
+	for _, host := range chain {
+		for _, pattern := range fingerprint.CNAME {
+			pattern = strings.ToLower(strings.Trim(pattern, "."))
+			if pattern == "" {
+				continue
+			}
+			if host == pattern || strings.HasSuffix(host, "."+pattern) {
+				return host
+			}
+		}
+	}
+	return ""
+}
+
+// fetchHTTP requests the domain over HTTP and HTTPS and returns the responses
+func (s *TakeoverScanner) fetchHTTP(ctx context.Context, domain string, timeout time.Duration) []takeoverHTTPResponse {
+	httpClient := &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			// Unclaimed resources frequently serve certificates for the provider's domain
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	var responses []takeoverHTTPResponse
+	for _, scheme := range []string{"https", "http"} {
+		url := fmt.Sprintf("%s://%s/", scheme, domain)
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		if err != nil {
+			continue
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			continue
+		}
+
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+		resp.Body.Close()
+
+		responses = append(responses, takeoverHTTPResponse{
+			URL:        url,
+			StatusCode: resp.StatusCode,
+			Body:       string(body),
+		})
+	}
+
+	return responses
+}
+
+// fingerprintsFile returns the path of a fingerprint database in the fingerprint directory, refusing
+// names that would point outside of it
+func (s *TakeoverScanner) fingerprintsFile(name string) (string, error) {
+	if name != filepath.Base(name) || name == "." || name == ".." {
+		return "", fmt.Errorf("invalid fingerprints_file %q, expected the name of a file in %s", name, s.fingerprintsDir)
+	}
+	return filepath.Join(s.fingerprintsDir, name), nil
+}
+
+// loadFingerprints reads a fingerprint database from disk
+func (s *TakeoverScanner) loadFingerprints(path string) ([]TakeoverFingerprint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var fingerprints []TakeoverFingerprint
+	if err := json.Unmarshal(data, &fingerprints); err != nil {
+		return nil, fmt.Errorf("invalid fingerprint database: %w", err)
+	}
+
+	return fingerprints, nil
+}
+
+// downloadFingerprints downloads a fingerprint database and stores it at path
+func (s *TakeoverScanner) downloadFingerprints(ctx context.Context, url string, path string, timeout time.Duration) ([]TakeoverFingerprint, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := (&http.Client{Timeout: timeout}).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fingerprint download returned status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20))
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate before replacing the local copy
+	var fingerprints []TakeoverFingerprint
+	if err := json.Unmarshal(data, &fingerprints); err != nil {
+		return nil, fmt.Errorf("invalid fingerprint database: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+
+	log.Printf("Updated takeover fingerprints from %s (%d services)", url, len(fingerprints))
+	return fingerprints, nil
+}
+
+// extractSnippet returns the part of the body surrounding the matched fingerprint
+func (s *TakeoverScanner) extractSnippet(body string, fingerprint string) string {
+	index := strings.Index(body, fingerprint)
+	if index < 0 {
+		return ""
+	}
+
+	start := index - 100
+	if start < 0 {
+		start = 0
+	}
+	end := index + len(fingerprint) + 100
+	if end > len(body) {
+		end = len(body)
+	}
+
+	return body[start:end]
+}
+
+// generateTakeoverDescription creates a human-readable description of a takeover finding
+func (s *TakeoverScanner) generateTakeoverDescription(domain string, chain []string, fingerprint TakeoverFingerprint, evidence string) string {
+	desc := fmt.Sprintf("%s points to %s through the following CNAME chain:", domain, fingerprint.Service)
+	for _, host := range chain {
+		desc += fmt.Sprintf("\n• %s", host)
+	}
+
+	switch evidence {
+	case "nxdomain":
+		desc += "\n\nThe end of the chain does not resolve (NXDOMAIN), so the resource appears to be deleted."
+	case "http_fingerprint":
+		desc += fmt.Sprintf("\n\nThe HTTP response contains the %s fingerprint for an unclaimed resource: %q", fingerprint.Service, fingerprint.Fingerprint)
+	}
+
+	if fingerprint.Vulnerable {
+		desc += fmt.Sprintf("\n\nAn attacker can claim the resource on %s and serve arbitrary content on %s. Remove the DNS record or reclaim the resource.", fingerprint.Service, domain)
+	} else {
+		desc += fmt.Sprintf("\n\nTakeovers on %s are only possible in some cases (%s) and need to be verified manually.", fingerprint.Service, fingerprint.Status)
+	}
+
+	if fingerprint.Documentation != "" {
+		desc += fmt.Sprintf("\n\nDocumentation: %s", fingerprint.Documentation)
+	}
+
+	return desc
+}
+
+// determineSeverityForFingerprint sets severity based on whether the service is known to be vulnerable
+func (s *TakeoverScanner) determineSeverityForFingerprint(fingerprint TakeoverFingerprint) string {
+	if fingerprint.Vulnerable {
+		return models.SeverityCritical
+	}
+	return models.SeverityMedium
+}
+
+// Type returns the scanner type identifier
+func (s *TakeoverScanner) Type() string {
+	return "takeover"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *TakeoverScanner) SupportsTargetType(targetType string) bool {
+	return targetType == models.TargetTypeDomain
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *TakeoverScanner) SupportsServices() bool {
+	return false
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *TakeoverScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"timeout":           integerParam("DNS and HTTP timeout in seconds", 1, 0),
+		"check_http":        booleanParam("Match HTTP response fingerprints, defaults to true"),
+		"nameservers":       stringListParam("Nameservers to query instead of the system resolvers"),
+		"fingerprints_file": stringParam("Name of a fingerprint database in the fingerprint directory of the worker"),
+	})
+}
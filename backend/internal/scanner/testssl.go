@@ -9,25 +9,53 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"net"
 	"time"
 
 	"os"
 	"os/exec"
+	"strconv"
 	"strings"
+
+	"github.com/google/uuid"
 )
 
 type TestSSLScanner struct {
 	resolverTimeout int
 }
 
+// TestSSLTarget is the host and optional port passed to testssl.sh
+type TestSSLTarget struct {
+	Host    string
+	Port    int
+	Service bool
+}
+
 type TestSSLOutput struct {
-	Id string `json:"id"`
-	Ip string `json:"ip"`
-	//port string `json:"port"`
-	//severity *string `json:"severity"`
-	//cve      *string `json:"cve"`
-	//cwe      *string `json:"cwe"`
-	Finding string `json:"finding"`
+	Id       string `json:"id"`
+	Ip       string `json:"ip"`
+	Port     string `json:"port"`
+	Severity string `json:"severity"`
+	Cve      string `json:"cve,omitempty"`
+	Cwe      string `json:"cwe,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+	Finding  string `json:"finding"`
+}
+
+// testSSLSeverities maps testssl.sh severities to Zecas severities, ordered by rank
+var testSSLSeverities = map[string]string{
+	"LOW":      models.SeverityLow,
+	"MEDIUM":   models.SeverityMedium,
+	"HIGH":     models.SeverityHigh,
+	"CRITICAL": models.SeverityCritical,
+}
+
+// testSSLSeverityRank orders testssl.sh severities for filtering
+var testSSLSeverityRank = map[string]int{
+	"LOW":      1,
+	"MEDIUM":   2,
+	"HIGH":     3,
+	"CRITICAL": 4,
 }
 
 type TestSSLDetails struct {
@@ -56,17 +84,30 @@ func (s *TestSSLScanner) Initialize(ctx context.Context) error {
 func (s *TestSSLScanner) ConvertTarget(target models.Target) interface{} {
 	switch target.TargetType {
 	case models.TargetTypeDomain:
-		return target.Value
+		return TestSSLTarget{Host: target.Value}
 	case models.TargetTypeIP:
-		return target.Value
+		return TestSSLTarget{Host: target.Value}
 	default:
 		return nil
 	}
 }
 
+// ConvertService converts a Service to a TestSSL target for its port
 func (s *TestSSLScanner) ConvertService(service models.Service) interface{} {
-	// TODO: Implement this...
-	return nil
+	if service.Protocol != "" && service.Protocol != "tcp" {
+		return nil
+	}
+
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return nil
+	}
+
+	return TestSSLTarget{
+		Host:    host,
+		Port:    service.Port,
+		Service: true,
+	}
 }
 
 func generateRandomID(length int) (string, error) {
@@ -80,18 +121,40 @@ func generateRandomID(length int) (string, error) {
 
 // Scan performs TestSSL resolution against the target
 func (s *TestSSLScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
-	if target == nil {
+	sslTarget, ok := target.(TestSSLTarget)
+	if !ok {
 		return nil, fmt.Errorf("invalid target for TestSSL resolver")
 	}
 
-	targetValue := target.(string)
+	targetValue := sslTarget.Host
+	if sslTarget.Port > 0 {
+		targetValue = net.JoinHostPort(sslTarget.Host, strconv.Itoa(sslTarget.Port))
+	}
+
 	scanResults := &models.ScanResults{
 		Certificates: []models.Certificate{},
 		Findings:     []models.Finding{},
+		Services:     []models.Service{},
+	}
+
+	// Only report entries at or above this testssl.sh severity
+	minSeverity := "LOW"
+	if val, ok := params["severity"].(string); ok {
+		if _, known := testSSLSeverityRank[strings.ToUpper(val)]; known {
+			minSeverity = strings.ToUpper(val)
+		}
+	}
+
+	// Bound the testssl.sh run if a timeout is given
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(val)*time.Second)
+		defer cancel()
 	}
 
 	var certificates []models.Certificate
 	var findings []models.Finding
+	var services []models.Service
 
 	randomBytes, err := generateRandomID(16)
 	if err != nil {
@@ -126,9 +189,23 @@ func (s *TestSSLScanner) Scan(ctx context.Context, target interface{}, params mo
 		var outputJson []TestSSLOutput
 		err = json.Unmarshal(byteValue, &outputJson)
 		if err == nil {
+			// For target scans, create the TLS services the results are linked to
+			var serviceIDs map[int]uuid.UUID
+			if !sslTarget.Service {
+				services, serviceIDs = s.createServices(outputJson, sslTarget.Host)
+			}
+
 			var certificate models.Certificate
 			layout := "2006-01-02 15:04"
 			for _, f := range outputJson {
+				if strings.HasPrefix(f.Id, "cert_") {
+					if port, err := strconv.Atoi(f.Port); err == nil {
+						if serviceID, ok := serviceIDs[port]; ok {
+							certificate.ServiceID = serviceID
+						}
+					}
+				}
+
 				switch f.Id {
 				case "cert_subjectAltName":
 					certificate.Domain = f.Finding
@@ -169,6 +246,8 @@ func (s *TestSSLScanner) Scan(ctx context.Context, target interface{}, params mo
 				certificate.Details = scanResults
 			}
 			certificates = append(certificates, certificate)
+
+			findings = s.createFindings(outputJson, sslTarget.Host, minSeverity, serviceIDs)
 		}
 	}
 
@@ -179,12 +258,125 @@ func (s *TestSSLScanner) Scan(ctx context.Context, target interface{}, params mo
 
 	scanResults.Findings = findings
 	scanResults.Certificates = certificates
+	scanResults.Services = services
 	return scanResults, nil
 }
 
+// createServices creates a service for every port testssl.sh reported results for
+func (s *TestSSLScanner) createServices(entries []TestSSLOutput, host string) ([]models.Service, map[int]uuid.UUID) {
+	var services []models.Service
+	serviceIDs := make(map[int]uuid.UUID)
+
+	for _, entry := range entries {
+		port, err := strconv.Atoi(entry.Port)
+		if err != nil || port <= 0 {
+			continue
+		}
+		if _, exists := serviceIDs[port]; exists {
+			continue
+		}
+
+		serviceName, known := wellKnownPorts[port]
+		if !known {
+			serviceName = "ssl"
+		}
+
+		service := models.Service{
+			ID:          uuid.New(),
+			TargetID:    uuid.Nil, // Will be set by worker
+			Port:        port,
+			Protocol:    "tcp",
+			ServiceName: serviceName,
+			Title:       fmt.Sprintf("TLS service on port %d", port),
+			RawInfo: models.JSONB{
+				"target_value":  host,
+				"discovered_at": time.Now().Format(time.RFC3339),
+			},
+		}
+		services = append(services, service)
+		serviceIDs[port] = service.ID
+	}
+
+	return services, serviceIDs
+}
+
+// createFindings turns testssl.sh entries with a severity of at least minSeverity into findings
+func (s *TestSSLScanner) createFindings(entries []TestSSLOutput, targetHost string, minSeverity string, serviceIDs map[int]uuid.UUID) []models.Finding {
+	var findings []models.Finding
+
+	for _, entry := range entries {
+		severity, ok := testSSLSeverities[strings.ToUpper(entry.Severity)]
+		if !ok || testSSLSeverityRank[strings.ToUpper(entry.Severity)] < testSSLSeverityRank[minSeverity] {
+			continue
+		}
+
+		port, _ := strconv.Atoi(entry.Port)
+		host, ip := targetHost, entry.Ip
+		if name, addr, found := strings.Cut(entry.Ip, "/"); found {
+			host, ip = name, addr
+		}
+
+		details := models.JSONB{
+			"testssl_id":       entry.Id,
+			"testssl_severity": entry.Severity,
+			"finding":          entry.Finding,
+			"host":             host,
+			"ip":               ip,
+			"port":             port,
+		}
+		if entry.Hint != "" {
+			details["hint"] = entry.Hint
+		}
+
+		finding := models.Finding{
+			Title:       fmt.Sprintf("%s on %s:%d", s.describeID(entry.Id), host, port),
+			Description: s.generateFindingDescription(entry),
+			Severity:    severity,
+			FindingType: "testssl_" + strings.ToLower(strings.ReplaceAll(entry.Id, "-", "_")),
+			Fingerprint: net.JoinHostPort(host, strconv.Itoa(port)),
+			Details:     details,
+			CVEIDs:      normalizeCVEIDs(strings.Fields(entry.Cve)),
+			CWEIDs:      normalizeCWEIDs(strings.Fields(entry.Cwe)),
+		}
+
+		// Link the finding to the TLS service on the reported port
+		if serviceID, ok := serviceIDs[port]; ok {
+			finding.ServiceID = &serviceID
+		}
+
+		findings = append(findings, finding)
+	}
+
+	return findings
+}
+
+// describeID turns a testssl.sh check identifier into a readable title
+func (s *TestSSLScanner) describeID(id string) string {
+	switch {
+	case strings.HasPrefix(id, "cipherlist_"):
+		return fmt.Sprintf("Weak cipher suites (%s)", strings.TrimPrefix(id, "cipherlist_"))
+	case strings.HasPrefix(id, "cert_"):
+		return fmt.Sprintf("Certificate issue (%s)", strings.TrimPrefix(id, "cert_"))
+	case id == "HSTS":
+		return "HSTS issue"
+	default:
+		return fmt.Sprintf("TLS issue %s", id)
+	}
+}
+
+// generateFindingDescription creates a human-readable description of a testssl.sh entry
+func (s *TestSSLScanner) generateFindingDescription(entry TestSSLOutput) string {
+	desc := fmt.Sprintf("testssl.sh reported %s for check %s: %s", entry.Severity, entry.Id, entry.Finding)
+	if entry.Hint != "" {
+		desc += fmt.Sprintf("\n\n%s", entry.Hint)
+	}
+
+	return desc
+}
+
 // Type returns the scanner type identifier
 func (s *TestSSLScanner) Type() string {
-	return "dns"
+	return "testSSL"
 }
 
 // SupportsTargetType indicates whether this scanner can handle the specified target type
@@ -199,5 +391,13 @@ func (s *TestSSLScanner) SupportsTargetType(targetType string) bool {
 
 // SupportsServices indicates whether this scanner can scan services
 func (s *TestSSLScanner) SupportsServices() bool {
-	return false
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *TestSSLScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"severity": enumParam("Minimum testssl.sh severity reported as a finding, defaults to LOW", "LOW", "MEDIUM", "HIGH", "CRITICAL"),
+		"timeout":  integerParam("Timeout of a testssl.sh run in seconds", 1, 0),
+	})
 }
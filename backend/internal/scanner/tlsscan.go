@@ -0,0 +1,696 @@
+// internal/scanner/tlsscan.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TLSScanner implements the Scanner interface for inspecting TLS configurations and certificates natively
+type TLSScanner struct {
+	timeout           int
+	expiryWarningDays int
+}
+
+// TLSTarget is the host and ports the TLS scanner connects to
+type TLSTarget struct {
+	Host    string
+	Ports   []int
+	Service bool
+}
+
+// tlsVersions lists the protocol versions that are tested, oldest first
+var tlsVersions = []uint16{
+	tls.VersionTLS10,
+	tls.VersionTLS11,
+	tls.VersionTLS12,
+	tls.VersionTLS13,
+}
+
+// tlsPortResult holds everything learned about a single TLS endpoint
+type tlsPortResult struct {
+	port       int
+	chain      []*x509.Certificate
+	versions   []string
+	ciphers    map[string][]string
+	weakCipher []string
+	negotiated tls.ConnectionState
+}
+
+// NewTLSScanner creates a new native TLS scanner
+func NewTLSScanner() *TLSScanner {
+	return &TLSScanner{
+		timeout:           5,  // Default timeout in seconds
+		expiryWarningDays: 30, // Warn about certificates expiring within this many days
+	}
+}
+
+// Initialize has nothing to set up since the scanner only relies on the standard library
+func (s *TLSScanner) Initialize(ctx context.Context) error {
+	return nil
+}
+
+// ConvertTarget converts a Target to a format suitable for TLS scanning
+func (s *TLSScanner) ConvertTarget(target models.Target) interface{} {
+	switch target.TargetType {
+	case models.TargetTypeDomain, models.TargetTypeIP:
+		return TLSTarget{Host: target.Value}
+	default:
+		return nil
+	}
+}
+
+// ConvertService converts a Service to a TLS target for its port
+func (s *TLSScanner) ConvertService(service models.Service) interface{} {
+	if service.Protocol != "" && service.Protocol != "tcp" {
+		return nil
+	}
+
+	host, ok := service.RawInfo["target_value"].(string)
+	if !ok || host == "" {
+		return nil
+	}
+
+	return TLSTarget{
+		Host:    host,
+		Ports:   []int{service.Port},
+		Service: true,
+	}
+}
+
+// Scan connects to each port of the target and inspects the TLS protocols, cipher suites and certificate chain
+func (s *TLSScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	tlsTarget, ok := target.(TLSTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for TLS scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings:        []models.Finding{},
+		NewTargets:      []models.Target{},
+		TargetRelations: []models.TargetRelation{},
+		Services:        []models.Service{},
+		Certificates:    []models.Certificate{},
+	}
+
+	// Default scan options
+	portList := "443"
+	timeout := s.timeout
+	enumerateCiphers := true
+	expiryWarningDays := s.expiryWarningDays
+	serverName := ""
+
+	// Override with provided parameters if available
+	if val, ok := params["ports"].(string); ok && val != "" {
+		portList = val
+	}
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["enumerate_ciphers"].(bool); ok {
+		enumerateCiphers = val
+	}
+	if val, ok := params["expiry_warning_days"].(float64); ok && val >= 0 {
+		expiryWarningDays = int(val)
+	}
+	if val, ok := params["server_name"].(string); ok && val != "" {
+		serverName = val
+	}
+
+	// Only send SNI for hostnames
+	if serverName == "" && net.ParseIP(tlsTarget.Host) == nil {
+		serverName = tlsTarget.Host
+	}
+
+	ports := tlsTarget.Ports
+	if len(ports) == 0 {
+		parsed, err := parsePortRange(portList)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ports: %w", err)
+		}
+		ports = parsed
+	}
+
+	dialTimeout := time.Duration(timeout) * time.Second
+
+	for _, port := range ports {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+
+		address := net.JoinHostPort(tlsTarget.Host, strconv.Itoa(port))
+		result, err := s.scanPort(ctx, address, serverName, dialTimeout, enumerateCiphers)
+		if err != nil {
+			// Only report failures for services that were explicitly scanned
+			if tlsTarget.Service {
+				finding := models.Finding{
+					Title:       fmt.Sprintf("No TLS on %s", address),
+					Description: fmt.Sprintf("A TLS handshake with %s could not be completed: %v", address, err),
+					Severity:    models.SeverityInfo,
+					FindingType: "tls_unavailable",
+					Fingerprint: address,
+					Details: models.JSONB{
+						"host":  tlsTarget.Host,
+						"port":  port,
+						"error": err.Error(),
+					},
+				}
+				scanResults.Findings = append(scanResults.Findings, finding)
+			}
+			continue
+		}
+		result.port = port
+
+		// When scanning a target, create the TLS service so certificates and findings can link to it
+		var serviceID *uuid.UUID
+		if !tlsTarget.Service {
+			service := s.createService(tlsTarget.Host, result)
+			scanResults.Services = append(scanResults.Services, service)
+			serviceID = &service.ID
+		}
+
+		certificate := s.createCertificate(tlsTarget.Host, result)
+		if serviceID != nil {
+			certificate.ServiceID = *serviceID
+		}
+		scanResults.Certificates = append(scanResults.Certificates, certificate)
+
+		findings := s.evaluate(tlsTarget.Host, serverName, result, expiryWarningDays)
+		for i := range findings {
+			findings[i].ServiceID = serviceID
+		}
+		scanResults.Findings = append(scanResults.Findings, findings...)
+	}
+
+	return scanResults, nil
+}
+
+// scanPort performs the handshakes needed to inspect a single TLS endpoint
+func (s *TLSScanner) scanPort(ctx context.Context, address string, serverName string, timeout time.Duration, enumerateCiphers bool) (*tlsPortResult, error) {
+	// First handshake with the default configuration to get the certificate chain
+	state, err := s.handshake(ctx, address, &tls.Config{
+		ServerName:         serverName,
+		InsecureSkipVerify: true,
+		MinVersion:         tls.VersionTLS10,
+	}, timeout)
+	if err != nil {
+		return nil, err
+	}
+	if len(state.PeerCertificates) == 0 {
+		return nil, fmt.Errorf("no certificate presented")
+	}
+
+	result := &tlsPortResult{
+		chain:      state.PeerCertificates,
+		ciphers:    map[string][]string{},
+		negotiated: *state,
+	}
+
+	insecure := map[uint16]bool{}
+	for _, suite := range tls.InsecureCipherSuites() {
+		insecure[suite.ID] = true
+	}
+
+	weak := map[string]bool{}
+	for _, version := range tlsVersions {
+		versionName := tls.VersionName(version)
+
+		versionState, err := s.handshake(ctx, address, &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+			MinVersion:         version,
+			MaxVersion:         version,
+		}, timeout)
+		if err != nil {
+			continue
+		}
+		result.versions = append(result.versions, versionName)
+
+		// TLS 1.3 cipher suites cannot be restricted, so only record the negotiated one
+		if version == tls.VersionTLS13 || !enumerateCiphers {
+			result.ciphers[versionName] = []string{tls.CipherSuiteName(versionState.CipherSuite)}
+			if insecure[versionState.CipherSuite] {
+				weak[tls.CipherSuiteName(versionState.CipherSuite)] = true
+			}
+			continue
+		}
+
+		for _, suite := range append(tls.CipherSuites(), tls.InsecureCipherSuites()...) {
+			if !supportsVersion(suite, version) {
+				continue
+			}
+
+			_, err := s.handshake(ctx, address, &tls.Config{
+				ServerName:         serverName,
+				InsecureSkipVerify: true,
+				MinVersion:         version,
+				MaxVersion:         version,
+				CipherSuites:       []uint16{suite.ID},
+			}, timeout)
+			if err != nil {
+				continue
+			}
+
+			result.ciphers[versionName] = append(result.ciphers[versionName], suite.Name)
+			if suite.Insecure {
+				weak[suite.Name] = true
+			}
+		}
+	}
+
+	for name := range weak {
+		result.weakCipher = append(result.weakCipher, name)
+	}
+
+	return result, nil
+}
+
+// handshake dials the address and completes a TLS handshake with the given configuration
+func (s *TLSScanner) handshake(ctx context.Context, address string, config *tls.Config, timeout time.Duration) (*tls.ConnectionState, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config:    config,
+	}
+
+	handshakeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(handshakeCtx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	state := conn.(*tls.Conn).ConnectionState()
+	return &state, nil
+}
+
+// createService creates a service for a TLS endpoint discovered on a target
+func (s *TLSScanner) createService(host string, result *tlsPortResult) models.Service {
+	serviceName, known := wellKnownPorts[result.port]
+	if !known {
+		serviceName = "ssl"
+	}
+
+	return models.Service{
+		ID:          uuid.New(),
+		TargetID:    uuid.Nil, // Will be set by worker
+		Port:        result.port,
+		Protocol:    "tcp",
+		ServiceName: serviceName,
+		Title:       fmt.Sprintf("TLS service on port %d", result.port),
+		Description: fmt.Sprintf("TLS service on port %d supporting %s", result.port, strings.Join(result.versions, ", ")),
+		RawInfo: models.JSONB{
+			"tls_versions":  result.versions,
+			"tls_ciphers":   result.ciphers,
+			"target_value":  host,
+			"discovered_at": time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// createCertificate builds the certificate record for the leaf certificate with the full chain in its details
+func (s *TLSScanner) createCertificate(host string, result *tlsPortResult) models.Certificate {
+	leaf := result.chain[0]
+
+	var chain []models.JSONB
+	for i, cert := range result.chain {
+		keyType, keySize := certificateKeyInfo(cert)
+		chain = append(chain, models.JSONB{
+			"position":            i,
+			"subject":             cert.Subject.String(),
+			"issuer":              cert.Issuer.String(),
+			"serial_number":       cert.SerialNumber.String(),
+			"sha256_fingerprint":  certificateFingerprint(cert),
+			"not_before":          cert.NotBefore.Format(time.RFC3339),
+			"not_after":           cert.NotAfter.Format(time.RFC3339),
+			"key_type":            keyType,
+			"key_size":            keySize,
+			"signature_algorithm": cert.SignatureAlgorithm.String(),
+			"is_ca":               cert.IsCA,
+			"san":                 certificateSANs(cert),
+		})
+	}
+
+	keyType, keySize := certificateKeyInfo(leaf)
+	domain := leaf.Subject.CommonName
+	if domain == "" && len(leaf.DNSNames) > 0 {
+		domain = leaf.DNSNames[0]
+	}
+
+	return models.Certificate{
+		ExpiresAt: leaf.NotAfter,
+		IssuedAt:  leaf.NotBefore,
+		Issuer:    leaf.Issuer.String(),
+		Domain:    domain,
+		Details: models.JSONB{
+			"host":                host,
+			"port":                result.port,
+			"subject":             leaf.Subject.String(),
+			"serial_number":       leaf.SerialNumber.String(),
+			"sha256_fingerprint":  certificateFingerprint(leaf),
+			"key_type":            keyType,
+			"key_size":            keySize,
+			"signature_algorithm": leaf.SignatureAlgorithm.String(),
+			"san":                 certificateSANs(leaf),
+			"self_signed":         isSelfSigned(leaf),
+			"chain":               chain,
+			"tls_versions":        result.versions,
+			"tls_ciphers":         result.ciphers,
+			"negotiated_version":  tls.VersionName(result.negotiated.Version),
+			"negotiated_cipher":   tls.CipherSuiteName(result.negotiated.CipherSuite),
+		},
+	}
+}
+
+// evaluate creates findings for certificate and protocol problems of a TLS endpoint
+func (s *TLSScanner) evaluate(host string, serverName string, result *tlsPortResult, expiryWarningDays int) []models.Finding {
+	var findings []models.Finding
+
+	leaf := result.chain[0]
+	address := net.JoinHostPort(host, strconv.Itoa(result.port))
+	now := time.Now()
+
+	baseDetails := func() models.JSONB {
+		return models.JSONB{
+			"host":               host,
+			"port":               result.port,
+			"subject":            leaf.Subject.String(),
+			"issuer":             leaf.Issuer.String(),
+			"sha256_fingerprint": certificateFingerprint(leaf),
+			"not_before":         leaf.NotBefore.Format(time.RFC3339),
+			"not_after":          leaf.NotAfter.Format(time.RFC3339),
+		}
+	}
+
+	// Validity period
+	if now.After(leaf.NotAfter) {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Expired TLS certificate on %s", address),
+			Description: fmt.Sprintf("The certificate for %s expired on %s.", leaf.Subject.String(), leaf.NotAfter.Format("2006-01-02")),
+			Severity:    models.SeverityHigh,
+			FindingType: "tls_certificate_expired",
+			Details:     baseDetails(),
+		})
+	} else if now.Before(leaf.NotBefore) {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("TLS certificate not yet valid on %s", address),
+			Description: fmt.Sprintf("The certificate for %s is not valid before %s.", leaf.Subject.String(), leaf.NotBefore.Format("2006-01-02")),
+			Severity:    models.SeverityMedium,
+			FindingType: "tls_certificate_not_yet_valid",
+			Details:     baseDetails(),
+		})
+	} else if leaf.NotAfter.Before(now.AddDate(0, 0, expiryWarningDays)) {
+		details := baseDetails()
+		details["days_remaining"] = int(leaf.NotAfter.Sub(now).Hours() / 24)
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("TLS certificate expiring soon on %s", address),
+			Description: fmt.Sprintf("The certificate for %s expires on %s.", leaf.Subject.String(), leaf.NotAfter.Format("2006-01-02")),
+			Severity:    models.SeverityLow,
+			FindingType: "tls_certificate_expiring",
+			Details:     details,
+		})
+	}
+
+	// Trust
+	if isSelfSigned(leaf) {
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Self-signed TLS certificate on %s", address),
+			Description: fmt.Sprintf("The certificate presented by %s is self-signed, so clients cannot verify the server's identity.", address),
+			Severity:    models.SeverityMedium,
+			FindingType: "tls_certificate_self_signed",
+			Details:     baseDetails(),
+		})
+	} else if err := verifyChain(result.chain); err != nil {
+		details := baseDetails()
+		details["error"] = err.Error()
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Untrusted TLS certificate chain on %s", address),
+			Description: fmt.Sprintf("The certificate chain presented by %s could not be verified: %v", address, err),
+			Severity:    models.SeverityMedium,
+			FindingType: "tls_certificate_untrusted",
+			Details:     details,
+		})
+	}
+
+	// Hostname
+	expectedName := serverName
+	if expectedName == "" {
+		expectedName = host
+	}
+	if err := leaf.VerifyHostname(expectedName); err != nil {
+		details := baseDetails()
+		details["expected_name"] = expectedName
+		details["san"] = certificateSANs(leaf)
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("TLS certificate hostname mismatch on %s", address),
+			Description: fmt.Sprintf("The certificate presented by %s is not valid for %s. It covers: %s", address, expectedName, strings.Join(certificateSANs(leaf), ", ")),
+			Severity:    models.SeverityMedium,
+			FindingType: "tls_hostname_mismatch",
+			Details:     details,
+		})
+	}
+
+	// Key strength of every certificate in the chain
+	for i, cert := range result.chain {
+		keyType, keySize := certificateKeyInfo(cert)
+		if !isWeakKey(keyType, keySize) {
+			continue
+		}
+
+		details := baseDetails()
+		details["chain_position"] = i
+		details["certificate_subject"] = cert.Subject.String()
+		details["key_type"] = keyType
+		details["key_size"] = keySize
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Weak %s key in TLS certificate on %s", keyType, address),
+			Description: fmt.Sprintf("The certificate %s uses a %d-bit %s key, which is too short to be considered secure.", cert.Subject.String(), keySize, keyType),
+			Severity:    models.SeverityHigh,
+			FindingType: "tls_weak_key",
+			Details:     details,
+		})
+	}
+
+	// Signature algorithm, the root's own signature is never checked by clients
+	for i, cert := range result.chain {
+		if isSelfSigned(cert) && i > 0 {
+			continue
+		}
+		switch cert.SignatureAlgorithm {
+		case x509.MD2WithRSA, x509.MD5WithRSA, x509.SHA1WithRSA, x509.DSAWithSHA1, x509.ECDSAWithSHA1:
+			details := baseDetails()
+			details["chain_position"] = i
+			details["certificate_subject"] = cert.Subject.String()
+			details["signature_algorithm"] = cert.SignatureAlgorithm.String()
+			findings = append(findings, models.Finding{
+				Title:       fmt.Sprintf("Weak certificate signature algorithm on %s", address),
+				Description: fmt.Sprintf("The certificate %s is signed with %s, which is vulnerable to collision attacks.", cert.Subject.String(), cert.SignatureAlgorithm.String()),
+				Severity:    models.SeverityMedium,
+				FindingType: "tls_weak_signature",
+				Details:     details,
+			})
+		}
+	}
+
+	// Protocols
+	var legacy []string
+	for _, version := range result.versions {
+		if version == tls.VersionName(tls.VersionTLS10) || version == tls.VersionName(tls.VersionTLS11) {
+			legacy = append(legacy, version)
+		}
+	}
+	if len(legacy) > 0 {
+		details := baseDetails()
+		details["legacy_protocols"] = legacy
+		details["supported_protocols"] = result.versions
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Legacy TLS protocols enabled on %s", address),
+			Description: fmt.Sprintf("%s accepts connections using %s. These protocol versions are deprecated (RFC 8996) and lack modern security properties.", address, strings.Join(legacy, " and ")),
+			Severity:    models.SeverityMedium,
+			FindingType: "tls_legacy_protocol",
+			Details:     details,
+		})
+	}
+
+	if len(result.weakCipher) > 0 {
+		details := baseDetails()
+		details["weak_ciphers"] = result.weakCipher
+		findings = append(findings, models.Finding{
+			Title:       fmt.Sprintf("Weak TLS cipher suites enabled on %s", address),
+			Description: fmt.Sprintf("%s accepts the following insecure cipher suites:\n• %s", address, strings.Join(result.weakCipher, "\n• ")),
+			Severity:    models.SeverityMedium,
+			FindingType: "tls_weak_cipher",
+			Details:     details,
+		})
+	}
+
+	// Summary of the endpoint
+	details := baseDetails()
+	details["protocols"] = result.versions
+	details["ciphers"] = result.ciphers
+	details["san"] = certificateSANs(leaf)
+	details["chain_length"] = len(result.chain)
+	findings = append(findings, models.Finding{
+		Title:       fmt.Sprintf("TLS configuration for %s", address),
+		Description: s.generateSummaryDescription(address, result),
+		Severity:    models.SeverityInfo,
+		FindingType: "tls_summary",
+		Details:     details,
+	})
+
+	// Keep the findings of each port of a host apart
+	for i := range findings {
+		findings[i].Fingerprint = address
+	}
+
+	return findings
+}
+
+// generateSummaryDescription creates a human-readable description of a TLS endpoint
+func (s *TLSScanner) generateSummaryDescription(address string, result *tlsPortResult) string {
+	leaf := result.chain[0]
+	keyType, keySize := certificateKeyInfo(leaf)
+
+	desc := fmt.Sprintf("%s supports %s.", address, strings.Join(result.versions, ", "))
+	desc += fmt.Sprintf("\n\nCertificate: %s", leaf.Subject.String())
+	desc += fmt.Sprintf("\n• Issuer: %s", leaf.Issuer.String())
+	desc += fmt.Sprintf("\n• Valid: %s to %s", leaf.NotBefore.Format("2006-01-02"), leaf.NotAfter.Format("2006-01-02"))
+	desc += fmt.Sprintf("\n• Key: %s %d bits", keyType, keySize)
+	desc += fmt.Sprintf("\n• Signature: %s", leaf.SignatureAlgorithm.String())
+	desc += fmt.Sprintf("\n• SHA-256: %s", certificateFingerprint(leaf))
+
+	return desc
+}
+
+// supportsVersion reports whether a cipher suite can be used with a protocol version
+func supportsVersion(suite *tls.CipherSuite, version uint16) bool {
+	for _, v := range suite.SupportedVersions {
+		if v == version {
+			return true
+		}
+	}
+	return false
+}
+
+// certificateFingerprint returns the SHA-256 fingerprint of a certificate as colon separated hex
+func certificateFingerprint(cert *x509.Certificate) string {
+	sum := sha256.Sum256(cert.Raw)
+	encoded := strings.ToUpper(hex.EncodeToString(sum[:]))
+
+	var parts []string
+	for i := 0; i < len(encoded); i += 2 {
+		parts = append(parts, encoded[i:i+2])
+	}
+	return strings.Join(parts, ":")
+}
+
+// certificateKeyInfo returns the public key type and size of a certificate
+func certificateKeyInfo(cert *x509.Certificate) (string, int) {
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		return "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		return "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		return "Ed25519", 256
+	default:
+		return cert.PublicKeyAlgorithm.String(), 0
+	}
+}
+
+// certificateSANs returns the DNS names and IP addresses a certificate is valid for
+func certificateSANs(cert *x509.Certificate) []string {
+	sans := append([]string{}, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+	return sans
+}
+
+// isSelfSigned reports whether a certificate is signed by its own key
+func isSelfSigned(cert *x509.Certificate) bool {
+	if cert.Subject.String() != cert.Issuer.String() {
+		return false
+	}
+	return cert.CheckSignatureFrom(cert) == nil
+}
+
+// isWeakKey reports whether a public key is too short to be secure
+func isWeakKey(keyType string, keySize int) bool {
+	switch keyType {
+	case "RSA":
+		return keySize < 2048
+	case "ECDSA":
+		return keySize < 256
+	case "DSA":
+		return true
+	default:
+		return false
+	}
+}
+
+// verifyChain verifies the presented chain against the system roots, without checking the hostname
+func verifyChain(chain []*x509.Certificate) error {
+	intermediates := x509.NewCertPool()
+	for _, cert := range chain[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	// Expiry is reported separately, so verify an expired leaf as of its last valid moment
+	currentTime := time.Now()
+	if currentTime.After(chain[0].NotAfter) {
+		currentTime = chain[0].NotAfter.Add(-time.Minute)
+	}
+
+	_, err := chain[0].Verify(x509.VerifyOptions{
+		Intermediates: intermediates,
+		CurrentTime:   currentTime,
+	})
+	return err
+}
+
+// Type returns the scanner type identifier
+func (s *TLSScanner) Type() string {
+	return "tls"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *TLSScanner) SupportsTargetType(targetType string) bool {
+	switch targetType {
+	case models.TargetTypeDomain, models.TargetTypeIP:
+		return true
+	default:
+		return false
+	}
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *TLSScanner) SupportsServices() bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *TLSScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"ports":               stringParam("Ports to inspect, defaults to 443"),
+		"timeout":             integerParam("Handshake timeout in seconds", 1, 0),
+		"enumerate_ciphers":   booleanParam("Enumerate the cipher suites of every protocol version"),
+		"expiry_warning_days": integerParam("Days before expiry a certificate is reported as expiring", 0, 0),
+		"server_name":         stringParam("SNI server name, defaults to the target"),
+	})
+}
@@ -0,0 +1,722 @@
+// internal/scanner/vhost.go
+package scanner
+
+import (
+	"backend/internal/models"
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// maxVHostBodySize caps how much of a response body is read to compare it with the baseline
+const maxVHostBodySize = 1 << 20
+
+// defaultVHostWords are prefixed to the base domains when no wordlist is configured
+var defaultVHostWords = []string{
+	"www", "www2", "mail", "webmail", "owa", "autodiscover", "admin", "administrator", "portal",
+	"intranet", "extranet", "internal", "vpn", "remote", "sso", "auth", "login", "secure", "api",
+	"app", "apps", "dev", "development", "staging", "stage", "test", "testing", "qa", "uat",
+	"preprod", "prod", "beta", "demo", "sandbox", "old", "new", "legacy", "backup", "m", "mobile",
+	"shop", "store", "blog", "cms", "wp", "wiki", "docs", "help", "support", "status", "monitor",
+	"monitoring", "grafana", "kibana", "prometheus", "git", "gitlab", "jenkins", "ci", "jira",
+	"confluence", "crm", "erp", "hr", "files", "ftp", "cpanel", "webdisk", "static", "assets",
+	"cdn", "media", "img", "images", "dashboard", "manage", "management", "console", "localhost",
+}
+
+// vhostTitlePattern extracts the title of an HTML page
+var vhostTitlePattern = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// vhostSNIKey carries the server name to send in the TLS handshake of a request
+type vhostSNIKey struct{}
+
+// VHostScanner implements the Scanner interface for discovering name based virtual hosts
+type VHostScanner struct {
+	wordlistPath  string
+	concurrency   int
+	timeout       int
+	userAgent     string
+	maxCandidates int
+}
+
+// VHostTarget holds the IP address to probe and the candidate base URLs, the first reachable one is used
+type VHostTarget struct {
+	IP       string
+	BaseURLs []string
+}
+
+// vhostResponse describes a response well enough to compare it with the baseline
+type vhostResponse struct {
+	Host       string `json:"host"`
+	StatusCode int    `json:"status_code"`
+	Size       int    `json:"content_length"`
+	Words      int    `json:"words"`
+	Lines      int    `json:"lines"`
+	Title      string `json:"title,omitempty"`
+	Location   string `json:"redirect_location,omitempty"`
+	Resolves   bool   `json:"resolves_to_ip"`
+}
+
+// NewVHostScanner creates a new virtual host discovery scanner
+func NewVHostScanner() *VHostScanner {
+	return &VHostScanner{
+		wordlistPath:  os.Getenv("VHOST_WORDLIST"), // Built-in wordlist when empty
+		concurrency:   20,                          // Requests in flight
+		timeout:       10,                          // Request timeout in seconds
+		userAgent:     "Zecas-VHost",               // User agent sent with every request
+		maxCandidates: 5000,                        // Host names tried per target
+	}
+}
+
+// Initialize checks that the configured wordlist exists
+func (s *VHostScanner) Initialize(ctx context.Context) error {
+	if s.wordlistPath == "" {
+		return nil
+	}
+	if _, err := os.Stat(s.wordlistPath); err != nil {
+		return fmt.Errorf("wordlist %s is not readable: %w", s.wordlistPath, err)
+	}
+	return nil
+}
+
+// ConvertTarget converts an IP Target to a format suitable for virtual host discovery
+func (s *VHostScanner) ConvertTarget(target models.Target) interface{} {
+	if target.TargetType != models.TargetTypeIP {
+		return nil
+	}
+	return VHostTarget{IP: target.Value, BaseURLs: webTargetURLs(target)}
+}
+
+// ConvertService converts a web Service on an IP address to a format suitable for virtual host discovery
+func (s *VHostScanner) ConvertService(service models.Service) interface{} {
+	baseURL := webServiceURL(service)
+	if baseURL == "" {
+		return nil
+	}
+
+	host := service.RawInfo["target_value"].(string)
+	if net.ParseIP(host) == nil {
+		return nil // Host names are only tried against IP addresses
+	}
+	return VHostTarget{IP: host, BaseURLs: []string{baseURL}}
+}
+
+// UsesProjectDomains makes the worker pass the domains known in the project as candidates
+func (s *VHostScanner) UsesProjectDomains() bool {
+	return true
+}
+
+// Scan sends requests with candidate Host headers to the IP address and reports the ones
+// answered differently from a host name that cannot exist
+func (s *VHostScanner) Scan(ctx context.Context, target interface{}, params models.JSONB) (*models.ScanResults, error) {
+	vhostTarget, ok := target.(VHostTarget)
+	if !ok {
+		return nil, fmt.Errorf("invalid target for vhost scanner")
+	}
+
+	scanResults := &models.ScanResults{
+		Findings:        []models.Finding{},
+		NewTargets:      []models.Target{},
+		TargetRelations: []models.TargetRelation{},
+	}
+
+	// Configure scan parameters
+	wordlist := s.wordlistPath
+	var words, domains, hosts, projectDomains []string
+	includeProjectDomains := true
+	filterStatus := []int{400, 421}
+	concurrency := s.concurrency
+	timeout := s.timeout
+	userAgent := s.userAgent
+	maxCandidates := s.maxCandidates
+
+	// Override with provided parameters if available
+	if val, ok := params["url"].(string); ok && val != "" {
+		if !strings.HasSuffix(val, "/") {
+			val += "/"
+		}
+		vhostTarget.BaseURLs = []string{val}
+	}
+	if val, ok := params["wordlist"].(string); ok && val != "" {
+		file, err := wordlistFile(val)
+		if err != nil {
+			return nil, err
+		}
+		wordlist = file
+	}
+	if val, ok := params["words"].([]interface{}); ok {
+		words = stringList(val)
+	}
+	if val, ok := params["domains"].([]interface{}); ok {
+		domains = stringList(val)
+	}
+	if val, ok := params["hosts"].([]interface{}); ok {
+		hosts = stringList(val)
+	}
+	if val, ok := params["include_project_domains"].(bool); ok {
+		includeProjectDomains = val
+	}
+	if val, ok := params["project_domains"].([]interface{}); ok && includeProjectDomains {
+		projectDomains = stringList(val)
+	}
+	if val, ok := params["filter_status"].([]interface{}); ok {
+		filterStatus = intList(val)
+	}
+	if val, ok := params["concurrency"].(float64); ok && val > 0 {
+		concurrency = int(val)
+	}
+	if val, ok := params["timeout"].(float64); ok && val > 0 {
+		timeout = int(val)
+	}
+	if val, ok := params["user_agent"].(string); ok && val != "" {
+		userAgent = val
+	}
+	if val, ok := params["max_candidates"].(float64); ok && val > 0 {
+		maxCandidates = int(val)
+	}
+
+	// Explicit words take precedence over the wordlist file
+	if len(words) == 0 {
+		var err error
+		words, err = s.loadWordlist(wordlist)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Words are prefixed to the base domains, which default to the topmost domains of the project
+	if len(domains) == 0 {
+		domains = topmostDomains(projectDomains)
+	}
+	candidates, truncated := vhostCandidates(vhostTarget.IP, hosts, projectDomains, words, domains, maxCandidates)
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no candidate host names for %s, set domains or hosts", vhostTarget.IP)
+	}
+
+	httpClient := s.newClient(timeout)
+
+	// Use the first base URL that answers
+	var baseURL *url.URL
+	for _, candidate := range vhostTarget.BaseURLs {
+		parsed, err := url.Parse(candidate)
+		if err != nil {
+			continue
+		}
+		if _, err := s.request(ctx, httpClient, parsed, "", userAgent); err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			continue
+		}
+		baseURL = parsed
+		break
+	}
+	if baseURL == nil {
+		return nil, fmt.Errorf("no web server reachable on %s", vhostTarget.IP)
+	}
+
+	// Learn how the server answers host names it doesn't serve
+	baseline := s.baseline(ctx, httpClient, baseURL, domains, userAgent)
+	tolerance := sizeTolerance(baseline)
+
+	responses := make([]*vhostResponse, len(candidates))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency && w < len(candidates); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if resp, err := s.request(ctx, httpClient, baseURL, candidates[i], userAgent); err == nil {
+					responses[i] = resp
+				}
+			}
+		}()
+	}
+
+	for i := range candidates {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	var hits []vhostResponse
+	for _, resp := range responses {
+		if resp == nil || containsInt(filterStatus, resp.StatusCode) || matchesBaseline(*resp, baseline, tolerance) {
+			continue
+		}
+		hits = append(hits, *resp)
+	}
+
+	// A server answering most names differently can't be told apart from one serving them all
+	if len(hits) > 10 && len(hits)*2 > len(candidates) {
+		scanResults.Findings = append(scanResults.Findings, s.createSummaryFinding(nil, baseURL, vhostTarget.IP, len(candidates), truncated, baseline,
+			fmt.Sprintf("%d of %d host names were answered differently from the baseline, so the responses don't tell virtual hosts apart. Nothing was recorded.", len(hits), len(candidates))))
+		return scanResults, nil
+	}
+
+	// Request every hit again so responses that only differed by chance are dropped
+	var confirmed []vhostResponse
+	for _, hit := range hits {
+		resp, err := s.request(ctx, httpClient, baseURL, hit.Host, userAgent)
+		if err != nil || matchesBaseline(*resp, baseline, tolerance) {
+			continue
+		}
+		resp.Resolves = resolvesTo(ctx, hit.Host, vhostTarget.IP)
+		confirmed = append(confirmed, *resp)
+	}
+
+	for _, vhost := range confirmed {
+		s.addVHostTarget(scanResults, vhost, baseURL, vhostTarget.IP)
+	}
+
+	if len(confirmed) > 0 {
+		scanResults.Findings = append(scanResults.Findings, s.createSummaryFinding(confirmed, baseURL, vhostTarget.IP, len(candidates), truncated, baseline, ""))
+	}
+
+	return scanResults, nil
+}
+
+// newClient creates an HTTP client sending the Host header as server name in TLS handshakes.
+// Connections aren't reused so every request is routed by its own server name.
+func (s *VHostScanner) newClient(timeout int) *http.Client {
+	dialer := &net.Dialer{Timeout: time.Duration(timeout) * time.Second}
+
+	return &http.Client{
+		Timeout: time.Duration(timeout) * time.Second,
+		Transport: &http.Transport{
+			DisableKeepAlives: true,
+			DialContext:       dialer.DialContext,
+			DialTLSContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+				conn, err := dialer.DialContext(ctx, network, addr)
+				if err != nil {
+					return nil, err
+				}
+
+				serverName, _ := ctx.Value(vhostSNIKey{}).(string)
+				tlsConn := tls.Client(conn, &tls.Config{
+					InsecureSkipVerify: true,
+					ServerName:         serverName,
+				})
+				if err := tlsConn.HandshakeContext(ctx); err != nil {
+					conn.Close()
+					return nil, err
+				}
+				return tlsConn, nil
+			},
+		},
+		// Redirects are compared, not followed
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// loadWordlist reads a wordlist file, or returns the built-in wordlist when no file is configured
+func (s *VHostScanner) loadWordlist(wordlist string) ([]string, error) {
+	if wordlist == "" {
+		return defaultVHostWords, nil
+	}
+
+	file, err := os.Open(wordlist)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open wordlist: %w", err)
+	}
+	defer file.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		word := strings.TrimSpace(scanner.Text())
+		if word == "" || strings.HasPrefix(word, "#") {
+			continue
+		}
+		words = append(words, word)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read wordlist: %w", err)
+	}
+
+	return words, nil
+}
+
+// baseline requests host names that can't exist, and the IP address itself, to learn the default answer
+func (s *VHostScanner) baseline(ctx context.Context, client *http.Client, baseURL *url.URL, domains []string, userAgent string) []vhostResponse {
+	probes := []string{
+		randomContentToken() + ".invalid",
+		randomContentToken() + ".invalid",
+		"", // The address of the base URL
+	}
+	for i, domain := range domains {
+		if i == 2 {
+			break
+		}
+		probes = append(probes, randomContentToken()+"."+domain)
+	}
+
+	var baseline []vhostResponse
+	for _, probe := range probes {
+		resp, err := s.request(ctx, client, baseURL, probe, userAgent)
+		if err != nil {
+			continue
+		}
+		baseline = append(baseline, *resp)
+	}
+	return baseline
+}
+
+// request fetches the base URL with the given Host header and measures the response.
+// Occurrences of the host name are replaced so pages echoing it compare equal.
+func (s *VHostScanner) request(ctx context.Context, client *http.Client, baseURL *url.URL, host string, userAgent string) (*vhostResponse, error) {
+	if host != "" {
+		ctx = context.WithValue(ctx, vhostSNIKey{}, host)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if host != "" {
+		req.Host = host
+	}
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxVHostBodySize))
+	if err != nil {
+		return nil, err
+	}
+
+	location := resp.Header.Get("Location")
+	if host != "" {
+		body = bytes.ReplaceAll(body, []byte(host), []byte("FUZZ"))
+		location = strings.ReplaceAll(location, host, "FUZZ")
+	}
+
+	title := ""
+	if match := vhostTitlePattern.FindSubmatch(body); match != nil {
+		title = strings.Join(strings.Fields(html.UnescapeString(string(match[1]))), " ")
+		if len(title) > 200 {
+			title = title[:200]
+		}
+	}
+
+	return &vhostResponse{
+		Host:       host,
+		StatusCode: resp.StatusCode,
+		Size:       len(body),
+		Words:      len(bytes.Fields(body)),
+		Lines:      bytes.Count(body, []byte("\n")) + 1,
+		Title:      title,
+		Location:   location,
+	}, nil
+}
+
+// addVHostTarget records a confirmed virtual host as a domain target related to the IP address
+func (s *VHostScanner) addVHostTarget(scanResults *models.ScanResults, vhost vhostResponse, baseURL *url.URL, ip string) {
+	vhostURL := *baseURL
+	if port := baseURL.Port(); port != "" {
+		vhostURL.Host = net.JoinHostPort(vhost.Host, port)
+	} else {
+		vhostURL.Host = vhost.Host
+	}
+
+	metadata := models.JSONB{
+		"discovered_from":   ip,
+		"discovery_scan":    "vhost",
+		"discovered_at":     time.Now().Format(time.RFC3339),
+		"vhost_ip":          ip,
+		"vhost_url":         vhostURL.String(),
+		"vhost_status_code": vhost.StatusCode,
+	}
+	if vhost.Title != "" {
+		metadata["vhost_title"] = vhost.Title
+	}
+
+	domainTarget := models.Target{
+		ID:         uuid.New(),
+		TargetType: models.TargetTypeDomain,
+		Value:      vhost.Host,
+		Metadata:   metadata,
+	}
+	scanResults.NewTargets = append(scanResults.NewTargets, domainTarget)
+
+	// Host names whose DNS points elsewhere are only served here
+	relationType := models.RelationResolvesTo
+	if !vhost.Resolves {
+		relationType = models.RelationHostedOn
+	}
+
+	relation := models.TargetRelation{
+		ID:            uuid.New(),
+		SourceID:      domainTarget.ID,
+		DestinationID: uuid.Nil, // Will be set to the scanned target by worker
+		RelationType:  relationType,
+		Metadata: models.JSONB{
+			"discovered_at":  time.Now().Format(time.RFC3339),
+			"discovery_scan": "vhost",
+		},
+	}
+	scanResults.TargetRelations = append(scanResults.TargetRelations, relation)
+}
+
+// createSummaryFinding lists every confirmed virtual host in a single informational finding
+func (s *VHostScanner) createSummaryFinding(vhosts []vhostResponse, baseURL *url.URL, ip string, requests int, truncated bool, baseline []vhostResponse, note string) models.Finding {
+	sort.Slice(vhosts, func(i, j int) bool {
+		return vhosts[i].Host < vhosts[j].Host
+	})
+
+	var lines []string
+	for _, vhost := range vhosts {
+		line := fmt.Sprintf("- %s [%d, %d bytes", vhost.Host, vhost.StatusCode, vhost.Size)
+		if vhost.Title != "" {
+			line += fmt.Sprintf(", %q", vhost.Title)
+		}
+		line += "]"
+		if !vhost.Resolves {
+			line += " not pointed at this address in DNS"
+		}
+		lines = append(lines, line)
+	}
+
+	var defaults []models.JSONB
+	for _, resp := range baseline {
+		defaults = append(defaults, models.JSONB{
+			"status_code":    resp.StatusCode,
+			"content_length": resp.Size,
+			"words":          resp.Words,
+			"lines":          resp.Lines,
+		})
+	}
+
+	description := fmt.Sprintf("Trying %d host names against %s found:\n%s", requests, baseURL.String(), strings.Join(lines, "\n"))
+	if note != "" {
+		description = note
+	}
+	if truncated {
+		description += "\n\nThe candidate list was truncated, raise max_candidates to try every host name."
+	}
+
+	return models.Finding{
+		Title:       fmt.Sprintf("Virtual host discovery found %d hosts on %s", len(vhosts), ip),
+		Description: description,
+		Severity:    models.SeverityInfo,
+		FindingType: "vhost_discovery",
+		Details: models.JSONB{
+			"ip":         ip,
+			"url":        baseURL.String(),
+			"requests":   requests,
+			"truncated":  truncated,
+			"vhosts":     vhosts,
+			"baseline":   defaults,
+			"scanned_at": time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// vhostCandidates builds the host names to try: explicit hosts, the project domains and every word
+// below every base domain, without duplicates and capped at max
+func vhostCandidates(ip string, hosts, projectDomains, words, domains []string, max int) ([]string, bool) {
+	seen := map[string]bool{normalizeHost(ip): true}
+	var candidates []string
+	truncated := false
+	add := func(host string) {
+		host = normalizeHost(host)
+		if host == "" || seen[host] {
+			return
+		}
+		if len(candidates) >= max {
+			truncated = true
+			return
+		}
+		seen[host] = true
+		candidates = append(candidates, host)
+	}
+
+	for _, host := range hosts {
+		add(host)
+	}
+	for _, domain := range projectDomains {
+		add(domain)
+	}
+	for _, word := range words {
+		// Words with a dot are complete host names
+		if strings.Contains(strings.Trim(word, "."), ".") {
+			add(word)
+			continue
+		}
+		for _, domain := range domains {
+			add(strings.Trim(word, ".") + "." + normalizeHost(domain))
+		}
+	}
+	return candidates, truncated
+}
+
+// topmostDomains returns the domains that aren't below another domain of the list
+func topmostDomains(domains []string) []string {
+	normalized := make(map[string]bool)
+	for _, domain := range domains {
+		if domain = normalizeHost(domain); domain != "" && net.ParseIP(domain) == nil {
+			normalized[domain] = true
+		}
+	}
+
+	var topmost []string
+	for domain := range normalized {
+		parent := domain
+		covered := false
+		for {
+			index := strings.Index(parent, ".")
+			if index < 0 {
+				break
+			}
+			parent = parent[index+1:]
+			if normalized[parent] {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			topmost = append(topmost, domain)
+		}
+	}
+	sort.Strings(topmost)
+	return topmost
+}
+
+// normalizeHost lowercases a host name and strips wildcard labels and the trailing dot
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	host = strings.TrimPrefix(host, "*.")
+	return strings.TrimSuffix(host, ".")
+}
+
+// matchesBaseline reports whether a response looks like the answer to a host name the server doesn't serve
+func matchesBaseline(resp vhostResponse, baseline []vhostResponse, tolerance int) bool {
+	for _, base := range baseline {
+		if resp.StatusCode != base.StatusCode || resp.Location != base.Location || resp.Title != base.Title {
+			continue
+		}
+		size := resp.Size - base.Size
+		if size < 0 {
+			size = -size
+		}
+		if size <= tolerance || (resp.Words == base.Words && resp.Lines == base.Lines) {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeTolerance is how much the size of equivalent baseline responses varies, for pages with dynamic content
+func sizeTolerance(baseline []vhostResponse) int {
+	tolerance := 0
+	for i := range baseline {
+		for j := i + 1; j < len(baseline); j++ {
+			if baseline[i].StatusCode != baseline[j].StatusCode || baseline[i].Title != baseline[j].Title {
+				continue
+			}
+			diff := baseline[i].Size - baseline[j].Size
+			if diff < 0 {
+				diff = -diff
+			}
+			if diff > tolerance {
+				tolerance = diff
+			}
+		}
+	}
+	return tolerance
+}
+
+// resolvesTo reports whether the host name resolves to the IP address
+func resolvesTo(ctx context.Context, host string, ip string) bool {
+	lookupCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	addrs, err := net.DefaultResolver.LookupHost(lookupCtx, host)
+	if err != nil {
+		return false
+	}
+	target := net.ParseIP(ip)
+	for _, addr := range addrs {
+		if parsed := net.ParseIP(addr); parsed != nil && parsed.Equal(target) {
+			return true
+		}
+	}
+	return false
+}
+
+// stringList converts a decoded JSON list of strings, skipping empty values
+func stringList(values []interface{}) []string {
+	var list []string
+	for _, value := range values {
+		if str, ok := value.(string); ok && strings.TrimSpace(str) != "" {
+			list = append(list, strings.TrimSpace(str))
+		}
+	}
+	return list
+}
+
+// Type returns the scanner type identifier
+func (s *VHostScanner) Type() string {
+	return "vhost"
+}
+
+// SupportsTargetType indicates whether this scanner can handle the specified target type
+func (s *VHostScanner) SupportsTargetType(targetType string) bool {
+	return targetType == models.TargetTypeIP
+}
+
+// SupportsServices indicates whether this scanner can scan services
+func (s *VHostScanner) SupportsServices() bool {
+	return true
+}
+
+// ParametersSchema returns the JSON Schema of the parameters accepted by the scanner
+func (s *VHostScanner) ParametersSchema() models.JSONB {
+	return parametersSchema(map[string]interface{}{
+		"url":                     map[string]interface{}{"type": "string", "description": "Base URL to request instead of the target's root", "pattern": "^https?://"},
+		"wordlist":                stringParam("Name of a wordlist file in the worker's wordlist directory, defaults to a built-in list of common host names"),
+		"words":                   stringListParam("Words to try instead of a wordlist, prefixed to every base domain unless they contain a dot"),
+		"domains":                 stringListParam("Base domains the words are prefixed to, defaults to the topmost domains of the project"),
+		"hosts":                   stringListParam("Complete host names to try"),
+		"include_project_domains": booleanParam("Try every domain known in the project, defaults to true"),
+		"filter_status": map[string]interface{}{
+			"type":        "array",
+			"description": "Status codes never reported, defaults to 400 and 421",
+			"items":       map[string]interface{}{"type": "integer", "minimum": 100, "maximum": 599},
+		},
+		"max_candidates": integerParam("Maximum number of host names tried per target, defaults to 5000", 1, 0),
+		"concurrency":    integerParam("Requests in flight", 1, 200),
+		"timeout":        integerParam("Request timeout in seconds", 1, 0),
+		"user_agent":     stringParam("User agent sent with every request"),
+		"skip_cdn":       booleanParam("Skip targets attributed to a CDN edge network"),
+	})
+}
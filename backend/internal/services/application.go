@@ -3,6 +3,7 @@ package services
 
 import (
 	"backend/internal/models"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -63,6 +64,82 @@ func (s *ApplicationService) Create(application *models.Application) error {
 	return s.db.Create(application).Error
 }
 
+// UpsertApplication creates an application or refreshes the one already stored for the same
+// project, type and location. A service is the location when known, otherwise the host and URL.
+func (s *ApplicationService) UpsertApplication(application *models.Application) (*models.Application, error) {
+	var existingApplication models.Application
+	query := s.db.Where("project_id = ? AND type = ?", application.ProjectID, application.Type)
+	if application.ServiceID != nil {
+		query = query.Where("service_id = ?", *application.ServiceID)
+	} else {
+		query = query.Where("service_id IS NULL AND url = ?", application.URL)
+		if application.HostTarget != nil {
+			query = query.Where("host_target = ?", *application.HostTarget)
+		}
+	}
+	result := query.First(&existingApplication)
+
+	if result.Error == nil {
+		// Application already exists, refresh it with the latest scan
+		updates := map[string]interface{}{}
+
+		if application.ScanID != nil {
+			updates["scan_id"] = application.ScanID
+		}
+
+		if application.Name != "" && application.Name != existingApplication.Name {
+			updates["name"] = application.Name
+		}
+
+		if application.Description != "" && application.Description != existingApplication.Description {
+			updates["description"] = application.Description
+		}
+
+		if application.URL != "" && application.URL != existingApplication.URL {
+			updates["url"] = application.URL
+		}
+
+		// Merge the metadata, keeping the original discovery time
+		mergedMetadata := existingApplication.Metadata
+		if mergedMetadata == nil {
+			mergedMetadata = models.JSONB{}
+		}
+		for k, v := range application.Metadata {
+			if _, exists := mergedMetadata[k]; exists && k == "discovered_at" {
+				continue
+			}
+			mergedMetadata[k] = v
+		}
+
+		// Keep track of earlier versions when the detected version changes
+		if application.Version != "" && application.Version != existingApplication.Version {
+			updates["version"] = application.Version
+			if existingApplication.Version != "" {
+				history, _ := mergedMetadata["version_history"].([]interface{})
+				mergedMetadata["version_history"] = append(history, map[string]interface{}{
+					"version":     existingApplication.Version,
+					"replaced_at": time.Now().Format(time.RFC3339),
+				})
+			}
+		}
+		updates["metadata"] = mergedMetadata
+		updates["updated_at"] = time.Now()
+
+		if err := s.db.Model(&existingApplication).Updates(updates).Error; err != nil {
+			return nil, err
+		}
+
+		return &existingApplication, nil
+	}
+
+	// Application doesn't exist, create it
+	if err := s.db.Create(application).Error; err != nil {
+		return nil, err
+	}
+
+	return application, nil
+}
+
 // Update updates an existing application
 func (s *ApplicationService) Update(application *models.Application) error {
 	return s.db.Save(application).Error
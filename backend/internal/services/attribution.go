@@ -0,0 +1,139 @@
+// internal/services/attribution.go
+package services
+
+import (
+	"backend/internal/cloud"
+	"backend/internal/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// attributionMetadataKeys are the target metadata keys written by the attribution
+var attributionMetadataKeys = []string{"cloud_provider", "cloud_service", "cloud_region", "cloud_network", "cdn", "cloud_attributed_at"}
+
+// ErrNoCloudRanges is returned when attribution is requested before any range file was loaded
+var ErrNoCloudRanges = errors.New("no cloud or CDN ranges loaded")
+
+// AttributionService tags IP targets with the cloud or CDN provider whose published ranges contain them
+type AttributionService struct {
+	db     *gorm.DB
+	ranges *dataSource[cloud.Database]
+}
+
+// CloudRangeStatus describes the loaded range files
+type CloudRangeStatus struct {
+	Directory string            `json:"directory"`
+	Loaded    bool              `json:"loaded"`
+	LoadedAt  *time.Time        `json:"loaded_at,omitempty"`
+	Ranges    int               `json:"ranges"`
+	Files     []cloud.RangeFile `json:"files"`
+}
+
+// AttributionSummary describes the outcome of attributing the targets of a project
+type AttributionSummary struct {
+	Targets    int `json:"targets"`
+	Attributed int `json:"attributed"`
+	CDN        int `json:"cdn"`
+	NotFound   int `json:"not_found"`
+}
+
+func NewAttributionService(db *gorm.DB, dir string) *AttributionService {
+	ranges := newDataSource("cloud and CDN ranges", dir, cloud.IsRangeFile, cloud.Load, func(database *cloud.Database) string {
+		return fmt.Sprintf("%d cloud and CDN ranges from %d files", database.Size(), len(database.Files))
+	})
+	return &AttributionService{db: db, ranges: ranges}
+}
+
+// LoadRanges loads the range files from the range directory. Unless forced, ranges are only
+// reloaded when files were added, removed or modified. Returns whether new ranges were loaded.
+func (s *AttributionService) LoadRanges(force bool) (bool, error) {
+	return s.ranges.Load(force)
+}
+
+// Status returns the state of the loaded range files
+func (s *AttributionService) Status() CloudRangeStatus {
+	status := CloudRangeStatus{
+		Directory: s.ranges.dir,
+		Files:     []cloud.RangeFile{},
+	}
+	if database := s.ranges.Get(); database != nil {
+		loadedAt := database.LoadedAt
+		status.Loaded = true
+		status.LoadedAt = &loadedAt
+		status.Ranges = database.Size()
+		status.Files = database.Files
+	}
+	return status
+}
+
+// Enrich attributes an IP target and writes the provider into its metadata, without saving it.
+// Returns whether the target is in a published range.
+func (s *AttributionService) Enrich(target *models.Target) (bool, error) {
+	if target.TargetType != models.TargetTypeIP {
+		return false, nil
+	}
+
+	database := s.ranges.Get()
+	if database == nil {
+		return false, ErrNoCloudRanges
+	}
+
+	attribution, err := database.Lookup(target.Value)
+	if err != nil {
+		return false, err
+	}
+
+	// Copy the metadata since targets created together may share the same map
+	metadata := models.JSONB{}
+	for k, v := range target.Metadata {
+		metadata[k] = v
+	}
+
+	// Drop what an earlier lookup found so ranges removed from the files don't linger
+	for _, key := range attributionMetadataKeys {
+		delete(metadata, key)
+	}
+	target.Metadata = metadata
+
+	if attribution == nil {
+		return false, nil
+	}
+
+	metadata["cloud_provider"] = attribution.Provider
+	if attribution.Service != "" {
+		metadata["cloud_service"] = attribution.Service
+	}
+	if attribution.Region != "" {
+		metadata["cloud_region"] = attribution.Region
+	}
+	metadata["cloud_network"] = attribution.Network
+	metadata["cdn"] = attribution.CDN
+	metadata["cloud_attributed_at"] = time.Now().Format(time.RFC3339)
+
+	return true, nil
+}
+
+// AttributeProject attributes every IP target of a project and stores the results in their metadata
+func (s *AttributionService) AttributeProject(projectID uuid.UUID) (*AttributionSummary, error) {
+	summary := &AttributionSummary{}
+	targets, err := enrichProjectTargets(s.db, projectID, s, ErrNoCloudRanges, func(target *models.Target, found bool) {
+		if !found {
+			summary.NotFound++
+			return
+		}
+		summary.Attributed++
+		if cdn, _ := target.Metadata["cdn"].(bool); cdn {
+			summary.CDN++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Targets = targets
+	return summary, nil
+}
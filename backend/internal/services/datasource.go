@@ -0,0 +1,90 @@
+// internal/services/datasource.go
+package services
+
+import (
+	"backend/internal/datadir"
+	"context"
+	"errors"
+	"io/fs"
+	"log"
+	"sync"
+	"time"
+)
+
+// dataSource holds what was loaded from the files of a data directory, such as CVE feeds or GeoIP
+// databases, and reloads it when the files change
+type dataSource[T any] struct {
+	name     string                 // What the files hold, for log messages
+	dir      string                 // Directory the files are read from
+	match    func(name string) bool // Selects the files of the directory to load
+	load     func(dir string) (*T, error)
+	describe func(data *T) string // Summarizes what was loaded, for log messages
+
+	mu          sync.RWMutex
+	data        *T
+	fingerprint string
+}
+
+func newDataSource[T any](name string, dir string, match func(string) bool, load func(string) (*T, error), describe func(*T) string) *dataSource[T] {
+	return &dataSource[T]{name: name, dir: dir, match: match, load: load, describe: describe}
+}
+
+// Load loads the files from the directory. Unless forced, files are only reloaded when they were
+// added, removed or modified. Returns whether new data was loaded.
+func (d *dataSource[T]) Load(force bool) (bool, error) {
+	fingerprint, err := datadir.Fingerprint(d.dir, d.match)
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.RLock()
+	unchanged := d.data != nil && fingerprint == d.fingerprint
+	d.mu.RUnlock()
+	if unchanged && !force {
+		return false, nil
+	}
+
+	data, err := d.load(d.dir)
+	if err != nil {
+		return false, err
+	}
+
+	d.mu.Lock()
+	d.data = data
+	d.fingerprint = fingerprint
+	d.mu.Unlock()
+
+	log.Printf("Loaded %s in %s", d.describe(data), d.dir)
+	return true, nil
+}
+
+// Get returns the loaded data, or nil when nothing was loaded yet
+func (d *dataSource[T]) Get() *T {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.data
+}
+
+// Watch reloads the files when the directory changes and calls changed after each reload, until ctx is cancelled
+func (d *dataSource[T]) Watch(ctx context.Context, interval time.Duration, changed func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			reloaded, err := d.Load(false)
+			if err != nil {
+				if !errors.Is(err, fs.ErrNotExist) {
+					log.Printf("Failed to reload %s: %v", d.name, err)
+				}
+				continue
+			}
+			if reloaded {
+				changed()
+			}
+		}
+	}
+}
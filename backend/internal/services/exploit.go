@@ -0,0 +1,188 @@
+// internal/services/exploit.go
+package services
+
+import (
+	"backend/internal/exploit"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// exploitDetailKeys are the finding details written from the KEV catalog
+var exploitDetailKeys = []string{"kev_date_added", "kev_ransomware"}
+
+// ErrNoExploitData is returned when scoring is requested before any EPSS or KEV file was loaded
+var ErrNoExploitData = errors.New("no EPSS or KEV data loaded")
+
+// ExploitService scores findings with the EPSS probabilities and CISA KEV entries of their CVEs
+type ExploitService struct {
+	db   *gorm.DB
+	data *dataSource[exploit.Database]
+
+	// rescoreMu serializes rescoring so concurrent runs don't overwrite each other
+	rescoreMu sync.Mutex
+}
+
+// ExploitDataStatus describes the loaded EPSS and KEV files
+type ExploitDataStatus struct {
+	Directory  string             `json:"directory"`
+	Loaded     bool               `json:"loaded"`
+	LoadedAt   *time.Time         `json:"loaded_at,omitempty"`
+	EPSSScores int                `json:"epss_scores"`
+	KEVEntries int                `json:"kev_entries"`
+	Files      []exploit.DataFile `json:"files"`
+}
+
+// RescoreSummary describes the outcome of scoring the stored findings
+type RescoreSummary struct {
+	Findings       int `json:"findings"`
+	Scored         int `json:"scored"`
+	KnownExploited int `json:"known_exploited"`
+}
+
+func NewExploitService(db *gorm.DB, dir string) *ExploitService {
+	data := newDataSource("EPSS and KEV data", dir, exploit.IsDataFile, exploit.Load, func(database *exploit.Database) string {
+		return fmt.Sprintf("%d EPSS scores and %d KEV entries from %d files",
+			database.EPSSCount(), database.KEVCount(), len(database.Files))
+	})
+	return &ExploitService{db: db, data: data}
+}
+
+// LoadData loads the EPSS and KEV files from the data directory. Unless forced, files are only
+// reloaded when they were added, removed or modified. Returns whether new data was loaded.
+func (s *ExploitService) LoadData(force bool) (bool, error) {
+	return s.data.Load(force)
+}
+
+// WatchData reloads the data files when they change and rescores the stored findings, until ctx is cancelled
+func (s *ExploitService) WatchData(ctx context.Context, interval time.Duration) {
+	s.data.Watch(ctx, interval, func() {
+		summary, err := s.Rescore(nil)
+		if err != nil {
+			log.Printf("Failed to rescore findings: %v", err)
+			return
+		}
+		log.Printf("Rescored findings after data refresh: %d findings, %d scored, %d known exploited",
+			summary.Findings, summary.Scored, summary.KnownExploited)
+	})
+}
+
+// Status returns the state of the loaded data files
+func (s *ExploitService) Status() ExploitDataStatus {
+	status := ExploitDataStatus{
+		Directory: s.data.dir,
+		Files:     []exploit.DataFile{},
+	}
+	if database := s.data.Get(); database != nil {
+		loadedAt := database.LoadedAt
+		status.Loaded = true
+		status.LoadedAt = &loadedAt
+		status.EPSSScores = database.EPSSCount()
+		status.KEVEntries = database.KEVCount()
+		status.Files = database.Files
+	}
+	return status
+}
+
+// Enrich sets the EPSS score of the most likely exploited CVE of a finding and flags findings with a CVE
+// in the KEV catalog, without saving it. Returns whether any of its CVEs is in the loaded data.
+func (s *ExploitService) Enrich(finding *models.Finding) (bool, error) {
+	if len(finding.CVEIDs) == 0 {
+		return false, nil
+	}
+
+	database := s.data.Get()
+	if database == nil {
+		return false, ErrNoExploitData
+	}
+
+	// Copy the details since findings reported together may share the same map
+	details := models.JSONB{}
+	for k, v := range finding.Details {
+		details[k] = v
+	}
+	for _, key := range exploitDetailKeys {
+		delete(details, key)
+	}
+	finding.Details = details
+	finding.KnownExploited = false
+
+	found := false
+	var best *exploit.Score
+	for _, cveID := range finding.CVEIDs {
+		score, ok := database.Lookup(cveID)
+		if !ok {
+			continue
+		}
+		found = true
+
+		if score.HasEPSS && (best == nil || score.EPSS > best.EPSS) {
+			best = &score
+		}
+		if score.KnownExploited {
+			finding.KnownExploited = true
+			if added, _ := details["kev_date_added"].(string); added == "" || score.DateAdded < added {
+				details["kev_date_added"] = score.DateAdded
+			}
+			if score.Ransomware {
+				details["kev_ransomware"] = true
+			}
+		}
+	}
+
+	// Scores reported by the scanner are kept for CVEs missing from the data files
+	if best != nil {
+		epssScore := best.EPSS
+		epssPercentile := best.Percentile
+		finding.EPSSScore = &epssScore
+		finding.EPSSPercentile = &epssPercentile
+	}
+
+	return found, nil
+}
+
+// Rescore scores the stored findings with CVEs again, optionally limited to a project
+func (s *ExploitService) Rescore(projectID *uuid.UUID) (*RescoreSummary, error) {
+	s.rescoreMu.Lock()
+	defer s.rescoreMu.Unlock()
+
+	var findings []models.Finding
+	query := s.db.Model(&models.Finding{}).Where("findings.cve_ids <> '[]'::jsonb")
+	if projectID != nil {
+		query = query.Joins("JOIN targets ON findings.target_id = targets.id").
+			Where("targets.project_id = ?", projectID)
+	}
+	if err := query.Find(&findings).Error; err != nil {
+		return nil, err
+	}
+
+	summary := &RescoreSummary{Findings: len(findings)}
+	for i := range findings {
+		found, err := s.Enrich(&findings[i])
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.db.Model(&findings[i]).Select("epss_score", "epss_percentile", "known_exploited", "details").
+			Updates(&findings[i]).Error
+		if err != nil {
+			return nil, err
+		}
+
+		if found {
+			summary.Scored++
+		}
+		if findings[i].KnownExploited {
+			summary.KnownExploited++
+		}
+	}
+
+	return summary, nil
+}
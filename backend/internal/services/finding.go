@@ -2,19 +2,55 @@ package services
 
 import (
 	"backend/internal/models"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+// findingSortOrders are the orders findings can be listed in, scores sort the highest first
+var findingSortOrders = map[string]string{
+	"cvss":          "findings.cvss_score DESC NULLS LAST",
+	"epss":          "findings.epss_score DESC NULLS LAST",
+	"discovered_at": "findings.discovered_at DESC",
+}
+
 type FindingService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	enrichers []FindingEnricher
+}
+
+// FindingEnricher adds data from offline sources to a finding before it is stored.
+// Enrich returns whether anything was known about the finding.
+type FindingEnricher interface {
+	Enrich(finding *models.Finding) (bool, error)
+}
+
+// FindingFilter narrows down finding listings, empty fields don't filter
+type FindingFilter struct {
+	Severity       string
+	FindingType    string
+	Fixed          string
+	CVE            string
+	CWE            string
+	MinCVSS        *float64
+	MinEPSS        *float64
+	KnownExploited *bool
+	Sort           string // cvss, epss or discovered_at
 }
 
 func NewFindingService(db *gorm.DB) *FindingService {
 	return &FindingService{db: db}
 }
 
+// AddEnricher enriches findings with the given source when they are stored
+func (s *FindingService) AddEnricher(enricher FindingEnricher) {
+	s.enrichers = append(s.enrichers, enricher)
+}
+
 // GetAll returns all findings
 func (s *FindingService) GetAll() ([]models.Finding, error) {
 	var findings []models.Finding
@@ -31,11 +67,13 @@ func (s *FindingService) GetByID(id uuid.UUID) (*models.Finding, error) {
 
 // Create creates a new finding
 func (s *FindingService) Create(finding *models.Finding) error {
+	enrichFinding(s.enrichers, finding)
 	return s.db.Create(finding).Error
 }
 
 // Update updates an existing finding
 func (s *FindingService) Update(finding *models.Finding) error {
+	enrichFinding(s.enrichers, finding)
 	return s.db.Save(finding).Error
 }
 
@@ -44,30 +82,57 @@ func (s *FindingService) Delete(id uuid.UUID) error {
 	return s.db.Delete(&models.Finding{}, id).Error
 }
 
-// GetFiltered returns findings filtered by various criteria
-func (s *FindingService) GetFiltered(projectID *uuid.UUID, severity, findingType, fixed string) ([]models.Finding, error) {
+// GetFiltered returns findings filtered by project and the criteria of the filter
+func (s *FindingService) GetFiltered(projectID *uuid.UUID, filter FindingFilter) ([]models.Finding, error) {
 	query := s.db.Model(&models.Finding{})
 
 	if projectID != nil {
-		// Join with scans to filter by project ID
-		query = query.Joins("JOIN scans ON findings.scan_id = scans.id").
-			Where("scans.project_id = ?", projectID)
+		// Join with targets to filter by project ID, findings such as matched CVEs don't belong to a scan
+		query = query.Joins("JOIN targets ON findings.target_id = targets.id").
+			Where("targets.project_id = ?", projectID)
 	}
 
-	if severity != "" {
-		query = query.Where("severity = ?", severity)
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", filter.Severity)
 	}
 
-	if findingType != "" {
-		query = query.Where("finding_type = ?", findingType)
+	if filter.FindingType != "" {
+		query = query.Where("finding_type = ?", filter.FindingType)
 	}
 
-	if fixed == "true" {
+	if filter.Fixed == "true" {
 		query = query.Where("fixed = ?", true)
-	} else if fixed == "false" {
+	} else if filter.Fixed == "false" {
 		query = query.Where("fixed = ?", false)
 	}
 
+	if filter.CVE != "" {
+		value, _ := json.Marshal([]string{strings.ToUpper(strings.TrimSpace(filter.CVE))})
+		query = query.Where("findings.cve_ids @> ?::jsonb", string(value))
+	}
+
+	if filter.CWE != "" {
+		cwe := strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(filter.CWE)), "CWE-")
+		value, _ := json.Marshal([]string{"CWE-" + cwe})
+		query = query.Where("findings.cwe_ids @> ?::jsonb", string(value))
+	}
+
+	if filter.MinCVSS != nil {
+		query = query.Where("findings.cvss_score >= ?", *filter.MinCVSS)
+	}
+
+	if filter.MinEPSS != nil {
+		query = query.Where("findings.epss_score >= ?", *filter.MinEPSS)
+	}
+
+	if filter.KnownExploited != nil {
+		query = query.Where("findings.known_exploited = ?", *filter.KnownExploited)
+	}
+
+	if order, ok := findingSortOrders[filter.Sort]; ok {
+		query = query.Order(order)
+	}
+
 	var findings []models.Finding
 	result := query.Find(&findings)
 	return findings, result.Error
@@ -103,8 +168,8 @@ func (s *FindingService) CountBySeverity(projectID uuid.UUID) (map[string]int64,
 
 	err := s.db.Model(&models.Finding{}).
 		Select("severity, count(*) as count").
-		Joins("JOIN scans ON findings.scan_id = scans.id").
-		Where("scans.project_id = ?", projectID).
+		Joins("JOIN targets ON findings.target_id = targets.id").
+		Where("targets.project_id = ?", projectID).
 		Group("severity").
 		Scan(&results).Error
 
@@ -144,37 +209,101 @@ func (s *FindingService) MarkVerified(id uuid.UUID, verified bool) error {
 	return s.db.Model(&models.Finding{}).Where("id = ?", id).Update("verified", verified).Error
 }
 
-// UpsertFinding creates a finding if it doesn't exist or returns existing one
+// UpsertFinding creates a finding if it doesn't exist or refreshes the existing one
 func (s *FindingService) UpsertFinding(finding *models.Finding) (*models.Finding, error) {
-	// Try to find an existing finding with the same project_id, finding_type, and value
+	// Try to find an existing finding about the same application, service or target with the same
+	// finding_type and fingerprint. Findings about different CVEs are kept apart.
+	cveIDs, err := finding.CVEIDs.Value()
+	if err != nil {
+		return nil, err
+	}
+	query := s.db.Where(
+		"finding_type = ? AND cve_ids = ?::jsonb AND fingerprint = ?",
+		finding.FindingType, cveIDs, finding.Fingerprint,
+	)
+	// Only the most specific owner is matched, so findings on other ports of the host stay apart
+	switch {
+	case finding.ApplicationID != nil:
+		query = query.Where("application_id = ?", finding.ApplicationID)
+	case finding.ServiceID != nil:
+		query = query.Where("service_id = ? AND application_id IS NULL", finding.ServiceID)
+	default:
+		query = query.Where("target_id = ? AND service_id IS NULL AND application_id IS NULL", finding.TargetID)
+	}
+	// A fingerprint identifies the finding whatever its severity, without one the severity tells them apart
+	if finding.Fingerprint == "" {
+		query = query.Where("severity = ?", finding.Severity)
+	}
+
 	var existingFinding models.Finding
-	result := s.db.Where(
-		"(target_id = ? OR service_id = ? OR application_id = ?) AND finding_type = ? AND severity = ?",
-		finding.TargetID, finding.ServiceID, finding.ApplicationID, finding.FindingType, finding.Severity,
-	).First(&existingFinding)
+	result := query.First(&existingFinding)
 
 	if result.Error == nil {
-		// Finding already exists, update metadata if necessary
+		// Finding already exists, refresh it with what the latest scan reported
+		if finding.Title != "" {
+			existingFinding.Title = finding.Title
+		}
+		if finding.Description != "" {
+			existingFinding.Description = finding.Description
+		}
+		existingFinding.Severity = finding.Severity
+
+		// Merge the details, keeping values the latest scan didn't report
 		if finding.Details != nil {
-			// Merge metadata, preferring existing values but adding new ones
+			details := models.JSONB{}
+			for k, v := range existingFinding.Details {
+				details[k] = v
+			}
 			for k, v := range finding.Details {
-				if _, exists := existingFinding.Details[k]; !exists {
-					if existingFinding.Details == nil {
-						existingFinding.Details = models.JSONB{}
-					}
-					existingFinding.Details[k] = v
-				}
+				details[k] = v
 			}
-			s.db.Model(&existingFinding).Update("metadata", existingFinding.Details)
+			existingFinding.Details = details
+		}
+
+		// Fill in vulnerability metadata the finding was first stored without
+		mergeVulnerability(&existingFinding, finding)
+		enrichFinding(s.enrichers, &existingFinding)
+		if err := s.db.Save(&existingFinding).Error; err != nil {
+			return nil, err
 		}
 		return &existingFinding, nil
 	}
 
 	// Finding doesn't exist, create it
-	err := s.db.Create(finding).Error
+	enrichFinding(s.enrichers, finding)
+	err = s.db.Create(finding).Error
 	if err != nil {
 		return nil, err
 	}
 
 	return finding, nil
 }
+
+// mergeVulnerability copies the vulnerability fields of found that existing lacks
+func mergeVulnerability(existing *models.Finding, found *models.Finding) {
+	if len(existing.CWEIDs) == 0 && len(found.CWEIDs) > 0 {
+		existing.CWEIDs = found.CWEIDs
+	}
+	if existing.CVSSScore == nil && found.CVSSScore != nil {
+		existing.CVSSScore = found.CVSSScore
+		existing.CVSSVersion = found.CVSSVersion
+		existing.CVSSVector = found.CVSSVector
+	}
+	if len(existing.References) == 0 && len(found.References) > 0 {
+		existing.References = found.References
+	}
+	if existing.EPSSScore == nil && found.EPSSScore != nil {
+		existing.EPSSScore = found.EPSSScore
+		existing.EPSSPercentile = found.EPSSPercentile
+	}
+}
+
+// enrichFinding runs the enrichers on a finding, lookups failing never prevent it from being stored
+func enrichFinding(enrichers []FindingEnricher, finding *models.Finding) {
+	for _, enricher := range enrichers {
+		_, err := enricher.Enrich(finding)
+		if err != nil && !errors.Is(err, ErrNoExploitData) {
+			log.Printf("Failed to enrich finding %s: %v", finding.Title, err)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+// internal/services/geoip.go
+package services
+
+import (
+	"backend/internal/geoip"
+	"backend/internal/models"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// geoipMetadataKeys are the target metadata keys written by the enrichment
+var geoipMetadataKeys = []string{"asn", "asn_org", "country", "country_name", "network", "geoip_enriched_at"}
+
+// ErrNoGeoIPDatabases is returned when enrichment is requested before any database was loaded
+var ErrNoGeoIPDatabases = errors.New("no GeoIP or ASN databases loaded")
+
+// GeoIPService enriches IP targets with ASN, organization, country and network from offline databases
+type GeoIPService struct {
+	db        *gorm.DB
+	databases *dataSource[geoip.Database]
+}
+
+// GeoIPStatus describes the loaded databases
+type GeoIPStatus struct {
+	Directory string               `json:"directory"`
+	Loaded    bool                 `json:"loaded"`
+	LoadedAt  *time.Time           `json:"loaded_at,omitempty"`
+	Files     []geoip.DatabaseFile `json:"files"`
+}
+
+// EnrichmentSummary describes the outcome of enriching the targets of a project
+type EnrichmentSummary struct {
+	Targets  int `json:"targets"`
+	Enriched int `json:"enriched"`
+	NotFound int `json:"not_found"`
+}
+
+func NewGeoIPService(db *gorm.DB, dir string) *GeoIPService {
+	databases := newDataSource("GeoIP and ASN databases", dir, geoip.IsDatabaseFile, geoip.Load, func(database *geoip.Database) string {
+		return fmt.Sprintf("%d GeoIP and ASN databases", len(database.Files))
+	})
+	return &GeoIPService{db: db, databases: databases}
+}
+
+// LoadDatabases loads the databases from the database directory. Unless forced, databases are only
+// reloaded when files were added, removed or modified. Returns whether new databases were loaded.
+func (s *GeoIPService) LoadDatabases(force bool) (bool, error) {
+	return s.databases.Load(force)
+}
+
+// Status returns the state of the loaded databases
+func (s *GeoIPService) Status() GeoIPStatus {
+	status := GeoIPStatus{
+		Directory: s.databases.dir,
+		Files:     []geoip.DatabaseFile{},
+	}
+	if database := s.databases.Get(); database != nil {
+		loadedAt := database.LoadedAt
+		status.Loaded = true
+		status.LoadedAt = &loadedAt
+		status.Files = database.Files
+	}
+	return status
+}
+
+// Enrich looks up an IP target and writes the result into its metadata, without saving it.
+// Returns whether the target was found in the databases.
+func (s *GeoIPService) Enrich(target *models.Target) (bool, error) {
+	if target.TargetType != models.TargetTypeIP {
+		return false, nil
+	}
+
+	database := s.databases.Get()
+	if database == nil {
+		return false, ErrNoGeoIPDatabases
+	}
+
+	result, err := database.Lookup(target.Value)
+	if err != nil {
+		return false, err
+	}
+
+	// Copy the metadata since targets created together may share the same map
+	metadata := models.JSONB{}
+	for k, v := range target.Metadata {
+		metadata[k] = v
+	}
+
+	// Drop what an earlier lookup found so entries removed from the databases don't linger
+	for _, key := range geoipMetadataKeys {
+		delete(metadata, key)
+	}
+	target.Metadata = metadata
+
+	if result == nil {
+		return false, nil
+	}
+
+	if result.ASN != 0 {
+		metadata["asn"] = result.ASN
+	}
+	if result.Organization != "" {
+		metadata["asn_org"] = result.Organization
+	}
+	if result.Country != "" {
+		metadata["country"] = result.Country
+	}
+	if result.CountryName != "" {
+		metadata["country_name"] = result.CountryName
+	}
+	if result.Network != "" {
+		metadata["network"] = result.Network
+	}
+	metadata["geoip_enriched_at"] = time.Now().Format(time.RFC3339)
+
+	return true, nil
+}
+
+// EnrichProject looks up every IP target of a project and stores the results in their metadata
+func (s *GeoIPService) EnrichProject(projectID uuid.UUID) (*EnrichmentSummary, error) {
+	summary := &EnrichmentSummary{}
+	targets, err := enrichProjectTargets(s.db, projectID, s, ErrNoGeoIPDatabases, func(target *models.Target, found bool) {
+		if found {
+			summary.Enriched++
+		} else {
+			summary.NotFound++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	summary.Targets = targets
+	return summary, nil
+}
@@ -0,0 +1,305 @@
+// internal/services/nuclei_template.go
+package services
+
+import (
+	"backend/internal/models"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+	"gorm.io/gorm"
+)
+
+// MaxNucleiTemplateSize caps the size of an uploaded template
+const MaxNucleiTemplateSize = 512 << 10
+
+// ErrInvalidNucleiTemplate is returned when an uploaded template can't be run by nuclei
+var ErrInvalidNucleiTemplate = errors.New("invalid nuclei template")
+
+// ErrNucleiTemplateExists is returned when a template with the same id is already stored
+var ErrNucleiTemplateExists = errors.New("a nuclei template with this id already exists")
+
+// nucleiTemplateIDPattern is the format nuclei accepts for template ids
+var nucleiTemplateIDPattern = regexp.MustCompile(`^([a-zA-Z0-9]+[-_])*[a-zA-Z0-9]+$`)
+
+// nucleiSeverities are the severities nuclei accepts in a template
+var nucleiSeverities = []string{"info", "low", "medium", "high", "critical", "unknown"}
+
+// nucleiProtocols are the request sections a template can run, one of them is required
+var nucleiProtocols = []string{"http", "requests", "dns", "file", "network", "tcp", "headless", "ssl", "websocket", "whois", "javascript"}
+
+// nucleiRejectedSections can't be run from an uploaded template. Code templates execute commands on the
+// worker and workflows refer to other templates by path.
+var nucleiRejectedSections = map[string]string{
+	"code":      "code templates run commands on the workers and can't be uploaded",
+	"workflows": "workflows refer to templates by path and can't be uploaded",
+}
+
+// nucleiTemplateFile holds the fields of a template that are stored alongside it
+type nucleiTemplateFile struct {
+	ID   string `yaml:"id"`
+	Info struct {
+		Name        string      `yaml:"name"`
+		Author      interface{} `yaml:"author"` // A string or a list
+		Severity    string      `yaml:"severity"`
+		Description string      `yaml:"description"`
+		Tags        interface{} `yaml:"tags"` // A comma separated string or a list
+	} `yaml:"info"`
+}
+
+// NucleiTemplateFilter narrows down template listings, empty fields don't filter
+type NucleiTemplateFilter struct {
+	Tag      string
+	Severity string
+}
+
+type NucleiTemplateService struct {
+	db *gorm.DB
+}
+
+func NewNucleiTemplateService(db *gorm.DB) *NucleiTemplateService {
+	return &NucleiTemplateService{db: db}
+}
+
+// GetAll returns the stored templates without their content
+func (s *NucleiTemplateService) GetAll(filter NucleiTemplateFilter) ([]models.NucleiTemplate, error) {
+	query := s.db.Omit("content").Order("template_id")
+
+	if filter.Tag != "" {
+		query = withTag(query, filter.Tag)
+	}
+	if filter.Severity != "" {
+		query = query.Where("severity = ?", strings.ToLower(filter.Severity))
+	}
+
+	var templates []models.NucleiTemplate
+	result := query.Find(&templates)
+	return templates, result.Error
+}
+
+// GetByID returns a specific template by ID
+func (s *NucleiTemplateService) GetByID(id uuid.UUID) (*models.NucleiTemplate, error) {
+	var template models.NucleiTemplate
+	result := s.db.First(&template, id)
+	return &template, result.Error
+}
+
+// Create validates and stores a template
+func (s *NucleiTemplateService) Create(content string) (*models.NucleiTemplate, error) {
+	template, err := ParseNucleiTemplate(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUnique(template.TemplateID, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	if err := s.db.Create(template).Error; err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Update replaces the content of a stored template
+func (s *NucleiTemplateService) Update(id uuid.UUID, content string) (*models.NucleiTemplate, error) {
+	existing, err := s.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	template, err := ParseNucleiTemplate(content)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkUnique(template.TemplateID, id); err != nil {
+		return nil, err
+	}
+
+	template.ID = existing.ID
+	template.CreatedAt = existing.CreatedAt
+	if err := s.db.Save(template).Error; err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// Delete deletes a template
+func (s *NucleiTemplateService) Delete(id uuid.UUID) error {
+	return s.db.Delete(&models.NucleiTemplate{}, id).Error
+}
+
+// Resolve returns the templates referenced by template id or selected by tag, with their content.
+// Every referenced id has to exist, tags may select no templates.
+func (s *NucleiTemplateService) Resolve(templateIDs []string, tags []string) ([]models.NucleiTemplate, error) {
+	selected := make(map[string]models.NucleiTemplate)
+
+	if len(templateIDs) > 0 {
+		var templates []models.NucleiTemplate
+		if err := s.db.Where("template_id IN ?", templateIDs).Find(&templates).Error; err != nil {
+			return nil, err
+		}
+		for _, template := range templates {
+			selected[template.TemplateID] = template
+		}
+		for _, templateID := range templateIDs {
+			if _, ok := selected[templateID]; !ok {
+				return nil, fmt.Errorf("custom nuclei template %s not found", templateID)
+			}
+		}
+	}
+
+	for _, tag := range tags {
+		var templates []models.NucleiTemplate
+		if err := withTag(s.db, tag).Find(&templates).Error; err != nil {
+			return nil, err
+		}
+		for _, template := range templates {
+			selected[template.TemplateID] = template
+		}
+	}
+
+	result := make([]models.NucleiTemplate, 0, len(selected))
+	for _, template := range selected {
+		result = append(result, template)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].TemplateID < result[j].TemplateID
+	})
+	return result, nil
+}
+
+// withTag narrows a query down to templates with the tag
+func withTag(query *gorm.DB, tag string) *gorm.DB {
+	value, _ := json.Marshal([]string{strings.ToLower(strings.TrimSpace(tag))})
+	return query.Where("tags @> ?::jsonb", string(value))
+}
+
+// checkUnique makes sure no other stored template uses the template id
+func (s *NucleiTemplateService) checkUnique(templateID string, id uuid.UUID) error {
+	var count int64
+	query := s.db.Model(&models.NucleiTemplate{}).Where("template_id = ?", templateID)
+	if id != uuid.Nil {
+		query = query.Where("id <> ?", id)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return ErrNucleiTemplateExists
+	}
+	return nil
+}
+
+// ParseNucleiTemplate validates a template and reads its id, info block and protocols
+func ParseNucleiTemplate(content string) (*models.NucleiTemplate, error) {
+	if strings.TrimSpace(content) == "" {
+		return nil, fmt.Errorf("%w: the template is empty", ErrInvalidNucleiTemplate)
+	}
+	if len(content) > MaxNucleiTemplateSize {
+		return nil, fmt.Errorf("%w: the template is larger than %d bytes", ErrInvalidNucleiTemplate, MaxNucleiTemplateSize)
+	}
+
+	var sections map[string]interface{}
+	if err := yaml.Unmarshal([]byte(content), &sections); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNucleiTemplate, err)
+	}
+	var file nucleiTemplateFile
+	if err := yaml.Unmarshal([]byte(content), &file); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidNucleiTemplate, err)
+	}
+
+	if !nucleiTemplateIDPattern.MatchString(file.ID) {
+		return nil, fmt.Errorf("%w: id must be letters and digits separated by - or _", ErrInvalidNucleiTemplate)
+	}
+	if strings.TrimSpace(file.Info.Name) == "" {
+		return nil, fmt.Errorf("%w: info.name is required", ErrInvalidNucleiTemplate)
+	}
+
+	authors := yamlStringList(file.Info.Author)
+	if len(authors) == 0 {
+		return nil, fmt.Errorf("%w: info.author is required", ErrInvalidNucleiTemplate)
+	}
+
+	severity := strings.ToLower(strings.TrimSpace(file.Info.Severity))
+	if !containsValue(nucleiSeverities, severity) {
+		return nil, fmt.Errorf("%w: info.severity must be one of %s", ErrInvalidNucleiTemplate, strings.Join(nucleiSeverities, ", "))
+	}
+
+	for section, reason := range nucleiRejectedSections {
+		if _, ok := sections[section]; ok {
+			return nil, fmt.Errorf("%w: %s", ErrInvalidNucleiTemplate, reason)
+		}
+	}
+
+	var protocols []string
+	for _, protocol := range nucleiProtocols {
+		if _, ok := sections[protocol]; ok {
+			protocols = append(protocols, protocol)
+		}
+	}
+	if len(protocols) == 0 {
+		return nil, fmt.Errorf("%w: the template has no requests, add one of %s", ErrInvalidNucleiTemplate, strings.Join(nucleiProtocols, ", "))
+	}
+
+	tags := []string{}
+	for _, tag := range yamlStringList(file.Info.Tags) {
+		tag = strings.ToLower(tag)
+		if !containsValue(tags, tag) {
+			tags = append(tags, tag)
+		}
+	}
+
+	checksum := sha256.Sum256([]byte(content))
+	return &models.NucleiTemplate{
+		TemplateID:  file.ID,
+		Name:        strings.TrimSpace(file.Info.Name),
+		Author:      strings.Join(authors, ", "),
+		Severity:    severity,
+		Description: strings.TrimSpace(file.Info.Description),
+		Tags:        tags,
+		Protocols:   protocols,
+		Content:     content,
+		Checksum:    hex.EncodeToString(checksum[:]),
+	}, nil
+}
+
+// yamlStringList reads a YAML value written as a comma separated string or as a list
+func yamlStringList(value interface{}) []string {
+	var values []string
+	switch value := value.(type) {
+	case string:
+		values = strings.Split(value, ",")
+	case []interface{}:
+		for _, item := range value {
+			if str, ok := item.(string); ok {
+				values = append(values, str)
+			}
+		}
+	}
+
+	var list []string
+	for _, item := range values {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func containsValue(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -3,6 +3,7 @@ package services
 
 import (
 	"backend/internal/models"
+	"encoding/json"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -123,7 +124,8 @@ func (s *ServiceService) UpsertService(service *models.Service) (*models.Service
 			updates["banner"] = service.Banner
 		}
 
-		// Merge the raw_info, keeping existing data but adding new
+		// Merge the raw_info, refreshing values from the latest scan but keeping
+		// the original discovery time
 		if service.RawInfo != nil {
 			mergedInfo := existingService.RawInfo
 			if mergedInfo == nil {
@@ -131,9 +133,10 @@ func (s *ServiceService) UpsertService(service *models.Service) (*models.Service
 			}
 
 			for k, v := range service.RawInfo {
-				if _, exists := mergedInfo[k]; !exists {
-					mergedInfo[k] = v
+				if _, exists := mergedInfo[k]; exists && k == "discovered_at" {
+					continue
 				}
+				mergedInfo[k] = v
 			}
 
 			updates["raw_info"] = mergedInfo
@@ -165,3 +168,18 @@ func (s *ServiceService) FindByTargetAndPort(targetID uuid.UUID, port int, proto
 	).First(&service)
 	return &service, result.Error
 }
+
+// FindByHostKeyFingerprint returns the services of a project that presented a host key with the given fingerprint
+func (s *ServiceService) FindByHostKeyFingerprint(projectID uuid.UUID, fingerprint string) ([]models.Service, error) {
+	fingerprints, err := json.Marshal([]string{fingerprint})
+	if err != nil {
+		return nil, err
+	}
+
+	var services []models.Service
+	result := s.db.Joins("JOIN targets ON services.target_id = targets.id").
+		Where("targets.project_id = ?", projectID).
+		Where("services.raw_info->'host_key_fingerprints' @> CAST(? AS jsonb)", string(fingerprints)).
+		Find(&services)
+	return services, result.Error
+}
@@ -3,19 +3,45 @@ package services
 
 import (
 	"backend/internal/models"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type TargetService struct {
-	db *gorm.DB
+	db        *gorm.DB
+	enrichers []TargetEnricher
+}
+
+// TargetEnricher adds data from offline sources to a target's metadata before it is stored.
+// Enrich returns whether anything was known about the target.
+type TargetEnricher interface {
+	Enrich(target *models.Target) (bool, error)
+}
+
+// TargetFilter narrows down target listings, empty fields don't filter
+type TargetFilter struct {
+	TargetType   string
+	ASN          uint
+	Organization string // Case-insensitive substring of the AS organization
+	Country      string
+	Provider     string // Cloud or CDN provider
+	CDN          *bool
 }
 
 func NewTargetService(db *gorm.DB) *TargetService {
 	return &TargetService{db: db}
 }
 
+// AddEnricher enriches targets with the given source when they are created
+func (s *TargetService) AddEnricher(enricher TargetEnricher) {
+	s.enrichers = append(s.enrichers, enricher)
+}
+
 // GetAll returns all targets
 func (s *TargetService) GetAll() ([]models.Target, error) {
 	var targets []models.Target
@@ -37,8 +63,46 @@ func (s *TargetService) GetByProjectID(projectID uuid.UUID) ([]models.Target, er
 	return targets, result.Error
 }
 
+// GetFiltered returns targets with optional filtering on project, type and the enrichment metadata
+func (s *TargetService) GetFiltered(projectID *uuid.UUID, filter TargetFilter) ([]models.Target, error) {
+	query := s.db.Model(&models.Target{})
+
+	if projectID != nil {
+		query = query.Preload("Findings").Preload("Services").Where("project_id = ?", projectID)
+	}
+
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+
+	if filter.ASN != 0 {
+		query = query.Where("metadata->>'asn' = ?", strconv.FormatUint(uint64(filter.ASN), 10))
+	}
+
+	if filter.Organization != "" {
+		query = query.Where("metadata->>'asn_org' ILIKE ?", "%"+filter.Organization+"%")
+	}
+
+	if filter.Country != "" {
+		query = query.Where("UPPER(metadata->>'country') = ?", strings.ToUpper(filter.Country))
+	}
+
+	if filter.Provider != "" {
+		query = query.Where("metadata->>'cloud_provider' = ?", strings.ToLower(filter.Provider))
+	}
+
+	if filter.CDN != nil {
+		query = query.Where("COALESCE(metadata->>'cdn', 'false') = ?", strconv.FormatBool(*filter.CDN))
+	}
+
+	var targets []models.Target
+	result := query.Find(&targets)
+	return targets, result.Error
+}
+
 // Create creates a new target
 func (s *TargetService) Create(target *models.Target) error {
+	s.enrich(target)
 	return s.db.Create(target).Error
 }
 
@@ -68,6 +132,9 @@ func (s *TargetService) GetByType(projectID uuid.UUID, targetType string) ([]mod
 
 // BulkCreate creates multiple targets at once
 func (s *TargetService) BulkCreate(targets []models.Target) error {
+	for i := range targets {
+		s.enrich(&targets[i])
+	}
 	return s.db.Create(&targets).Error
 }
 
@@ -208,6 +275,7 @@ func (s *TargetService) UpsertTarget(target *models.Target) (*models.Target, err
 	}
 
 	// Target doesn't exist, create it
+	s.enrich(target)
 	err := s.db.Create(target).Error
 	if err != nil {
 		return nil, err
@@ -216,6 +284,23 @@ func (s *TargetService) UpsertTarget(target *models.Target) (*models.Target, err
 	return target, nil
 }
 
+// UpdateMetadata merges the given keys into a target's metadata, overwriting existing values
+func (s *TargetService) UpdateMetadata(id uuid.UUID, metadata models.JSONB) error {
+	var target models.Target
+	if err := s.db.First(&target, id).Error; err != nil {
+		return err
+	}
+
+	if target.Metadata == nil {
+		target.Metadata = models.JSONB{}
+	}
+	for k, v := range metadata {
+		target.Metadata[k] = v
+	}
+
+	return s.db.Model(&target).Update("metadata", target.Metadata).Error
+}
+
 // FindByTypeAndValue finds a target by type and value in a specific project
 func (s *TargetService) FindByTypeAndValue(projectID uuid.UUID, targetType, value string) (*models.Target, error) {
 	var target models.Target
@@ -225,3 +310,41 @@ func (s *TargetService) FindByTypeAndValue(projectID uuid.UUID, targetType, valu
 	).First(&target)
 	return &target, result.Error
 }
+
+// enrich runs the enrichers on a new target, lookups failing never prevent it from being created
+func (s *TargetService) enrich(target *models.Target) {
+	for _, enricher := range s.enrichers {
+		_, err := enricher.Enrich(target)
+		if err != nil && !errors.Is(err, ErrNoGeoIPDatabases) && !errors.Is(err, ErrNoCloudRanges) {
+			log.Printf("Failed to enrich target %s: %v", target.Value, err)
+		}
+	}
+}
+
+// enrichProjectTargets runs an enricher on every IP target of a project and stores their metadata,
+// reporting to counted whether each target was found. Returns the number of targets. Failed lookups
+// are logged and skipped, except noData which means nothing is loaded to look them up in.
+func enrichProjectTargets(db *gorm.DB, projectID uuid.UUID, enricher TargetEnricher, noData error, counted func(target *models.Target, found bool)) (int, error) {
+	var targets []models.Target
+	if err := db.Where("project_id = ? AND target_type = ?", projectID, models.TargetTypeIP).Find(&targets).Error; err != nil {
+		return 0, err
+	}
+
+	for i := range targets {
+		found, err := enricher.Enrich(&targets[i])
+		if err != nil {
+			if errors.Is(err, noData) {
+				return 0, err
+			}
+			log.Printf("Failed to enrich target %s: %v", targets[i].Value, err)
+			continue
+		}
+
+		if err := db.Model(&targets[i]).Update("metadata", targets[i].Metadata).Error; err != nil {
+			return 0, err
+		}
+		counted(&targets[i], found)
+	}
+
+	return len(targets), nil
+}
@@ -0,0 +1,454 @@
+// internal/services/vulnerability.go
+package services
+
+import (
+	"backend/internal/cve"
+	"backend/internal/models"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VulnerabilityFindingType is the finding type of components matched to known CVEs
+const VulnerabilityFindingType = "vulnerable_version"
+
+// Limits on how much of the matched CVEs is stored on a finding
+const (
+	maxFindingVulnerabilities = 50
+	maxFindingReferences      = 25
+)
+
+// ErrNoFeeds is returned when matching is requested before any CVE feed was loaded
+var ErrNoFeeds = errors.New("no CVE feeds loaded")
+
+// VulnerabilityService matches service and application versions against offline NVD feeds
+type VulnerabilityService struct {
+	db    *gorm.DB
+	feeds *dataSource[cve.Database]
+
+	enrichers []FindingEnricher
+
+	// recomputeMu lets recomputes of different projects run side by side, while a recompute
+	// of every project runs alone
+	recomputeMu sync.RWMutex
+
+	// projectLocks serializes the recomputes of each project, so overlapping runs can't both
+	// create a finding for the same component or resolve one the other run just stored
+	projectLocksMu sync.Mutex
+	projectLocks   map[uuid.UUID]*sync.Mutex
+}
+
+// FeedStatus describes the loaded CVE feeds
+type FeedStatus struct {
+	Directory       string         `json:"directory"`
+	Loaded          bool           `json:"loaded"`
+	LoadedAt        *time.Time     `json:"loaded_at,omitempty"`
+	Vulnerabilities int            `json:"vulnerabilities"`
+	Files           []cve.FeedFile `json:"files"`
+}
+
+// RecomputeSummary describes the outcome of matching components against the feeds
+type RecomputeSummary struct {
+	Components int `json:"components"`
+	Created    int `json:"created"`
+	Updated    int `json:"updated"`
+	Resolved   int `json:"resolved"`
+}
+
+// component is a service or application to match, with the keys needed to store its finding
+type component struct {
+	key           string
+	componentType string
+	name          string
+	targetID      uuid.UUID
+	serviceID     *uuid.UUID
+	applicationID *uuid.UUID
+	match         cve.Component
+}
+
+func NewVulnerabilityService(db *gorm.DB, feedDir string) *VulnerabilityService {
+	feeds := newDataSource("CVE feeds", feedDir, cve.IsFeedFile, cve.Load, func(database *cve.Database) string {
+		return fmt.Sprintf("%d CVEs from %d feed files", database.Size(), len(database.Files))
+	})
+	return &VulnerabilityService{db: db, feeds: feeds, projectLocks: make(map[uuid.UUID]*sync.Mutex)}
+}
+
+// AddEnricher enriches the vulnerable version findings with the given source when they are stored
+func (s *VulnerabilityService) AddEnricher(enricher FindingEnricher) {
+	s.enrichers = append(s.enrichers, enricher)
+}
+
+// LoadFeeds loads the feeds from the feed directory. Unless forced, feeds are only reloaded
+// when files were added, removed or modified. Returns whether a new database was loaded.
+func (s *VulnerabilityService) LoadFeeds(force bool) (bool, error) {
+	return s.feeds.Load(force)
+}
+
+// Status returns the state of the loaded feeds
+func (s *VulnerabilityService) Status() FeedStatus {
+	status := FeedStatus{
+		Directory: s.feeds.dir,
+		Files:     []cve.FeedFile{},
+	}
+	if database := s.feeds.Get(); database != nil {
+		loadedAt := database.LoadedAt
+		status.Loaded = true
+		status.LoadedAt = &loadedAt
+		status.Vulnerabilities = database.Size()
+		status.Files = database.Files
+	}
+	return status
+}
+
+// WatchFeeds reloads the feeds when the feed directory changes and recomputes every project's findings
+func (s *VulnerabilityService) WatchFeeds(ctx context.Context, interval time.Duration) {
+	s.feeds.Watch(ctx, interval, func() {
+		summary, err := s.RecomputeAll()
+		if err != nil {
+			log.Printf("Failed to recompute vulnerable versions: %v", err)
+			return
+		}
+		log.Printf("Recomputed vulnerable versions after feed refresh: %d components, %d created, %d updated, %d resolved",
+			summary.Components, summary.Created, summary.Updated, summary.Resolved)
+	})
+}
+
+// RecomputeAll matches the components of every project against the feeds
+func (s *VulnerabilityService) RecomputeAll() (*RecomputeSummary, error) {
+	return s.recompute(nil)
+}
+
+// RecomputeProject matches the components of a project against the feeds
+func (s *VulnerabilityService) RecomputeProject(projectID uuid.UUID) (*RecomputeSummary, error) {
+	return s.recompute(&projectID)
+}
+
+// recompute creates or updates a finding for every vulnerable component and resolves the
+// findings of components that no longer match
+func (s *VulnerabilityService) recompute(projectID *uuid.UUID) (*RecomputeSummary, error) {
+	database := s.feeds.Get()
+	if database == nil {
+		return nil, ErrNoFeeds
+	}
+
+	unlock := s.lockRecompute(projectID)
+	defer unlock()
+
+	components, err := s.components(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := s.existingFindings(projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &RecomputeSummary{Components: len(components)}
+	matchedAt := time.Now().Format(time.RFC3339)
+	matched := make(map[string]bool)
+
+	for _, c := range components {
+		match := database.Match(c.match)
+		if match == nil || len(match.Vulnerabilities) == 0 {
+			continue
+		}
+		matched[c.key] = true
+
+		finding := s.buildFinding(c, match, matchedAt)
+		enrichFinding(s.enrichers, finding)
+		if current, ok := existing[c.key]; ok {
+			finding.ID = current.ID
+			finding.ScanID = current.ScanID
+			finding.DiscoveredAt = current.DiscoveredAt
+			finding.Verified = current.Verified
+			finding.Manual = current.Manual
+
+			// Reopen findings resolved by an earlier recompute, but keep findings fixed by hand
+			finding.Fixed = current.Fixed && current.Details["resolved_at"] == nil
+			if err := s.db.Save(finding).Error; err != nil {
+				return nil, err
+			}
+			summary.Updated++
+			continue
+		}
+
+		if err := s.db.Create(finding).Error; err != nil {
+			return nil, err
+		}
+		summary.Created++
+	}
+
+	for key, finding := range existing {
+		if matched[key] || finding.Fixed {
+			continue
+		}
+		finding.Fixed = true
+		finding.Details["resolved_at"] = matchedAt
+		finding.Details["resolution"] = "Version no longer matches any CVE in the loaded feeds"
+		if err := s.db.Save(finding).Error; err != nil {
+			return nil, err
+		}
+		summary.Resolved++
+	}
+
+	return summary, nil
+}
+
+// lockRecompute waits until no other recompute covers the project, or any project when projectID
+// is nil, and returns the function releasing the lock
+func (s *VulnerabilityService) lockRecompute(projectID *uuid.UUID) func() {
+	if projectID == nil {
+		s.recomputeMu.Lock()
+		return s.recomputeMu.Unlock
+	}
+
+	s.projectLocksMu.Lock()
+	projectLock, ok := s.projectLocks[*projectID]
+	if !ok {
+		projectLock = &sync.Mutex{}
+		s.projectLocks[*projectID] = projectLock
+	}
+	s.projectLocksMu.Unlock()
+
+	s.recomputeMu.RLock()
+	projectLock.Lock()
+	return func() {
+		projectLock.Unlock()
+		s.recomputeMu.RUnlock()
+	}
+}
+
+// components collects the services and applications with a version, optionally limited to a project
+func (s *VulnerabilityService) components(projectID *uuid.UUID) ([]component, error) {
+	var services []models.Service
+	query := s.db.Model(&models.Service{})
+	if projectID != nil {
+		query = query.Joins("JOIN targets ON services.target_id = targets.id").
+			Where("targets.project_id = ?", projectID)
+	}
+	if err := query.Find(&services).Error; err != nil {
+		return nil, err
+	}
+
+	var applications []models.Application
+	query = s.db.Model(&models.Application{}).Where("version <> '' AND host_target IS NOT NULL")
+	if projectID != nil {
+		query = query.Where("project_id = ?", projectID)
+	}
+	if err := query.Find(&applications).Error; err != nil {
+		return nil, err
+	}
+
+	var components []component
+	for _, service := range services {
+		product, _ := service.RawInfo["product"].(string)
+		version := service.Version
+		if rawVersion, ok := service.RawInfo["version"].(string); ok && rawVersion != "" {
+			version = rawVersion
+		}
+		cpes := stringValues(service.RawInfo["cpe"])
+		if (product == "" && len(cpes) == 0) || (version == "" && len(cpes) == 0) {
+			continue
+		}
+
+		serviceID := service.ID
+		name := product
+		if name == "" {
+			name = service.ServiceName
+		}
+		components = append(components, component{
+			key:           "service:" + service.ID.String(),
+			componentType: "service",
+			name:          fmt.Sprintf("%s on port %d", name, service.Port),
+			targetID:      service.TargetID,
+			serviceID:     &serviceID,
+			match:         cve.Component{Product: product, Version: version, CPEs: cpes},
+		})
+	}
+
+	for _, application := range applications {
+		applicationID := application.ID
+		product := application.Name
+		if product == "" {
+			product = strings.ReplaceAll(application.Type, "-", " ")
+		}
+		components = append(components, component{
+			key:           "application:" + application.ID.String(),
+			componentType: "application",
+			name:          application.Name,
+			targetID:      *application.HostTarget,
+			serviceID:     application.ServiceID,
+			applicationID: &applicationID,
+			match: cve.Component{
+				Product: product,
+				Version: application.Version,
+				CPEs:    stringValues(application.Metadata["cpe"]),
+			},
+		})
+	}
+
+	return components, nil
+}
+
+// existingFindings returns the stored vulnerable version findings keyed by component
+func (s *VulnerabilityService) existingFindings(projectID *uuid.UUID) (map[string]*models.Finding, error) {
+	var findings []models.Finding
+	query := s.db.Model(&models.Finding{}).Where("findings.finding_type = ?", VulnerabilityFindingType)
+	if projectID != nil {
+		query = query.Joins("JOIN targets ON findings.target_id = targets.id").
+			Where("targets.project_id = ?", projectID)
+	}
+	if err := query.Find(&findings).Error; err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]*models.Finding)
+	for i := range findings {
+		key, _ := findings[i].Details["component"].(string)
+		if key == "" {
+			continue
+		}
+		existing[key] = &findings[i]
+	}
+	return existing, nil
+}
+
+// buildFinding creates the finding for a component matching one or more CVEs
+func (s *VulnerabilityService) buildFinding(c component, match *cve.Match, matchedAt string) *models.Finding {
+	top := match.Vulnerabilities[0]
+
+	cveIDs := make([]string, 0, len(match.Vulnerabilities))
+	vulnerabilities := make([]map[string]interface{}, 0, maxFindingVulnerabilities)
+	var references, cwes []string
+	seenReferences := make(map[string]bool)
+	seenCWEs := make(map[string]bool)
+	for i, vulnerability := range match.Vulnerabilities {
+		cveIDs = append(cveIDs, vulnerability.ID)
+		for _, cwe := range vulnerability.CWEs {
+			if !seenCWEs[cwe] {
+				seenCWEs[cwe] = true
+				cwes = append(cwes, cwe)
+			}
+		}
+		if i >= maxFindingVulnerabilities {
+			continue
+		}
+		vulnerabilities = append(vulnerabilities, map[string]interface{}{
+			"id":           vulnerability.ID,
+			"description":  vulnerability.Description,
+			"cvss_score":   vulnerability.CVSSScore,
+			"cvss_version": vulnerability.CVSSVersion,
+			"cvss_vector":  vulnerability.CVSSVector,
+			"published":    vulnerability.Published,
+		})
+		for _, reference := range vulnerability.References {
+			if len(references) < maxFindingReferences && !seenReferences[reference] {
+				seenReferences[reference] = true
+				references = append(references, reference)
+			}
+		}
+	}
+
+	title := fmt.Sprintf("%s %s is affected by %s", match.Product, match.Version, top.ID)
+	if len(cveIDs) > 1 {
+		title = fmt.Sprintf("%s %s is affected by %d known vulnerabilities", match.Product, match.Version, len(cveIDs))
+	}
+
+	description := fmt.Sprintf("%s (%s) matches %s. The most severe is %s (CVSS %.1f): %s",
+		c.name, match.CPE, strings.Join(limitStrings(cveIDs, 10), ", "), top.ID, top.CVSSScore, top.Description)
+
+	var cvssScore *float64
+	if top.CVSSVector != "" {
+		score := top.CVSSScore
+		cvssScore = &score
+	}
+
+	return &models.Finding{
+		ID:            uuid.New(),
+		TargetID:      c.targetID,
+		ServiceID:     c.serviceID,
+		ApplicationID: c.applicationID,
+		Title:         truncate(title, 255),
+		Description:   description,
+		Severity:      severityForCVSS(top.CVSSScore),
+		FindingType:   VulnerabilityFindingType,
+		DiscoveredAt:  time.Now(),
+		Details: models.JSONB{
+			"component":       c.key,
+			"component_type":  c.componentType,
+			"product":         match.Product,
+			"vendor":          match.Vendor,
+			"version":         match.Version,
+			"cpe":             match.CPE,
+			"cve_count":       len(cveIDs),
+			"vulnerabilities": vulnerabilities,
+			"matched_at":      matchedAt,
+			"source":          "nvd",
+		},
+		CVEIDs:      cveIDs,
+		CWEIDs:      cwes,
+		CVSSVersion: top.CVSSVersion,
+		CVSSVector:  top.CVSSVector,
+		CVSSScore:   cvssScore,
+		References:  references,
+	}
+}
+
+// severityForCVSS maps a CVSS base score to a finding severity using the CVSS v3 ratings
+func severityForCVSS(score float64) string {
+	switch {
+	case score >= 9.0:
+		return models.SeverityCritical
+	case score >= 7.0:
+		return models.SeverityHigh
+	case score >= 4.0:
+		return models.SeverityMedium
+	case score > 0:
+		return models.SeverityLow
+	default:
+		return models.SeverityUnknown
+	}
+}
+
+// stringValues converts a string or list of strings stored in a JSONB column
+func stringValues(value interface{}) []string {
+	switch v := value.(type) {
+	case string:
+		if v != "" {
+			return []string{v}
+		}
+	case []string:
+		return v
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+func limitStrings(values []string, limit int) []string {
+	if len(values) <= limit {
+		return values
+	}
+	return append(values[:limit:limit], fmt.Sprintf("and %d more", len(values)-limit))
+}
+
+func truncate(value string, length int) string {
+	if len(value) <= length {
+		return value
+	}
+	return value[:length-3] + "..."
+}
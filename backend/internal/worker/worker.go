@@ -7,7 +7,10 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"os"
+	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"backend/internal/models"
@@ -17,6 +20,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// maxCrawledEndpoints caps the crawled endpoints passed to a scanner for a single target or service
+const maxCrawledEndpoints = 500
+
 // Worker manages scan jobs
 type Worker struct {
 	queueService       *services.QueueService
@@ -26,7 +32,9 @@ type Worker struct {
 	serviceService     *services.ServiceService
 	dnsRecordService   *services.DNSRecordService
 	certificateService *services.CertificateService
+	templateService    *services.NucleiTemplateService
 	activeScans        map[uuid.UUID]context.CancelFunc
+	scanHostKeys       map[uuid.UUID]map[string][]models.Service // SSH services seen by running scans, by host key fingerprint
 	workerID           string
 }
 
@@ -39,6 +47,7 @@ func NewWorker(
 	applicationService *services.ApplicationService,
 	dnsRecordService *services.DNSRecordService,
 	certificateService *services.CertificateService,
+	templateService *services.NucleiTemplateService,
 	workerID string,
 ) *Worker {
 	return &Worker{
@@ -49,7 +58,9 @@ func NewWorker(
 		serviceService:     serviceService,
 		dnsRecordService:   dnsRecordService,
 		certificateService: certificateService,
+		templateService:    templateService,
 		activeScans:        make(map[uuid.UUID]context.CancelFunc),
+		scanHostKeys:       make(map[uuid.UUID]map[string][]models.Service),
 		workerID:           workerID,
 	}
 }
@@ -65,13 +76,8 @@ func (w *Worker) Start() error {
 		return fmt.Errorf("failed to set up cancellation consumer: %w", err)
 	}
 
-	// Setup target consumer
-	err = w.queueService.ConsumeTargets(w.handleNewTarget)
-	if err != nil {
-		return fmt.Errorf("failed to set up target consumer: %w", err)
-	}
-
-	// Setup relation consumer
+	// Setup relation consumer. New targets are left to the API, which enriches them with
+	// ASN, GeoIP and cloud metadata.
 	err = w.queueService.ConsumeTargetRelations(w.handleNewRelation)
 	if err != nil {
 		return fmt.Errorf("failed to set up relation consumer: %w", err)
@@ -123,10 +129,12 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 	// Initialize scanner
 	ctx, cancel := context.WithCancel(context.Background())
 	w.activeScans[request.ScanID] = cancel
+	w.scanHostKeys[request.ScanID] = make(map[string][]models.Service)
 
 	defer func() {
 		cancel()
 		delete(w.activeScans, request.ScanID)
+		delete(w.scanHostKeys, request.ScanID)
 	}()
 
 	err = s.Initialize(ctx)
@@ -144,6 +152,30 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 	var totalServices int
 	startTime := time.Now()
 
+	params := request.Parameters
+	if projectAware, ok := s.(scanner.ProjectAwareScanner); ok && projectAware.UsesProjectDomains() {
+		params = w.withProjectDomains(request)
+	}
+
+	if templateScanner, ok := s.(scanner.CustomTemplateScanner); ok && templateScanner.UsesCustomTemplates() {
+		var cleanup func()
+		params, cleanup, err = w.withCustomTemplates(params)
+		if err != nil {
+			errMsg := fmt.Sprintf("Failed to prepare custom templates: %v", err)
+			log.Printf("[Worker %s] %s", w.workerID, errMsg)
+			w.queueService.UpdateScanStatus(request.ScanID, models.StatusFailed, errMsg)
+			return err
+		}
+		defer cleanup()
+	}
+
+	// CDN edges are shared infrastructure, scanning them wastes time on hosts outside the scope
+	skipCDN, _ := request.Parameters["skip_cdn"].(bool)
+	cdnTargets := make(map[uuid.UUID]bool)
+
+	// Hosts the ping scanner found down are left out of heavy scans when asked to
+	skipDead, _ := request.Parameters["skip_dead"].(bool)
+
 	// First check if we have services specified for scanning
 	if s.SupportsServices() && len(request.Services) > 0 {
 		for i, service := range request.Services {
@@ -153,6 +185,12 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 				return nil
 			}
 
+			if skipCDN && w.isCDNTarget(service.TargetID, cdnTargets) {
+				log.Printf("[Worker %s] Service %s:%d is on a CDN edge, skipping",
+					w.workerID, service.ServiceName, service.Port)
+				continue
+			}
+
 			// Update status
 			statusMsg := fmt.Sprintf("Scanning service %d/%d: %s:%d",
 				i+1, len(request.Services), service.ServiceName, service.Port)
@@ -165,10 +203,17 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 				continue // Skip if scanner doesn't support this service
 			}
 
-			// Run the scan with timeout
-			scanCtx, scanCancel := context.WithTimeout(ctx, 30*time.Minute)
-			results, err := s.Scan(scanCtx, scanTarget, request.Parameters)
+			scanParams := params
+			if endpointAware, ok := s.(scanner.EndpointAwareScanner); ok && endpointAware.UsesCrawledEndpoints(params) {
+				scanParams = w.withCrawledEndpoints(params, w.applicationService.GetByServiceID, service.ID)
+			}
+
+			// Run the scan with timeout, findings reported while it runs are published right away
+			reporter := w.newScanReporter(request.ScanID, service.TargetID, &service.ID, statusMsg)
+			scanCtx, scanCancel := context.WithTimeout(scanner.WithReporter(ctx, reporter), 30*time.Minute)
+			results, err := s.Scan(scanCtx, scanTarget, scanParams)
 			scanCancel()
+			totalFindings += reporter.reported()
 
 			if err != nil {
 				if ctx.Err() == context.Canceled {
@@ -181,7 +226,7 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 			}
 
 			// Process scan results
-			w.processScanResults(results, request.ScanID, service.TargetID, &service.ID)
+			w.processScanResults(results, request.ScanID, service.TargetID, &service.ID, probesServices(s, scanTarget, params))
 
 			totalFindings += len(results.Findings)
 			totalNewTargets += len(results.NewTargets)
@@ -205,6 +250,16 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 			continue
 		}
 
+		if skipCDN && isCDN(target) {
+			log.Printf("[Worker %s] Target %s is on a CDN edge, skipping", w.workerID, target.Value)
+			continue
+		}
+
+		if skipDead && isDead(target) {
+			log.Printf("[Worker %s] Target %s did not answer the last ping scan, skipping", w.workerID, target.Value)
+			continue
+		}
+
 		// Update status
 		statusMsg := fmt.Sprintf("Scanning target %d/%d: %s",
 			i+1, len(request.Targets), target.Value)
@@ -217,10 +272,17 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 			continue // Skip if conversion fails
 		}
 
-		// Run the scan with timeout
-		scanCtx, scanCancel := context.WithTimeout(ctx, 30*time.Minute)
-		results, err := s.Scan(scanCtx, scanTarget, request.Parameters)
+		scanParams := params
+		if endpointAware, ok := s.(scanner.EndpointAwareScanner); ok && endpointAware.UsesCrawledEndpoints(params) {
+			scanParams = w.withCrawledEndpoints(params, w.applicationService.GetByTargetID, target.ID)
+		}
+
+		// Run the scan with timeout, findings reported while it runs are published right away
+		reporter := w.newScanReporter(request.ScanID, target.ID, nil, statusMsg)
+		scanCtx, scanCancel := context.WithTimeout(scanner.WithReporter(ctx, reporter), 30*time.Minute)
+		results, err := s.Scan(scanCtx, scanTarget, scanParams)
 		scanCancel()
+		totalFindings += reporter.reported()
 
 		if err != nil {
 			if ctx.Err() == context.Canceled {
@@ -232,7 +294,7 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 		}
 
 		// Process scan results
-		w.processScanResults(results, request.ScanID, target.ID, nil)
+		w.processScanResults(results, request.ScanID, target.ID, nil, probesServices(s, scanTarget, params))
 
 		totalFindings += len(results.Findings)
 		totalNewTargets += len(results.NewTargets)
@@ -254,8 +316,233 @@ func (w *Worker) handleScanRequest(request services.ScanRequest) error {
 	return nil
 }
 
-// processScanResults handles the results of a scan
-func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUID, targetID uuid.UUID, serviceID *uuid.UUID) {
+// withProjectDomains copies the scan parameters and adds the domain targets of the scanned project
+func (w *Worker) withProjectDomains(request services.ScanRequest) models.JSONB {
+	params := models.JSONB{}
+	for k, v := range request.Parameters {
+		params[k] = v
+	}
+
+	var projectID uuid.UUID
+	if len(request.Targets) > 0 {
+		projectID = request.Targets[0].ProjectID
+	} else if len(request.Services) > 0 {
+		target, err := w.targetService.GetByID(request.Services[0].TargetID)
+		if err != nil {
+			log.Printf("[Worker %s] Failed to look up target of service %s: %v", w.workerID, request.Services[0].ID, err)
+			return params
+		}
+		projectID = target.ProjectID
+	}
+
+	targets, err := w.targetService.GetByType(projectID, models.TargetTypeDomain)
+	if err != nil {
+		log.Printf("[Worker %s] Failed to get domains of project %s: %v", w.workerID, projectID, err)
+		return params
+	}
+
+	domains := make([]interface{}, 0, len(targets))
+	for _, target := range targets {
+		domains = append(domains, target.Value)
+	}
+	params["project_domains"] = domains
+	return params
+}
+
+// withCrawledEndpoints copies the scan parameters and adds the URLs of the GET endpoints the crawler stored
+// on the applications of a target or service, looked up with applications
+func (w *Worker) withCrawledEndpoints(params models.JSONB, applications func(uuid.UUID) ([]models.Application, error), id uuid.UUID) models.JSONB {
+	scanParams := models.JSONB{}
+	for k, v := range params {
+		scanParams[k] = v
+	}
+
+	apps, err := applications(id)
+	if err != nil {
+		log.Printf("[Worker %s] Failed to get applications of %s: %v", w.workerID, id, err)
+		return scanParams
+	}
+
+	seen := make(map[string]bool)
+	endpoints := []interface{}{}
+	for _, app := range apps {
+		if source, _ := app.Metadata["source"].(string); source != "crawler" {
+			continue
+		}
+		stored, _ := app.Metadata["endpoints"].([]interface{})
+		for _, entry := range stored {
+			endpoint, ok := entry.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			url, _ := endpoint["url"].(string)
+			method, _ := endpoint["method"].(string)
+			if url == "" || seen[url] || (method != "" && !strings.EqualFold(method, "GET")) {
+				continue
+			}
+			if len(endpoints) >= maxCrawledEndpoints {
+				break
+			}
+			seen[url] = true
+			endpoints = append(endpoints, url)
+		}
+	}
+
+	scanParams["crawled_endpoints"] = endpoints
+	return scanParams
+}
+
+// withCustomTemplates writes the custom templates selected in the scan parameters to a temporary
+// directory and adds their paths to a copy of the parameters. The returned function removes the directory.
+func (w *Worker) withCustomTemplates(params models.JSONB) (models.JSONB, func(), error) {
+	noop := func() {}
+
+	var templateIDs, tags []string
+	if val, ok := params["custom_templates"].([]interface{}); ok {
+		for _, id := range val {
+			if idStr, ok := id.(string); ok && idStr != "" {
+				templateIDs = append(templateIDs, idStr)
+			}
+		}
+	}
+	if val, ok := params["custom_template_tags"].([]interface{}); ok {
+		for _, tag := range val {
+			if tagStr, ok := tag.(string); ok && tagStr != "" {
+				tags = append(tags, tagStr)
+			}
+		}
+	}
+	if len(templateIDs) == 0 && len(tags) == 0 {
+		return params, noop, nil
+	}
+
+	templates, err := w.templateService.Resolve(templateIDs, tags)
+	if err != nil {
+		return nil, noop, err
+	}
+	if len(templates) == 0 {
+		return nil, noop, fmt.Errorf("no custom templates tagged %s", strings.Join(tags, ", "))
+	}
+
+	dir, err := os.MkdirTemp("", "zecas-templates-*")
+	if err != nil {
+		return nil, noop, err
+	}
+	cleanup := func() {
+		os.RemoveAll(dir)
+	}
+
+	paths := make([]interface{}, 0, len(templates))
+	for _, template := range templates {
+		path := filepath.Join(dir, template.TemplateID+".yaml")
+		if err := os.WriteFile(path, []byte(template.Content), 0600); err != nil {
+			cleanup()
+			return nil, noop, err
+		}
+		paths = append(paths, path)
+	}
+	log.Printf("[Worker %s] Wrote %d custom templates to %s", w.workerID, len(templates), dir)
+
+	result := models.JSONB{}
+	for k, v := range params {
+		result[k] = v
+	}
+	result["custom_template_paths"] = paths
+	return result, cleanup, nil
+}
+
+// isCDNTarget reports whether the target a service runs on is attributed to a CDN edge, caching lookups per scan
+func (w *Worker) isCDNTarget(targetID uuid.UUID, cache map[uuid.UUID]bool) bool {
+	if cdn, ok := cache[targetID]; ok {
+		return cdn
+	}
+
+	target, err := w.targetService.GetByID(targetID)
+	if err != nil {
+		log.Printf("[Worker %s] Failed to look up target %s: %v", w.workerID, targetID, err)
+		return false
+	}
+	cache[targetID] = isCDN(*target)
+	return cache[targetID]
+}
+
+// isCDN reports whether a target was attributed to a CDN edge network
+func isCDN(target models.Target) bool {
+	cdn, _ := target.Metadata["cdn"].(bool)
+	return cdn
+}
+
+// isDead reports whether the ping scanner found a target down, targets never pinged count as alive
+func isDead(target models.Target) bool {
+	alive, ok := target.Metadata["alive"].(bool)
+	return ok && !alive
+}
+
+// scanReporter publishes the findings and progress of a scan of a single target or service while it runs
+type scanReporter struct {
+	worker    *Worker
+	scanID    uuid.UUID
+	targetID  uuid.UUID
+	serviceID *uuid.UUID
+	status    string // Status of the scan the progress is appended to
+
+	mu       sync.Mutex
+	findings int
+}
+
+func (w *Worker) newScanReporter(scanID, targetID uuid.UUID, serviceID *uuid.UUID, status string) *scanReporter {
+	return &scanReporter{
+		worker:    w,
+		scanID:    scanID,
+		targetID:  targetID,
+		serviceID: serviceID,
+		status:    status,
+	}
+}
+
+// ReportFinding queues a finding of the running scan
+func (r *scanReporter) ReportFinding(finding models.Finding) error {
+	finding.ScanID = &r.scanID
+	finding.TargetID = r.targetID
+	if r.serviceID != nil {
+		finding.ServiceID = r.serviceID
+	}
+	scanner.Classify(&finding)
+
+	if err := r.worker.queueService.PublishFinding(finding); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.findings++
+	r.mu.Unlock()
+	return nil
+}
+
+// ReportStatus updates the scan status with the progress of the running scan
+func (r *scanReporter) ReportStatus(message string) {
+	err := r.worker.queueService.UpdateScanStatus(r.scanID, models.StatusRunning, fmt.Sprintf("%s (%s)", r.status, message))
+	if err != nil {
+		log.Printf("[Worker %s] Failed to update scan status: %v", r.worker.workerID, err)
+	}
+}
+
+// reported returns the number of findings published while the scan ran
+func (r *scanReporter) reported() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.findings
+}
+
+// probesServices reports whether a scan identifies the services it finds by probing them
+func probesServices(s scanner.Scanner, target interface{}, params models.JSONB) bool {
+	prober, ok := s.(scanner.ServiceProbingScanner)
+	return ok && prober.ProbesServices(target, params)
+}
+
+// processScanResults handles the results of a scan. Services that are already known are only refreshed
+// when the scanner probed them, so port number guesses don't overwrite what version detection found.
+func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUID, targetID uuid.UUID, serviceID *uuid.UUID, refreshServices bool) {
 	// Get project ID from target
 	target, err := w.targetService.GetByID(targetID)
 	if err != nil {
@@ -282,6 +569,13 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 			targetIDMap[originalID] = existingTarget.ID
 			log.Printf("Target already exists: %s (%s) with ID %s",
 				results.NewTargets[i].Value, results.NewTargets[i].TargetType, existingTarget.ID)
+
+			// Refresh metadata gathered by the scanner, keeping the original discovery information
+			if metadata := scanMetadata(results.NewTargets[i].Metadata); len(metadata) > 0 {
+				if err := w.targetService.UpdateMetadata(existingTarget.ID, metadata); err != nil {
+					log.Printf("Error updating metadata for target %s: %v", existingTarget.ID, err)
+				}
+			}
 		} else {
 			// Queue new target
 			err := w.queueService.PublishTarget(results.NewTargets[i])
@@ -311,8 +605,16 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 		)
 
 		if err == nil {
+			originalID := results.Services[i].ID
 			results.Services[i].ID = existingService.ID
-			w.updateServiceReferences(results, existingService.ID)
+			w.updateServiceReferences(results, originalID, existingService.ID)
+
+			// Refresh version, banner and raw info of the known service
+			if refreshServices {
+				if _, err := w.serviceService.UpsertService(&results.Services[i]); err != nil {
+					log.Printf("Error updating service %s: %v", existingService.ID, err)
+				}
+			}
 		} else {
 			// Queue service
 			err := w.queueService.PublishService(results.Services[i])
@@ -320,6 +622,15 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 				log.Printf("Error publishing service: %v", err)
 			}
 		}
+
+		// Flag every host sharing a host key with the service, including hosts on other targets
+		for _, finding := range w.hostKeyReuseFindings(projectID, scanID, &results.Services[i]) {
+			finding.ScanID = &scanID
+			scanner.Classify(&finding)
+			if err := w.queueService.PublishFinding(finding); err != nil {
+				log.Printf("Error publishing finding: %v", err)
+			}
+		}
 	}
 
 	// Process target relations
@@ -331,7 +642,10 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 			results.TargetRelations[i].SourceID = mappedID
 		}
 
-		if mappedID, exists := targetIDMap[results.TargetRelations[i].DestinationID]; exists {
+		// Likewise for relations pointing at the scanned target, such as hostnames resolving to it
+		if results.TargetRelations[i].DestinationID == uuid.Nil {
+			results.TargetRelations[i].DestinationID = targetID
+		} else if mappedID, exists := targetIDMap[results.TargetRelations[i].DestinationID]; exists {
 			results.TargetRelations[i].DestinationID = mappedID
 		}
 
@@ -363,13 +677,19 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 			results.Applications[i].HostTarget = &targetID
 		}
 
-		// Create the application
+		// Link the application to the scanned service
+		if serviceID != nil && results.Applications[i].ServiceID == nil {
+			results.Applications[i].ServiceID = serviceID
+		}
+
+		// Create the application, or refresh the one found by an earlier scan
 		// TODO: Change below to publish new application on queue instead.
-		err := w.applicationService.Create(&results.Applications[i])
+		storedApp, err := w.applicationService.UpsertApplication(&results.Applications[i])
 		if err != nil {
-			log.Printf("Error creating application: %v", err)
+			log.Printf("Error storing application: %v", err)
 			continue
 		}
+		results.Applications[i].ID = storedApp.ID
 
 		// Update related findings with the application ID
 		for j := range results.Findings {
@@ -386,7 +706,7 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 			}
 		}
 
-		log.Printf("Created application: %s (ID: %s)", results.Applications[i].Name, results.Applications[i].ID)
+		log.Printf("Stored application: %s (ID: %s)", results.Applications[i].Name, results.Applications[i].ID)
 	}
 
 	for i := range results.DNSRecords {
@@ -415,13 +735,18 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 		results.Certificates[i].TargetID = targetID
 		results.Certificates[i].ScanID = &scanID
 
+		// Set service ID if applicable
+		if serviceID != nil {
+			results.Certificates[i].ServiceID = *serviceID
+		}
+
 		err := w.certificateService.Create(&results.Certificates[i])
 		if err != nil {
-			log.Printf("Error creating dnsrecord: %v", err)
+			log.Printf("Error creating certificate: %v", err)
 			continue
 		}
 
-		log.Printf("Created dnsrecord: %s (ID: %s)", results.Certificates[i].Domain, results.Certificates[i].ID)
+		log.Printf("Created certificate: %s (ID: %s)", results.Certificates[i].Domain, results.Certificates[i].ID)
 	}
 
 	// Process findings
@@ -434,6 +759,7 @@ func (w *Worker) processScanResults(results *models.ScanResults, scanID uuid.UUI
 		if serviceID != nil {
 			results.Findings[i].ServiceID = serviceID
 		}
+		scanner.Classify(&results.Findings[i])
 
 		// Queue finding
 		err := w.queueService.PublishFinding(results.Findings[i])
@@ -459,19 +785,6 @@ func (w *Worker) handleCancellation(scanID uuid.UUID) error {
 	return nil
 }
 
-// handleNewTarget processes a new target
-func (w *Worker) handleNewTarget(target models.Target) error {
-	log.Printf("[Worker %s] Received new target: %s (%s)", w.workerID, target.Value, target.TargetType)
-
-	// Create the target
-	_, err := w.targetService.UpsertTarget(&target)
-	if err != nil {
-		return fmt.Errorf("failed to create target: %w", err)
-	}
-
-	return nil
-}
-
 // handleNewRelation processes a new target relation
 func (w *Worker) handleNewRelation(relation models.TargetRelation) error {
 	log.Printf("[Worker %s] Received new relation: %s -> %s (%s)",
@@ -518,13 +831,175 @@ func (w *Worker) updateRelationships(results *models.ScanResults, targetID uuid.
 	}
 }
 
-func (w *Worker) updateServiceReferences(results *models.ScanResults, serviceID uuid.UUID) {
-	// Update findings that reference this service
+// updateServiceReferences points findings, certificates and applications created for a scanned service at the stored service
+func (w *Worker) updateServiceReferences(results *models.ScanResults, originalID uuid.UUID, serviceID uuid.UUID) {
 	for i := range results.Findings {
-		if results.Findings[i].ServiceID != nil && *results.Findings[i].ServiceID == serviceID {
-			// Do nothing - the ID is already correct
+		if results.Findings[i].ServiceID != nil && *results.Findings[i].ServiceID == originalID {
+			id := serviceID
+			results.Findings[i].ServiceID = &id
+		}
+	}
+
+	for i := range results.Certificates {
+		if results.Certificates[i].ServiceID == originalID {
+			results.Certificates[i].ServiceID = serviceID
+		}
+	}
+
+	for i := range results.Applications {
+		if results.Applications[i].ServiceID != nil && *results.Applications[i].ServiceID == originalID {
+			id := serviceID
+			results.Applications[i].ServiceID = &id
+		}
+	}
+}
+
+// hostKeyReuseFindings reports every host presenting a host key of the service together with another
+// host of the project, since anyone holding the key can impersonate all of them. Services found earlier in
+// the same scan are matched as well, because new services are only stored once the API consumed them.
+func (w *Worker) hostKeyReuseFindings(projectID uuid.UUID, scanID uuid.UUID, service *models.Service) []models.Finding {
+	fingerprints := stringValues(service.RawInfo["host_key_fingerprints"])
+	if len(fingerprints) == 0 {
+		return nil
+	}
+
+	// Collect the services presenting any of the keys, with the keys each of them presents
+	members := []models.Service{*service}
+	keys := map[uuid.UUID][]string{service.ID: fingerprints}
+	seen := w.scanHostKeys[scanID]
+	for _, fingerprint := range fingerprints {
+		others, err := w.serviceService.FindByHostKeyFingerprint(projectID, fingerprint)
+		if err != nil {
+			log.Printf("Error looking up host key %s: %v", fingerprint, err)
+		}
+		if seen != nil {
+			others = append(others, seen[fingerprint]...)
+			seen[fingerprint] = append(seen[fingerprint], *service)
+		}
+
+		for _, other := range others {
+			if other.ID == service.ID {
+				continue
+			}
+			if _, exists := keys[other.ID]; !exists {
+				members = append(members, other)
+				keys[other.ID] = stringValues(other.RawInfo["host_key_fingerprints"])
+			}
+		}
+	}
+
+	var findings []models.Finding
+	for _, member := range members {
+		var reusedBy []map[string]interface{}
+		hosts := make(map[uuid.UUID]bool)
+		for _, other := range members {
+			if sameSSHHost(member, other) {
+				continue
+			}
+
+			otherIP, _ := other.RawInfo["ip_address"].(string)
+			otherHost, _ := other.RawInfo["target_value"].(string)
+			for _, fingerprint := range keys[other.ID] {
+				if !containsString(keys[member.ID], fingerprint) {
+					continue
+				}
+				hosts[other.TargetID] = true
+				reusedBy = append(reusedBy, map[string]interface{}{
+					"fingerprint": fingerprint,
+					"target_id":   other.TargetID,
+					"service_id":  other.ID,
+					"host":        otherHost,
+					"port":        other.Port,
+					"ip_address":  otherIP,
+				})
+			}
+		}
+
+		if len(reusedBy) > 0 {
+			findings = append(findings, hostKeyReuseFinding(member, keys[member.ID], reusedBy, len(hosts)))
+		}
+	}
+
+	return findings
+}
+
+// hostKeyReuseFinding creates the finding for a service presenting host keys also presented by other hosts
+func hostKeyReuseFinding(service models.Service, fingerprints []string, reusedBy []map[string]interface{}, hosts int) models.Finding {
+	host, _ := service.RawInfo["target_value"].(string)
+
+	var lines []string
+	for _, reuse := range reusedBy {
+		lines = append(lines, fmt.Sprintf("- %v:%v (%v)", reuse["host"], reuse["port"], reuse["fingerprint"]))
+	}
+
+	serviceID := service.ID
+	return models.Finding{
+		ID:        uuid.New(),
+		TargetID:  service.TargetID,
+		ServiceID: &serviceID,
+		Title:     fmt.Sprintf("SSH host key shared with %d other hosts", hosts),
+		Description: fmt.Sprintf("%s:%d presents host keys also presented by:\n%s\nHosts sharing a private key can impersonate each other, often because they were cloned from the same image.",
+			host, service.Port, strings.Join(lines, "\n")),
+		Severity:    models.SeverityMedium,
+		FindingType: "ssh_host_key_reuse",
+		Details: models.JSONB{
+			"host":          host,
+			"port":          service.Port,
+			"fingerprints":  fingerprints,
+			"reused_by":     reusedBy,
+			"discovered_at": time.Now().Format(time.RFC3339),
+		},
+	}
+}
+
+// sameSSHHost reports whether two services are on the same host, such as a domain and its IP address
+func sameSSHHost(a models.Service, b models.Service) bool {
+	if a.ID == b.ID || a.TargetID == b.TargetID {
+		return true
+	}
+	ipA, _ := a.RawInfo["ip_address"].(string)
+	ipB, _ := b.RawInfo["ip_address"].(string)
+	return ipA != "" && ipA == ipB
+}
+
+// stringValues converts a string list stored in raw info, which is []interface{} once read back from the database
+func stringValues(value interface{}) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []interface{}:
+		var values []string
+		for _, item := range v {
+			if s, ok := item.(string); ok && s != "" {
+				values = append(values, s)
+			}
+		}
+		return values
+	}
+	return nil
+}
+
+// containsString reports whether a list contains a value
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// scanMetadata returns the metadata of a discovered target without the discovery bookkeeping keys
+func scanMetadata(metadata models.JSONB) models.JSONB {
+	result := models.JSONB{}
+	for k, v := range metadata {
+		switch k {
+		case "discovered_from", "discovery_scan", "discovered_at":
+			continue
 		}
+		result[k] = v
 	}
+	return result
 }
 
 // shouldAssociateWithApp determines if a finding should be associated with an application
@@ -45,7 +45,18 @@ const SCANNER_TYPES: ScannerType[] = [
     "subdomain",
     "nuclei",
     "testSSL",
-    "httpx"
+    "httpx",
+    "portscan",
+    "takeover",
+    "tls",
+    "ping",
+    "command",
+    "crawler",
+    "contentdiscovery",
+    "banner",
+    "ssh",
+    "smtp",
+    "vhost"
 ]
 
 const scanConfigFormSchema = z.object({
@@ -11,10 +11,20 @@ export const FindingSchema = z.object({
     description: z.string().optional(),
     severity: z.string(),
     finding_type: z.string(),
+    fingerprint: z.string().optional(),
     details: z.string().optional(),
     verified: z.boolean().optional(),
     fixed: z.boolean().optional(),
-    manual: z.boolean().optional()
+    manual: z.boolean().optional(),
+    cve_ids: z.array(z.string()).optional(),
+    cwe_ids: z.array(z.string()).optional(),
+    cvss_version: z.string().optional(),
+    cvss_vector: z.string().optional(),
+    cvss_score: z.number().optional(),
+    references: z.array(z.string()).optional(),
+    epss_score: z.number().optional(),
+    epss_percentile: z.number().optional(),
+    known_exploited: z.boolean().optional()
 });
 
 export type Finding = z.infer<typeof FindingSchema>;
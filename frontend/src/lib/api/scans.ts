@@ -18,7 +18,18 @@ export type ScannerType =
     | "subdomain"
     | "nuclei"
     | "httpx"
-    | "testSSL";
+    | "testSSL"
+    | "portscan"
+    | "takeover"
+    | "tls"
+    | "ping"
+    | "command"
+    | "crawler"
+    | "contentdiscovery"
+    | "banner"
+    | "ssh"
+    | "smtp"
+    | "vhost";
 
 export const ScannerTypeEnum = z.enum([
     "nmap",
@@ -27,6 +38,17 @@ export const ScannerTypeEnum = z.enum([
     "nuclei",
     "httpx",
     "testSSL",
+    "portscan",
+    "takeover",
+    "tls",
+    "ping",
+    "command",
+    "crawler",
+    "contentdiscovery",
+    "banner",
+    "ssh",
+    "smtp",
+    "vhost",
 ]);
 
 const NmapParametersSchema = z.object({
@@ -46,23 +68,90 @@ const NmapParametersSchema = z.object({
             message: "Parameter timing can only be a number between 0-5",
         })
         .optional(),
+    scripts: z
+        .array(
+            z.string({
+                message: "Parameter scripts needs to be a list of strings",
+            })
+        )
+        .optional(),
+    script_args: z
+        .string({ message: "Parameter script_args needs to be a valid string" })
+        .optional(),
+    os_detection: z
+        .boolean({
+            message: "Parameter os_detection needs to be either true or false",
+        })
+        .optional(),
+    version_intensity: z
+        .enum(["0", "1", "2", "3", "4", "5", "6", "7", "8", "9"], {
+            message: "Parameter version_intensity can only be a number between 0-9",
+        })
+        .optional(),
+    skip_cdn: z
+        .boolean({ message: "Parameter skip_cdn needs to be either true or false" })
+        .optional(),
+    skip_dead: z
+        .boolean({ message: "Parameter skip_dead needs to be either true or false" })
+        .optional(),
 });
 
-const allowedRecordTypes = ["A", "AAAA", "CNAME", "MX", "TXT", "NS"] as const;
+const allowedRecordTypes = [
+    "A",
+    "AAAA",
+    "CNAME",
+    "MX",
+    "TXT",
+    "NS",
+    "SOA",
+    "SRV",
+    "CAA",
+    "DS",
+    "DNSKEY",
+] as const;
 const DNSParametersSchema = z.object({
     record_types: z
         .array(
             z.enum(allowedRecordTypes, {
-    rate_limit: z.number({message: "Parameter rate_limit needs to be a valid number"}).optional(),
-    bulk_size: z.number({message: "Parameter bulk_size needs to be a valid number"}).optional(),
-    templates_dir: z.string({message: "Parameter templates_dir needs to be a valid string"}).optional(),
-    headless: z.boolean({message: "Parameter headless needs to be either true or false"}).optional(),
-    include_all: z.boolean({message: "Parameter include_all needs to be either true or false"}).optional(),
                 message:
                     "Parameter record_types needs to be an array of valid DNS records",
             })
         )
         .optional(),
+    nameservers: z
+        .array(
+            z.string({
+                message: "Parameter nameservers needs to be a list of strings",
+            })
+        )
+        .optional(),
+    srv_names: z
+        .array(
+            z.string({
+                message: "Parameter srv_names needs to be a list of strings",
+            })
+        )
+        .optional(),
+    zone_transfer: z
+        .boolean({
+            message: "Parameter zone_transfer needs to be either true or false",
+        })
+        .optional(),
+    email_security: z
+        .boolean({
+            message: "Parameter email_security needs to be either true or false",
+        })
+        .optional(),
+    dkim_selectors: z
+        .array(
+            z.string({
+                message: "Parameter dkim_selectors needs to be a list of strings",
+            })
+        )
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
 });
 
 const SubdomainParametersSchema = z.object({
@@ -107,7 +196,10 @@ const NucleiParametersSchema = z.object({
         )
         .optional(),
     severity: z
-        .string({ message: "Parameter severity needs to be a valid string" })
+        .union([z.string(), z.array(z.string())], {
+            message:
+                "Parameter severity needs to be a valid string or a list of strings",
+        })
         .optional(),
     timeout: z
         .number({ message: "Parameter timeout needs to be a valid number" })
@@ -129,6 +221,31 @@ const NucleiParametersSchema = z.object({
             message: "Parameter include_all needs to be either true or false",
         })
         .optional(),
+    skip_cdn: z
+        .boolean({ message: "Parameter skip_cdn needs to be either true or false" })
+        .optional(),
+    skip_dead: z
+        .boolean({ message: "Parameter skip_dead needs to be either true or false" })
+        .optional(),
+    use_crawled_endpoints: z
+        .boolean({
+            message: "Parameter use_crawled_endpoints needs to be either true or false",
+        })
+        .optional(),
+    custom_templates: z
+        .array(
+            z.string({
+                message: "Parameter custom_templates needs to be a list of strings",
+            })
+        )
+        .optional(),
+    custom_template_tags: z
+        .array(
+            z.string({
+                message: "Parameter custom_template_tags needs to be a list of strings",
+            })
+        )
+        .optional(),
 });
 
 const TestSSLParametersSchema = z.object({
@@ -205,6 +322,344 @@ const HttpxParametersSchema = z.object({
         .optional(),
 });
 
+const PortscanParametersSchema = z.object({
+    port_range: z
+        .string({ message: "Parameter port_range needs to be a valid string" })
+        .optional(),
+    udp_port_range: z
+        .string({ message: "Parameter udp_port_range needs to be a valid string" })
+        .optional(),
+    concurrency: z
+        .number({ message: "Parameter concurrency needs to be a valid number" })
+        .optional(),
+    rate: z
+        .number({ message: "Parameter rate needs to be a valid number" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    grab_banner: z
+        .boolean({ message: "Parameter grab_banner needs to be either true or false" })
+        .optional(),
+    skip_cdn: z
+        .boolean({ message: "Parameter skip_cdn needs to be either true or false" })
+        .optional(),
+    skip_dead: z
+        .boolean({ message: "Parameter skip_dead needs to be either true or false" })
+        .optional(),
+});
+
+const TakeoverParametersSchema = z.object({
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    check_http: z
+        .boolean({ message: "Parameter check_http needs to be either true or false" })
+        .optional(),
+    nameservers: z
+        .array(
+            z.string({
+                message: "Parameter nameservers needs to be a list of strings",
+            })
+        )
+        .optional(),
+    fingerprints_file: z
+        .string({ message: "Parameter fingerprints_file needs to be a valid string" })
+        .optional(),
+});
+
+const TLSParametersSchema = z.object({
+    ports: z
+        .string({ message: "Parameter ports needs to be a valid string" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    enumerate_ciphers: z
+        .boolean({
+            message: "Parameter enumerate_ciphers needs to be either true or false",
+        })
+        .optional(),
+    expiry_warning_days: z
+        .number({
+            message: "Parameter expiry_warning_days needs to be a valid number",
+        })
+        .optional(),
+    server_name: z
+        .string({ message: "Parameter server_name needs to be a valid string" })
+        .optional(),
+});
+
+const PingParametersSchema = z.object({
+    method: z
+        .enum(["auto", "icmp", "tcp"], {
+            message: "Parameter method can only be auto, icmp or tcp",
+        })
+        .optional(),
+    count: z
+        .number({ message: "Parameter count needs to be a valid number" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    concurrency: z
+        .number({ message: "Parameter concurrency needs to be a valid number" })
+        .optional(),
+    tcp_ports: z
+        .string({ message: "Parameter tcp_ports needs to be a valid string" })
+        .optional(),
+});
+
+const CommandParametersSchema = z.object({
+    command: z
+        .string({ message: "Parameter command needs to be a valid string" })
+        .optional(),
+    args: z
+        .array(
+            z.string({ message: "Parameter args needs to be a list of strings" }),
+        )
+        .optional(),
+    output_format: z
+        .enum(["json", "jsonl", "xml", "lines"], {
+            message: "Parameter output_format can only be json, jsonl, xml or lines",
+        })
+        .optional(),
+    records_path: z
+        .string({ message: "Parameter records_path needs to be a valid string" })
+        .optional(),
+    line_pattern: z
+        .string({ message: "Parameter line_pattern needs to be a valid string" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    mapping: z.object(
+        {
+            findings: z.any().optional(),
+            services: z.any().optional(),
+            targets: z.any().optional(),
+            dns_records: z.any().optional(),
+        },
+        { message: "Parameter mapping needs to be an object" },
+    ),
+});
+
+const CrawlerParametersSchema = z.object({
+    max_depth: z
+        .number({ message: "Parameter max_depth needs to be a valid number" })
+        .optional(),
+    max_pages: z
+        .number({ message: "Parameter max_pages needs to be a valid number" })
+        .optional(),
+    rate_limit: z
+        .number({ message: "Parameter rate_limit needs to be a valid number" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    user_agent: z
+        .string({ message: "Parameter user_agent needs to be a valid string" })
+        .optional(),
+    include_subdomains: z
+        .boolean({ message: "Parameter include_subdomains needs to be a boolean" })
+        .optional(),
+    robots: z
+        .boolean({ message: "Parameter robots needs to be a boolean" })
+        .optional(),
+    sitemap: z
+        .boolean({ message: "Parameter sitemap needs to be a boolean" })
+        .optional(),
+    parse_scripts: z
+        .boolean({ message: "Parameter parse_scripts needs to be a boolean" })
+        .optional(),
+    exclude: z
+        .array(
+            z.string({ message: "Parameter exclude needs to be a list of strings" }),
+        )
+        .optional(),
+});
+
+const ContentDiscoveryParametersSchema = z.object({
+    url: z
+        .string({ message: "Parameter url needs to be a valid string" })
+        .url({ message: "Parameter url needs to be a valid URL" })
+        .optional(),
+    wordlist: z
+        .string({ message: "Parameter wordlist needs to be a valid string" })
+        .optional(),
+    words: z
+        .array(
+            z.string({ message: "Parameter words needs to be a list of strings" }),
+        )
+        .optional(),
+    extensions: z
+        .array(
+            z.string({ message: "Parameter extensions needs to be a list of strings" }),
+        )
+        .optional(),
+    match_status: z
+        .array(
+            z.number({ message: "Parameter match_status needs to be a list of numbers" }),
+        )
+        .optional(),
+    filter_status: z
+        .array(
+            z.number({ message: "Parameter filter_status needs to be a list of numbers" }),
+        )
+        .optional(),
+    filter_size: z
+        .array(
+            z.number({ message: "Parameter filter_size needs to be a list of numbers" }),
+        )
+        .optional(),
+    filter_words: z
+        .array(
+            z.number({ message: "Parameter filter_words needs to be a list of numbers" }),
+        )
+        .optional(),
+    auto_calibrate: z
+        .boolean({ message: "Parameter auto_calibrate needs to be a boolean" })
+        .optional(),
+    concurrency: z
+        .number({ message: "Parameter concurrency needs to be a valid number" })
+        .optional(),
+    rate_limit: z
+        .number({ message: "Parameter rate_limit needs to be a valid number" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    user_agent: z
+        .string({ message: "Parameter user_agent needs to be a valid string" })
+        .optional(),
+});
+
+const BannerParametersSchema = z.object({
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    protocol: z
+        .enum(
+            ["ssh", "ftp", "smtp", "pop3", "imap", "mysql", "postgresql", "redis", "rdp"],
+            { message: "Parameter protocol needs to be a supported protocol" },
+        )
+        .optional(),
+    tls: z
+        .boolean({ message: "Parameter tls needs to be a boolean" })
+        .optional(),
+});
+
+const SSHParametersSchema = z.object({
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    port: z
+        .number({ message: "Parameter port needs to be a valid number" })
+        .optional(),
+    username: z
+        .string({ message: "Parameter username needs to be a string" })
+        .optional(),
+    auth_methods: z
+        .boolean({ message: "Parameter auth_methods needs to be a boolean" })
+        .optional(),
+});
+
+const SMTPParametersSchema = z.object({
+    ports: z
+        .string({ message: "Parameter ports needs to be a string" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    helo_name: z
+        .string({ message: "Parameter helo_name needs to be a string" })
+        .optional(),
+    domain: z
+        .string({ message: "Parameter domain needs to be a string" })
+        .optional(),
+    relay_from: z
+        .string({ message: "Parameter relay_from needs to be a string" })
+        .optional(),
+    relay_to: z
+        .string({ message: "Parameter relay_to needs to be a string" })
+        .optional(),
+    users: z
+        .array(
+            z.string({
+                message: "Parameter users needs to be a list of strings",
+            })
+        )
+        .optional(),
+    check_tls: z
+        .boolean({ message: "Parameter check_tls needs to be a boolean" })
+        .optional(),
+    check_relay: z
+        .boolean({ message: "Parameter check_relay needs to be a boolean" })
+        .optional(),
+    check_enumeration: z
+        .boolean({ message: "Parameter check_enumeration needs to be a boolean" })
+        .optional(),
+    expiry_warning_days: z
+        .number({ message: "Parameter expiry_warning_days needs to be a valid number" })
+        .optional(),
+});
+
+const VHostParametersSchema = z.object({
+    url: z
+        .string({ message: "Parameter url needs to be a valid string" })
+        .optional(),
+    wordlist: z
+        .string({ message: "Parameter wordlist needs to be a valid string" })
+        .optional(),
+    words: z
+        .array(
+            z.string({
+                message: "Parameter words needs to be a list of strings",
+            })
+        )
+        .optional(),
+    domains: z
+        .array(
+            z.string({
+                message: "Parameter domains needs to be a list of strings",
+            })
+        )
+        .optional(),
+    hosts: z
+        .array(
+            z.string({
+                message: "Parameter hosts needs to be a list of strings",
+            })
+        )
+        .optional(),
+    include_project_domains: z
+        .boolean({
+            message:
+                "Parameter include_project_domains needs to be either true or false",
+        })
+        .optional(),
+    filter_status: z
+        .array(
+            z.number({ message: "Parameter filter_status needs to be a list of numbers" }),
+        )
+        .optional(),
+    max_candidates: z
+        .number({ message: "Parameter max_candidates needs to be a valid number" })
+        .optional(),
+    concurrency: z
+        .number({ message: "Parameter concurrency needs to be a valid number" })
+        .optional(),
+    timeout: z
+        .number({ message: "Parameter timeout needs to be a valid number" })
+        .optional(),
+    user_agent: z
+        .string({ message: "Parameter user_agent needs to be a valid string" })
+        .optional(),
+    skip_cdn: z
+        .boolean({ message: "Parameter skip_cdn needs to be either true or false" })
+        .optional(),
+});
+
 const ScanConfigBaseSchema = z.object({
     id: z.string().optional(),
     name: z.string(),
@@ -236,9 +691,69 @@ export const ScanConfigSchema = z.discriminatedUnion("scanner_type", [
         scanner_type: z.literal("httpx"),
         parameters: HttpxParametersSchema,
     }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("portscan"),
+        parameters: PortscanParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("takeover"),
+        parameters: TakeoverParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("tls"),
+        parameters: TLSParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("ping"),
+        parameters: PingParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("command"),
+        parameters: CommandParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("crawler"),
+        parameters: CrawlerParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("contentdiscovery"),
+        parameters: ContentDiscoveryParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("banner"),
+        parameters: BannerParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("ssh"),
+        parameters: SSHParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("smtp"),
+        parameters: SMTPParametersSchema,
+    }),
+    ScanConfigBaseSchema.extend({
+        scanner_type: z.literal("vhost"),
+        parameters: VHostParametersSchema,
+    }),
 ]);
 export type ScanConfig = z.infer<typeof ScanConfigSchema>;
 
+export type ScannerInfo = {
+    type: string;
+    target_types: string[];
+    supports_services: boolean;
+    plugin: boolean;
+    parameters_schema: Record<string, unknown>;
+};
+
+export const getScanners = async (access_token: string) => {
+    return await callAPI("/api/v1/scanners", {
+        method: "GET",
+        expected_status: 200,
+        access_token,
+    });
+};
+
 export const getScanConfigs = async (access_token: string) => {
     return await callAPI("/api/v1/scan-configs", {
         method: "GET",
@@ -313,12 +828,14 @@ export const startNewScan = async (
     projectId: string,
     scanConfigId: string,
     targetIds: string[],
-    access_token: string
+    access_token: string,
+    serviceIds: string[] = []
 ) => {
     const body = {
         project_id: projectId,
         scan_config_id: scanConfigId,
         target_ids: targetIds,
+        service_ids: serviceIds,
     };
     return await callAPI("/api/v1/scans", {
         method: "POST",